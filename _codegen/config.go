@@ -49,6 +49,12 @@ type (
 		// GenericsFunctions specify which functions you need in the whole
 		// package
 		GenericsFunctions tpl.Generics
+		// JSONTagsWhiteList config option as a SQL query to select all tables
+		// whose struct fields should contain the column name in the json
+		// struct tag, e.g. json:"entity_id,omitempty". Same rules as for
+		// GenericsWhiteList apply: empty disables it and the word SQLQuery
+		// enables it for all tables.
+		JSONTagsWhiteList string
 	}
 
 	// AttributeToStructMap contains as key the name of the EAV entity and points to
@@ -339,6 +345,24 @@ var ConfigMaterializationEntityType = TableToStruct{
 	OutputFile: BasePath.AppendDir("testgen", "generated_entity_type_test"),
 }
 
+// ConfigMaterializationAttributeSets configuration for
+// materializeAttributeSets() to write the materialized attribute sets of all
+// entity types in ConfigEntityType into a file. Other fields of the struct
+// TableToStruct are ignored.
+var ConfigMaterializationAttributeSets = TableToStruct{
+	Package:    "testgen",
+	OutputFile: BasePath.AppendDir("testgen", "generated_attribute_set_test"),
+}
+
+// ConfigMaterializationAttributeGroups configuration for
+// materializeAttributeGroups() to write the materialized attribute groups of
+// all entity types in ConfigEntityType into a file. Other fields of the
+// struct TableToStruct are ignored.
+var ConfigMaterializationAttributeGroups = TableToStruct{
+	Package:    "testgen",
+	OutputFile: BasePath.AppendDir("testgen", "generated_attribute_group_test"),
+}
+
 // ConfigLocalization temporary integration because missing feature in https://github.com/golang/text
 var ConfigLocalization = struct {
 	Package       string
@@ -26,8 +26,10 @@ import (
 )
 
 const (
-	TableNameSeparator string = "_"
-	TableEavEntityType string = "eav_entity_type"
+	TableNameSeparator     string = "_"
+	TableEavEntityType     string = "eav_entity_type"
+	TableEavAttributeSet   string = "eav_attribute_set"
+	TableEavAttributeGroup string = "eav_attribute_group"
 )
 
 var (
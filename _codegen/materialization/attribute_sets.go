@@ -0,0 +1,120 @@
+// Copyright 2015-2017, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"text/template"
+
+	"github.com/corestoreio/csfw/codegen"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/errors"
+)
+
+// materializeAttributeSets writes the data from eav_attribute_set into a Go
+// file. Only the sets of the entity types in codegen.ConfigEntityType are
+// written.
+func materializeAttributeSets(ctx *context) {
+	defer ctx.wg.Done()
+	codegen.LogFatal(writeAttributeSets(
+		ctx.dbc,
+		codegen.ConfigMaterializationAttributeSets.Package,
+		codegen.ConfigMaterializationAttributeSets.OutputFile.String(),
+	))
+}
+
+// materializeAttributeGroups writes the data from eav_attribute_group into a
+// Go file. Only the groups of the entity types in codegen.ConfigEntityType
+// are written.
+func materializeAttributeGroups(ctx *context) {
+	defer ctx.wg.Done()
+	codegen.LogFatal(writeAttributeGroups(
+		ctx.dbc,
+		codegen.ConfigMaterializationAttributeGroups.Package,
+		codegen.ConfigMaterializationAttributeGroups.OutputFile.String(),
+	))
+}
+
+// writeAttributeSets loads all attribute sets joined with their entity type
+// and writes the generated code for package pkg into outputFile.
+func writeAttributeSets(dbc *dbr.Connection, pkg, outputFile string) error {
+	sel := dbc.NewSession().
+		Select("eas.attribute_set_id", "eas.entity_type_id", "eas.attribute_set_name", "eas.sort_order").
+		From(codegen.TablePrefix+codegen.TableEavAttributeSet, "eas").
+		Join(
+			dbr.JoinTable(codegen.TablePrefix+codegen.TableEavEntityType, "eet"),
+			dbr.JoinColumns("eet.entity_type_code"),
+			dbr.ConditionRaw("eet.entity_type_id = eas.entity_type_id"),
+		).
+		Where(dbr.ConditionRawExpand("eet.entity_type_code IN ?", codegen.ConfigEntityType.Keys())).
+		OrderBy("eas.entity_type_id").
+		OrderBy("eas.sort_order").
+		OrderBy("eas.attribute_set_id")
+	return writeStringEntities(dbc, sel, pkg, outputFile, tplAttrSets)
+}
+
+// writeAttributeGroups loads all attribute groups joined with their attribute
+// set and entity type and writes the generated code for package pkg into
+// outputFile.
+func writeAttributeGroups(dbc *dbr.Connection, pkg, outputFile string) error {
+	sel := dbc.NewSession().
+		Select("eag.attribute_group_id", "eag.attribute_set_id", "eag.attribute_group_name", "eag.sort_order", "eag.default_id").
+		From(codegen.TablePrefix+codegen.TableEavAttributeGroup, "eag").
+		Join(
+			dbr.JoinTable(codegen.TablePrefix+codegen.TableEavAttributeSet, "eas"),
+			dbr.JoinColumns("eas.entity_type_id"),
+			dbr.ConditionRaw("eas.attribute_set_id = eag.attribute_set_id"),
+		).
+		Join(
+			dbr.JoinTable(codegen.TablePrefix+codegen.TableEavEntityType, "eet"),
+			dbr.JoinColumns("eet.entity_type_code"),
+			dbr.ConditionRaw("eet.entity_type_id = eas.entity_type_id"),
+		).
+		Where(dbr.ConditionRawExpand("eet.entity_type_code IN ?", codegen.ConfigEntityType.Keys())).
+		OrderBy("eag.attribute_set_id").
+		OrderBy("eag.sort_order").
+		OrderBy("eag.attribute_group_id")
+	return writeStringEntities(dbc, sel, pkg, outputFile, tplAttrGroups)
+}
+
+func writeStringEntities(dbc *dbr.Connection, sel *dbr.Select, pkg, outputFile, tpl string) error {
+	rows, err := codegen.LoadStringEntities(dbc.DB, sel)
+	if err != nil {
+		return errors.Wrap(err, "[materialization] LoadStringEntities")
+	}
+
+	data := struct {
+		Package string
+		Rows    []codegen.StringEntities
+	}{
+		Package: pkg,
+		Rows:    rows,
+	}
+	addFM := template.FuncMap{
+		// intOrZero prints NULL columns as zero
+		"intOrZero": func(s string) string {
+			if s == "" {
+				return "0"
+			}
+			return s
+		},
+	}
+
+	code, err := codegen.GenerateCode(pkg, tpl, data, addFM)
+	if err != nil {
+		return errors.Wrapf(err, "[materialization] GenerateCode\n%s", code)
+	}
+	return errors.Wrap(ioutil.WriteFile(outputFile, code, 0600), "[materialization] WriteFile")
+}
@@ -0,0 +1,58 @@
+// Copyright 2015-2017, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAttributeSetsAndGroups(t *testing.T) {
+	dbc, _ := cstesting.MustConnectDB()
+	if dbc == nil {
+		t.Skip("Environment DB DSN not found")
+	}
+	defer func() { require.NoError(t, dbc.Close()) }()
+
+	dir, err := ioutil.TempDir("", "materialization")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	setsFile := filepath.Join(dir, "attribute_set.go")
+	groupsFile := filepath.Join(dir, "attribute_group.go")
+	require.NoError(t, writeAttributeSets(dbc, "testgen", setsFile))
+	require.NoError(t, writeAttributeGroups(dbc, "testgen", groupsFile))
+
+	// both files form one package and must type check together.
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, fn := range []string{setsFile, groupsFile} {
+		f, err := parser.ParseFile(fset, fn, nil, parser.AllErrors)
+		require.NoError(t, err, "File %q", fn)
+		files = append(files, f)
+	}
+	conf := types.Config{}
+	_, err = conf.Check("testgen", fset, files, nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,119 @@
+// Copyright 2015-2017, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+const tplAttrSets = `package {{ .Package }}
+
+// Auto generated via materialization
+
+// AttributeSet represents a row from table eav_attribute_set.
+type AttributeSet struct {
+	AttributeSetID   int64
+	EntityTypeID     int64
+	EntityTypeCode   string
+	AttributeSetName string
+	SortOrder        int64
+}
+
+// AttributeSetSlice a collection of attribute sets.
+type AttributeSetSlice []AttributeSet
+
+// ByID returns the attribute set with the ID or false if not found.
+func (s AttributeSetSlice) ByID(id int64) (AttributeSet, bool) {
+	for _, as := range s {
+		if as.AttributeSetID == id {
+			return as, true
+		}
+	}
+	return AttributeSet{}, false
+}
+
+// ByEntityTypeCode returns all attribute sets of an entity type.
+func (s AttributeSetSlice) ByEntityTypeCode(code string) AttributeSetSlice {
+	var ret AttributeSetSlice
+	for _, as := range s {
+		if as.EntityTypeCode == code {
+			ret = append(ret, as)
+		}
+	}
+	return ret
+}
+
+// AttributeSets contains all materialized attribute sets.
+var AttributeSets = AttributeSetSlice{
+	{{ range .Rows }}{
+		AttributeSetID:   {{ index . "attribute_set_id" }},
+		EntityTypeID:     {{ index . "entity_type_id" }},
+		EntityTypeCode:   {{ index . "entity_type_code" | printf "%q" }},
+		AttributeSetName: {{ index . "attribute_set_name" | printf "%q" }},
+		SortOrder:        {{ index . "sort_order" }},
+	},
+	{{ end }}
+}
+`
+
+const tplAttrGroups = `package {{ .Package }}
+
+// Auto generated via materialization
+
+// AttributeGroup represents a row from table eav_attribute_group.
+type AttributeGroup struct {
+	AttributeGroupID   int64
+	AttributeSetID     int64
+	EntityTypeID       int64
+	EntityTypeCode     string
+	AttributeGroupName string
+	SortOrder          int64
+	DefaultID          int64
+}
+
+// AttributeGroupSlice a collection of attribute groups.
+type AttributeGroupSlice []AttributeGroup
+
+// ByID returns the attribute group with the ID or false if not found.
+func (s AttributeGroupSlice) ByID(id int64) (AttributeGroup, bool) {
+	for _, ag := range s {
+		if ag.AttributeGroupID == id {
+			return ag, true
+		}
+	}
+	return AttributeGroup{}, false
+}
+
+// ByAttributeSetID returns all groups of an attribute set.
+func (s AttributeGroupSlice) ByAttributeSetID(id int64) AttributeGroupSlice {
+	var ret AttributeGroupSlice
+	for _, ag := range s {
+		if ag.AttributeSetID == id {
+			ret = append(ret, ag)
+		}
+	}
+	return ret
+}
+
+// AttributeGroups contains all materialized attribute groups.
+var AttributeGroups = AttributeGroupSlice{
+	{{ range .Rows }}{
+		AttributeGroupID:   {{ index . "attribute_group_id" }},
+		AttributeSetID:     {{ index . "attribute_set_id" }},
+		EntityTypeID:       {{ index . "entity_type_id" }},
+		EntityTypeCode:     {{ index . "entity_type_code" | printf "%q" }},
+		AttributeGroupName: {{ index . "attribute_group_name" | printf "%q" }},
+		SortOrder:          {{ index . "sort_order" }},
+		DefaultID:          {{ index . "default_id" | intOrZero }},
+	},
+	{{ end }}
+}
+`
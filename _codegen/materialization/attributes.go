@@ -278,7 +278,7 @@ func stripCoreAttributeColumns(cols codegen.Columns) codegen.Columns {
 //		return err
 //	}
 
-// @todo ValidateRules field must be converted from PHP serialized string to JSON
+// @todo ValidateRules field must be converted from PHP serialized string to JSON, use eav.PHPUnserializeToJSON
 //	pkg := getPackage(ctx.et)
 
 //	data := map[string]interface{}{
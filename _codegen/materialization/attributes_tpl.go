@@ -16,7 +16,8 @@ package main
 
 /* @todo
    Data will be "carved in stone" because it only changes during development.
-   - attribute_set related tables: eav_attribute_set, eav_entity_attribute, eav_attribute_group, etc
+   - DONE: eav_attribute_set and eav_attribute_group, see attribute_sets_tpl.go
+   - attribute_set related tables: eav_entity_attribute, etc
    - label and option tables will not be hard coded
 */
 const tplAttrImport = `
@@ -75,12 +75,11 @@ func main() {
 	ctx.wg.Add(1)
 	go materializeAttributes(ctx)
 
-	// EAV -> Create queries for AttributeSets and AttributeGroups
-	//    ctx.wg.Add(1)
-	//    go materializeAttributeSets(ctx)
-	//
-	//    ctx.wg.Add(1)
-	//    go materializeAttributeGroups(ctx)
+	ctx.wg.Add(1)
+	go materializeAttributeSets(ctx)
+
+	ctx.wg.Add(1)
+	go materializeAttributeGroups(ctx)
 
 	ctx.wg.Wait()
 }
@@ -16,10 +16,12 @@ package main
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -30,14 +32,16 @@ import (
 	"github.com/corestoreio/csfw/codegen/tableToStruct/tpl"
 	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/util/slices"
+	"github.com/corestoreio/errors"
 )
 
 type generator struct {
 	tts             codegen.TableToStruct
 	dbrConn         *dbr.Connection
-	outfile         *os.File
+	outfile         bytes.Buffer  // rendered code, written to OutputFile only if changed
 	tables          []string      // all available tables for which we should at least generate a type definition
 	whiteListTables slices.String // table name in this slice is allowed for generic functions
+	jsonTagTables   slices.String // table name in this slice gets the column names in the json struct tags
 	eavValueTables  codegen.TypeCodeValueTable
 	wg              *sync.WaitGroup
 	// existingMethodSets contains all existing method sets from a package for the Table* types
@@ -60,16 +64,32 @@ func (g *generator) run() {
 	defer g.wg.Done()
 	g.analyzePackage()
 
-	var err error
-	g.outfile, err = os.OpenFile(g.tts.OutputFile.String(), os.O_APPEND|os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
-	codegen.LogFatal(err)
 	g.appendToFile(tpl.Copy, struct{ Package string }{Package: g.tts.Package}, nil)
 
 	g.initTables()
 	g.runHeader()
 	g.runTable()
 	g.runEAValueTables()
-	codegen.LogFatal(g.outfile.Close())
+
+	written, err := writeIfChanged(g.tts.OutputFile.String(), g.outfile.Bytes())
+	codegen.LogFatal(err)
+	if !written {
+		fmt.Printf("File %s unchanged. Skipping.\n", g.tts.OutputFile.String())
+	}
+}
+
+// writeIfChanged writes data into the file only if the SHA256 hash of data
+// differs from the hash of the current file content. Reports whether the
+// file has been written.
+func writeIfChanged(file string, data []byte) (bool, error) {
+	current, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return false, errors.Wrapf(err, "[tableToStruct] ReadFile %q", file)
+	}
+	if err == nil && sha256.Sum256(current) == sha256.Sum256(data) {
+		return false, nil
+	}
+	return true, errors.Wrapf(ioutil.WriteFile(file, data, 0600), "[tableToStruct] WriteFile %q", file)
 }
 
 func (g *generator) setMagentoVersion(v int) *generator {
@@ -135,7 +155,6 @@ func (g *generator) appendToFile(tpl string, data interface{}, addFM template.Fu
 	if _, err := g.outfile.Write(formatted); err != nil {
 		codegen.LogFatal(err)
 	}
-	codegen.LogFatal(g.outfile.Sync()) // flush immediately to disk to prevent a race condition
 }
 
 func (g *generator) initTables() {
@@ -156,17 +175,24 @@ func (g *generator) initTables() {
 		}
 	}
 
-	if g.tts.GenericsWhiteList == "" {
-		return // do nothing because nothing defined, neither custom SQL nor to copy from SQLQuery field
+	g.whiteListTables = g.queryWhiteList(g.tts.GenericsWhiteList)
+	g.jsonTagTables = g.queryWhiteList(g.tts.JSONTagsWhiteList)
+}
+
+// queryWhiteList returns the tables selected by the SQL query. An empty query
+// returns nothing and a non-select query returns all tables from SQLQuery.
+func (g *generator) queryWhiteList(query string) slices.String {
+	if query == "" {
+		return nil // do nothing because nothing defined, neither custom SQL nor to copy from SQLQuery field
 	}
-	if false == dbr.Stmt.IsSelect(g.tts.GenericsWhiteList) {
+	if false == dbr.Stmt.IsSelect(query) {
 		// copy result from tables because select key word not found
-		g.whiteListTables = g.tables
-		return
+		return g.tables
 	}
 
-	g.whiteListTables, err = codegen.GetTables(g.dbrConn.NewSession(), codegen.ReplaceTablePrefix(g.tts.GenericsWhiteList))
+	tables, err := codegen.GetTables(g.dbrConn.NewSession(), codegen.ReplaceTablePrefix(query))
 	codegen.LogFatal(err)
+	return tables
 }
 
 func (g *generator) runHeader() {
@@ -174,11 +200,13 @@ func (g *generator) runHeader() {
 	data := struct {
 		Package, Tick          string
 		HasTypeCodeValueTables bool
+		HasContext             bool
 		Tables                 []OneTable
 	}{
 		Package: g.tts.Package,
 		Tick:    "`",
 		HasTypeCodeValueTables: len(g.eavValueTables) > 0,
+		HasContext:             g.hasContext(),
 	}
 
 	for _, table := range g.tables {
@@ -195,6 +223,7 @@ func (g *generator) runTable() {
 	for _, table := range g.tables {
 
 		data := NewOneTable(g.dbrConn.DB, g.mageVersion, g.tts.Package, table)
+		data.JSONTags = g.jsonTagTables.Contains(table)
 
 		tplFuncs := template.FuncMap{
 			"typePrefix": func(name string) string {
@@ -231,6 +260,10 @@ func (g *generator) getGenericTemplate(tableName string) string {
 		_, err := finalTpl.WriteString(tpl.SQL)
 		codegen.LogFatal(err)
 	}
+	if g.hasContext() {
+		_, err := finalTpl.WriteString(tpl.SQLContext)
+		codegen.LogFatal(err)
+	}
 	if isAll || (g.tts.GenericsFunctions&tpl.OptFindBy) == tpl.OptFindBy {
 		_, err := finalTpl.WriteString(tpl.FindBy)
 		codegen.LogFatal(err)
@@ -250,6 +283,13 @@ func (g *generator) getGenericTemplate(tableName string) string {
 	return finalTpl.String()
 }
 
+// hasContext reports whether the context aware SQL functions get generated for
+// at least one table.
+func (g *generator) hasContext() bool {
+	const opts = tpl.OptSQL | tpl.OptContext
+	return (g.tts.GenericsFunctions&opts) == opts && len(g.whiteListTables) > 0
+}
+
 func (g *generator) runEAValueTables() {
 	if len(g.eavValueTables) == 0 {
 		return
@@ -0,0 +1,50 @@
+// Copyright 2015-2017, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteIfChanged(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tableToStruct")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "tables_generated.go")
+	code := []byte("package store\n")
+
+	written, err := writeIfChanged(file, code)
+	require.NoError(t, err)
+	assert.True(t, written, "First run must write the file")
+
+	written, err = writeIfChanged(file, code)
+	require.NoError(t, err)
+	assert.False(t, written, "Second run must skip the unchanged file")
+
+	written, err = writeIfChanged(file, []byte("package store\n\nconst x = 1\n"))
+	require.NoError(t, err)
+	assert.True(t, written, "Changed content must be written")
+
+	have, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	assert.Exactly(t, "package store\n\nconst x = 1\n", string(have))
+}
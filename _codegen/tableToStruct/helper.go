@@ -110,6 +110,8 @@ func dbrType(c csdb.Column) string {
 		return "" // money.Money
 	case c.IsFloat():
 		return ".Float64" // dbr.NullFloat64
+	case c.IsInt() && c.IsUnsigned():
+		return ".Uint64" // null.Uint64
 	case c.IsInt():
 		return ".Int64" // dbr.NullInt64
 	case c.IsDate():
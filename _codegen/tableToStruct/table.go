@@ -37,6 +37,8 @@ type OneTable struct {
 	Columns          csdb.Columns
 	MethodRecvPrefix string
 	FindByPk         string
+	// JSONTags if true the column name gets written into the json struct tag.
+	JSONTags bool
 }
 
 func NewOneTable(db *sql.DB, mageVersion int, pkgName, table string) OneTable {
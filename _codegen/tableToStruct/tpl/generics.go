@@ -24,7 +24,7 @@ type {{.Slice}} []*{{.Struct}}
 // {{.Struct}} represents a type for DB table {{ .TableName }}
 // Generated via tableToStruct.
 type {{.Struct}} struct {
-{{ range .GoColumns }}{{.GoName}} {{.GoType}} {{ $.Tick }}db:"{{.Field.String}}" json:",omitempty"{{ $.Tick }} {{.Comment}}
+{{ range .GoColumns }}{{.GoName}} {{.GoType}} {{ $.Tick }}db:"{{.Field.String}}" json:"{{if $.JSONTags}}{{.Field.String}}{{end}},omitempty"{{ $.Tick }} {{.Comment}}
 {{ end }} }
 `
 
@@ -38,14 +38,17 @@ const (
 	OptSort
 	OptSliceFunctions
 	OptExtractFromSlice
+	// OptContext generates additionally the context aware variant of the
+	// OptSQL select function. It is not part of OptAll and requires OptSQL.
+	OptContext
 	OptAll = OptSQL | OptFindBy | OptSort | OptSliceFunctions | OptExtractFromSlice
 )
 
 const SQL = `
 // {{ typePrefix "SQLSelect" }} fills this slice with data from the database.
 // Generated via tableToStruct.
-func (s *{{.Slice}}) {{ typePrefix "SQLSelect" }}(dbrSess dbr.SessionRunner, cbs ...dbr.SelectCb) (int, error) {
-	return csdb.LoadSlice(dbrSess, TableCollection, TableIndex{{.Name}}, &(*s), cbs...)
+func (s *{{.Slice}}) {{ typePrefix "SQLSelect" }}(db dbr.Querier, listeners ...dbr.Listen) (int, error) {
+	return TableCollection.MustTable(TableIndex{{.Name}}).LoadSlice(db, s, listeners...)
 }
 
 // {{ typePrefix "SQLInsert" }} inserts all records into the database @todo.
@@ -67,6 +70,15 @@ func (s *{{.Slice}}) {{ typePrefix "SQLDelete" }}(dbrSess dbr.SessionRunner, cbs
 }
 `
 
+const SQLContext = `
+// {{ typePrefix "SQLSelectContext" }} fills this slice with data from the
+// database and respects the context.
+// Generated via tableToStruct.
+func (s *{{.Slice}}) {{ typePrefix "SQLSelectContext" }}(ctx context.Context, db dbr.Querier, listeners ...dbr.Listen) (int, error) {
+	return TableCollection.MustTable(TableIndex{{.Name}}).LoadSliceContext(ctx, db, s, listeners...)
+}
+`
+
 const FindBy = `
 {{if (.FindByPk) ne ""}}
 // {{ typePrefix .FindByPk }} searches the primary keys and returns a
@@ -0,0 +1,86 @@
+// Copyright 2015-2017, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tpl
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typeColumn struct {
+	GoName, GoType, Comment string
+	Field                   sql.NullString
+}
+
+func executeType(t *testing.T, jsonTags bool) string {
+	data := struct {
+		Slice, Struct, TableName, Tick string
+		JSONTags                       bool
+		GoColumns                      []typeColumn
+	}{
+		Slice:     "TableUserSlice",
+		Struct:    "TableUser",
+		TableName: "admin_user",
+		Tick:      "`",
+		JSONTags:  jsonTags,
+		GoColumns: []typeColumn{
+			{GoName: "UserID", GoType: "int64", Field: sql.NullString{String: "user_id", Valid: true}},
+			{GoName: "Email", GoType: "dbr.NullString", Field: sql.NullString{String: "email", Valid: true}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := template.Must(template.New("type").Parse(Type)).Execute(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestType_JSONTags(t *testing.T) {
+	have := executeType(t, true)
+	assert.Contains(t, have, "UserID int64 `db:\"user_id\" json:\"user_id,omitempty\"`")
+	assert.Contains(t, have, "Email dbr.NullString `db:\"email\" json:\"email,omitempty\"`")
+}
+
+func TestType_WithoutJSONTags(t *testing.T) {
+	have := executeType(t, false)
+	assert.Contains(t, have, "UserID int64 `db:\"user_id\" json:\",omitempty\"`")
+	assert.Contains(t, have, "Email dbr.NullString `db:\"email\" json:\",omitempty\"`")
+}
+
+func TestSQLContext(t *testing.T) {
+	data := struct {
+		Slice, Name string
+	}{
+		Slice: "TableUserSlice",
+		Name:  "User",
+	}
+	fm := template.FuncMap{
+		"typePrefix": func(name string) string { return name },
+	}
+	var buf bytes.Buffer
+	if err := template.Must(template.New("sql").Funcs(fm).Parse(SQL+SQLContext)).Execute(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+	have := buf.String()
+	assert.Contains(t, have, "func (s *TableUserSlice) SQLSelect(db dbr.Querier, listeners ...dbr.Listen) (int, error) {")
+	assert.Contains(t, have, "func (s *TableUserSlice) SQLSelectContext(ctx context.Context, db dbr.Querier, listeners ...dbr.Listen) (int, error) {")
+	assert.Contains(t, have, "TableCollection.MustTable(TableIndexUser).LoadSlice(db, s, listeners...)")
+	assert.Contains(t, have, "TableCollection.MustTable(TableIndexUser).LoadSliceContext(ctx, db, s, listeners...)")
+	assert.NotContains(t, have, "SQLInsertContext")
+}
@@ -18,6 +18,7 @@ const Header = `
 // Auto generated via tableToStruct
 
 import (
+	{{ if .HasContext }}"context"{{end}}
 	"sort"
     {{ if .HasTypeCodeValueTables }}
 	"github.com/corestoreio/csfw/eav"{{end}}
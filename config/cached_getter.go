@@ -0,0 +1,226 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/errors"
+)
+
+// Check if the interfaces are implemented
+var _ Getter = (*CachedGetter)(nil)
+var _ MessageReceiver = (*CachedGetter)(nil)
+
+const (
+	cacheTypeByte uint8 = iota + 1
+	cacheTypeString
+	cacheTypeBool
+	cacheTypeFloat64
+	cacheTypeInt
+	cacheTypeTime
+	cacheTypeDuration
+)
+
+type cacheKey struct {
+	typ  uint8
+	path string // fully qualified path
+}
+
+type cacheEntry struct {
+	val interface{}
+	// err contains a NotFound error of the inner Getter.
+	err     error
+	route   string
+	expires time.Time
+}
+
+// CachedGetter decorates a Getter and caches the typed values for a duration
+// of TTL. The cache key contains the fully qualified path and the requested
+// type. NotFound errors get cached like values, so that bubbling up the scopes
+// does not query the inner Getter on each call. All other errors won't be
+// cached. CachedGetter implements the MessageReceiver
+// interface. Subscribe it to the routes which can be written to evict the
+// matching entries from the cache for all scopes:
+//
+//	cg := config.NewCachedGetter(srv, time.Minute)
+//	_, err := srv.Subscribe(cfgpath.NewRoute("general/country"), cg)
+//
+// Thread safe.
+type CachedGetter struct {
+	inner Getter
+	ttl   time.Duration
+
+	mu    sync.RWMutex
+	cache map[cacheKey]cacheEntry
+	// gen gets incremented on each eviction. A value fetched while an
+	// eviction happened won't be cached because it might be stale.
+	gen uint64
+}
+
+// NewCachedGetter creates a new caching Getter which reads the values from the
+// inner Getter and keeps them for the duration ttl.
+func NewCachedGetter(inner Getter, ttl time.Duration) *CachedGetter {
+	return &CachedGetter{
+		inner: inner,
+		ttl:   ttl,
+		cache: make(map[cacheKey]cacheEntry),
+	}
+}
+
+// MessageConfig removes all cached entries from all scopes whose route equals
+// the route of the path p or lies below it.
+func (cg *CachedGetter) MessageConfig(p cfgpath.Path) error {
+	route := p.Route.String()
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	cg.gen++
+	for k, e := range cg.cache {
+		if e.route == route || strings.HasPrefix(e.route, route+string(cfgpath.Separator)) {
+			delete(cg.cache, k)
+		}
+	}
+	return nil
+}
+
+// Flush removes all cached entries.
+func (cg *CachedGetter) Flush() {
+	cg.mu.Lock()
+	cg.gen++
+	cg.cache = make(map[cacheKey]cacheEntry)
+	cg.mu.Unlock()
+}
+
+func (cg *CachedGetter) get(typ uint8, p cfgpath.Path, fetch func() (interface{}, error)) (interface{}, error) {
+	key := cacheKey{typ: typ, path: p.String()}
+	now := time.Now()
+
+	cg.mu.RLock()
+	e, ok := cg.cache[key]
+	gen := cg.gen
+	cg.mu.RUnlock()
+	if ok && now.Before(e.expires) {
+		return e.val, e.err
+	}
+
+	v, err := fetch()
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	cg.mu.Lock()
+	if gen == cg.gen {
+		cg.cache[key] = cacheEntry{
+			val:     v,
+			err:     err,
+			route:   p.Route.String(),
+			expires: now.Add(cg.ttl),
+		}
+	}
+	cg.mu.Unlock()
+	return v, err
+}
+
+// NewScoped creates a new scope base configuration reader which reads the
+// values through the cache.
+func (cg *CachedGetter) NewScoped(websiteID, storeID int64) Scoped {
+	return NewScoped(cg, websiteID, storeID)
+}
+
+// Byte returns a cached copy of the byte slice.
+func (cg *CachedGetter) Byte(p cfgpath.Path) ([]byte, error) {
+	v, err := cg.get(cacheTypeByte, p, func() (interface{}, error) {
+		return cg.inner.Byte(p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	b := v.([]byte)
+	if b == nil {
+		return nil, nil
+	}
+	ret := make([]byte, len(b))
+	copy(ret, b)
+	return ret, nil
+}
+
+// String returns a cached string.
+func (cg *CachedGetter) String(p cfgpath.Path) (string, error) {
+	v, err := cg.get(cacheTypeString, p, func() (interface{}, error) {
+		return cg.inner.String(p)
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// Bool returns a cached bool.
+func (cg *CachedGetter) Bool(p cfgpath.Path) (bool, error) {
+	v, err := cg.get(cacheTypeBool, p, func() (interface{}, error) {
+		return cg.inner.Bool(p)
+	})
+	if err != nil {
+		return false, err
+	}
+	return v.(bool), nil
+}
+
+// Float64 returns a cached float64.
+func (cg *CachedGetter) Float64(p cfgpath.Path) (float64, error) {
+	v, err := cg.get(cacheTypeFloat64, p, func() (interface{}, error) {
+		return cg.inner.Float64(p)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(float64), nil
+}
+
+// Int returns a cached int.
+func (cg *CachedGetter) Int(p cfgpath.Path) (int, error) {
+	v, err := cg.get(cacheTypeInt, p, func() (interface{}, error) {
+		return cg.inner.Int(p)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(int), nil
+}
+
+// Time returns a cached time.Time.
+func (cg *CachedGetter) Time(p cfgpath.Path) (time.Time, error) {
+	v, err := cg.get(cacheTypeTime, p, func() (interface{}, error) {
+		return cg.inner.Time(p)
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return v.(time.Time), nil
+}
+
+// Duration returns a cached time.Duration.
+func (cg *CachedGetter) Duration(p cfgpath.Path) (time.Duration, error) {
+	v, err := cg.get(cacheTypeDuration, p, func() (interface{}, error) {
+		return cg.inner.Duration(p)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(time.Duration), nil
+}
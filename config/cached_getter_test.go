@@ -0,0 +1,176 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedGetter(t *testing.T) {
+
+	p := cfgpath.MustNewByParts("aa/bb/cc")
+
+	t.Run("second read within TTL", func(t *testing.T) {
+		mock := cfgmock.NewService(cfgmock.PathValue{
+			p.String():              "Gopher",
+			p.BindStore(3).String(): 2016,
+		})
+		cg := config.NewCachedGetter(mock, time.Hour)
+
+		for i := 0; i < 3; i++ {
+			have, err := cg.String(p)
+			assert.NoError(t, err, "%+v", err)
+			assert.Exactly(t, "Gopher", have)
+
+			haveInt, err := cg.Int(p.BindStore(3))
+			assert.NoError(t, err, "%+v", err)
+			assert.Exactly(t, 2016, haveInt)
+		}
+		assert.Exactly(t, 1, mock.StringInvokes().Sum())
+		assert.Exactly(t, 1, mock.IntInvokes().Sum())
+	})
+
+	t.Run("expired TTL", func(t *testing.T) {
+		mock := cfgmock.NewService(cfgmock.PathValue{
+			p.String(): true,
+		})
+		cg := config.NewCachedGetter(mock, time.Millisecond)
+
+		_, err := cg.Bool(p)
+		assert.NoError(t, err, "%+v", err)
+		time.Sleep(time.Millisecond * 5)
+		_, err = cg.Bool(p)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 2, mock.BoolInvokes().Sum())
+	})
+
+	t.Run("NotFound gets cached", func(t *testing.T) {
+		mock := cfgmock.NewService()
+		cg := config.NewCachedGetter(mock, time.Hour)
+		for i := 0; i < 2; i++ {
+			_, err := cg.String(p)
+			assert.True(t, errors.IsNotFound(err), "%+v", err)
+		}
+		assert.Exactly(t, 1, mock.StringInvokes().Sum())
+	})
+
+	t.Run("other errors are not cached", func(t *testing.T) {
+		mock := cfgmock.NewService()
+		mock.StringFn = func(path string) (string, error) {
+			return "", errors.NewFatalf("Connection lost")
+		}
+		cg := config.NewCachedGetter(mock, time.Hour)
+		for i := 0; i < 2; i++ {
+			_, err := cg.String(p)
+			assert.True(t, errors.IsFatal(err), "%+v", err)
+		}
+		assert.Exactly(t, 2, mock.StringInvokes().Sum())
+	})
+
+	t.Run("eviction during fetch", func(t *testing.T) {
+		mock := cfgmock.NewService()
+		cg := config.NewCachedGetter(mock, time.Hour)
+		calls := 0
+		mock.StringFn = func(path string) (string, error) {
+			calls++
+			if calls == 1 {
+				// a write happens while the old value is on its way
+				assert.NoError(t, cg.MessageConfig(p))
+				return "stale", nil
+			}
+			return "fresh", nil
+		}
+		have, err := cg.String(p)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "stale", have)
+		have, err = cg.String(p)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "fresh", have)
+		assert.Exactly(t, 2, mock.StringInvokes().Sum())
+	})
+
+	t.Run("MessageConfig evicts matching routes", func(t *testing.T) {
+		other := cfgpath.MustNewByParts("aa/bbx/cc")
+		mock := cfgmock.NewService(cfgmock.PathValue{
+			p.String():                "a",
+			p.BindWebsite(1).String(): "b",
+			other.String():            "c",
+		})
+		cg := config.NewCachedGetter(mock, time.Hour)
+		read := func() {
+			for _, pp := range []cfgpath.Path{p, p.BindWebsite(1), other} {
+				_, err := cg.String(pp)
+				assert.NoError(t, err, "%+v", err)
+			}
+		}
+		read()
+		assert.NoError(t, cg.MessageConfig(cfgpath.MustNewByParts("aa/bb/cc").BindStore(2)))
+		read()
+		assert.Exactly(t, 5, mock.StringInvokes().Sum())
+
+		// partial route like a subscription to the group
+		assert.NoError(t, cg.MessageConfig(cfgpath.Path{Route: cfgpath.NewRoute("aa/bb"), RouteLevelValid: true}))
+		read()
+		assert.Exactly(t, 7, mock.StringInvokes().Sum())
+	})
+
+	t.Run("Scoped reads through the cache", func(t *testing.T) {
+		mock := cfgmock.NewService(cfgmock.PathValue{
+			p.BindWebsite(1).String(): "website",
+		})
+		cg := config.NewCachedGetter(mock, time.Hour)
+		for i := 0; i < 2; i++ {
+			have, err := cg.NewScoped(1, 0).String(p.Route)
+			assert.NoError(t, err, "%+v", err)
+			assert.Exactly(t, "website", have)
+		}
+		assert.Exactly(t, 1, mock.StringInvokes().Sum())
+
+		// the NotFound of the store scope gets cached too
+		for i := 0; i < 2; i++ {
+			have, err := cg.NewScoped(1, 2).String(p.Route)
+			assert.NoError(t, err, "%+v", err)
+			assert.Exactly(t, "website", have)
+		}
+		assert.Exactly(t, 2, mock.StringInvokes().Sum())
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		mock := cfgmock.NewService(cfgmock.PathValue{
+			p.String(): []byte(`Gopher`),
+		})
+		cg := config.NewCachedGetter(mock, time.Hour)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				have, err := cg.Byte(p)
+				assert.NoError(t, err, "%+v", err)
+				assert.Exactly(t, []byte(`Gopher`), have)
+				assert.NoError(t, cg.MessageConfig(p))
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -43,20 +45,82 @@ func (a keyNotFound) NotFound() bool { return true }
 type Write struct {
 	// WriteError gets always returned by Write
 	WriteError error
+	// PathErrors contains the fully qualified path and its error which gets
+	// returned by Write for a matching path. Has precedence over WriteError.
+	PathErrors map[string]error
 	// ArgPath will be set after calling write to export the config path.
 	// Values you enter here will be overwritten when calling Write
 	ArgPath string
 	// ArgValue contains the written data
 	ArgValue interface{}
+
+	mu sync.Mutex
+	// written contains all writes in the order of their occurrence.
+	written []WriteArg
+	// expected contains the expected writes set via ExpectWrite.
+	expected []WriteArg
+}
+
+// WriteArg contains the fully qualified path and the value of a write.
+type WriteArg struct {
+	Path  string
+	Value interface{}
 }
 
 // Write writes to a black hole, may return an error
 func (w *Write) Write(p cfgpath.Path, v interface{}) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	w.ArgPath = p.String()
 	w.ArgValue = v
+	w.written = append(w.written, WriteArg{Path: w.ArgPath, Value: v})
+	if err, ok := w.PathErrors[w.ArgPath]; ok {
+		return err
+	}
 	return w.WriteError
 }
 
+// Written returns all writes in the order of their occurrence.
+func (w *Write) Written() []WriteArg {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ret := make([]WriteArg, len(w.written))
+	copy(ret, w.written)
+	return ret
+}
+
+// ExpectWrite adds an expected write of a value to the fully qualified path.
+// Use ExpectationsWereMet to check the expectations.
+func (w *Write) ExpectWrite(path string, value interface{}) *Write {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expected = append(w.expected, WriteArg{Path: path, Value: value})
+	return w
+}
+
+// ExpectationsWereMet checks if all expected writes have occurred in the
+// order of their definition. Other writes in between are permitted. Returns a
+// NotValid error listing the first unmet expectation.
+func (w *Write) ExpectationsWereMet() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	pos := 0
+	for _, e := range w.expected {
+		found := false
+		for ; pos < len(w.written); pos++ {
+			if wa := w.written[pos]; wa.Path == e.Path && reflect.DeepEqual(wa.Value, e.Value) {
+				found = true
+				pos++
+				break
+			}
+		}
+		if !found {
+			return errors.NewNotValidf("[cfgmock] Expected write to path %q with value %#v did not occur. Written: %#v", e.Path, e.Value, w.written)
+		}
+	}
+	return nil
+}
+
 // Invocations represents a list containing the fully qualified configuration
 // path and number of invocations. This type has attached some helper functions.
 type Invocations map[string]int
@@ -71,6 +135,17 @@ func (iv Invocations) Sum() int {
 	return s
 }
 
+func (iv Invocations) clone() Invocations {
+	if iv == nil {
+		return nil
+	}
+	ret := make(Invocations, len(iv))
+	for k, v := range iv {
+		ret[k] = v
+	}
+	return ret
+}
+
 // PathCount returns the number of different paths.
 func (iv Invocations) PathCount() int {
 	return len(iv)
@@ -94,7 +169,7 @@ func (iv Invocations) ScopeIDs() scope.TypeIDs {
 	ids := make(scope.TypeIDs, len(iv))
 	i := 0
 	for k := range iv {
-		p, err := cfgpath.SplitFQ(k)
+		p, err := splitFQ(k)
 		if err != nil {
 			panic(fmt.Sprintf("[cfgmock] Path: %q with error: %+v", k, err))
 		}
@@ -105,6 +180,28 @@ func (iv Invocations) ScopeIDs() scope.TypeIDs {
 	return ids
 }
 
+// splitFQ same as cfgpath.SplitFQ but supports additionally the paths bound
+// with cfgpath.Path.BindGroup like groups/2/aa/bb/cc.
+func splitFQ(fq string) (cfgpath.Path, error) {
+	const groups = "groups/"
+	if !strings.HasPrefix(fq, groups) {
+		return cfgpath.SplitFQ(fq)
+	}
+	idRoute := strings.SplitN(fq[len(groups):], "/", 2)
+	if len(idRoute) != 2 {
+		return cfgpath.Path{}, errors.NewNotValidf("[cfgmock] Incorrect fully qualified path: %q", fq)
+	}
+	id, err := strconv.ParseInt(idRoute[0], 10, 64)
+	if err != nil {
+		return cfgpath.Path{}, errors.NewNotValid(err, "[cfgmock] Path %q", fq)
+	}
+	p, err := cfgpath.New(cfgpath.NewRoute(idRoute[1]))
+	if err != nil {
+		return cfgpath.Path{}, errors.Wrapf(err, "[cfgmock] Path %q", fq)
+	}
+	return p.BindGroup(id), nil
+}
+
 // Service used for testing. Contains functions which will be called in the
 // appropriate methods of interface config.Getter. Field DB has precedence over
 // the applied functions.
@@ -125,6 +222,7 @@ type Service struct {
 	timeInvokes      Invocations
 	DurationFn       func(path string) (time.Duration, error)
 	durationInvokes  Invocations
+	invokedPaths     []string // all paths of all typed functions in calling order
 	SubscribeFn      func(cfgpath.Route, config.MessageReceiver) (subscriptionID int, err error)
 	SubscribeInvokes int32
 }
@@ -135,7 +233,7 @@ type PathValue map[string]interface{}
 
 func (pv PathValue) set(db config.Storager) {
 	for fq, v := range pv {
-		p, err := cfgpath.SplitFQ(fq)
+		p, err := splitFQ(fq)
 		if err != nil {
 			panic(err)
 		}
@@ -209,6 +307,20 @@ func (s *Service) AllInvocations() Invocations {
 	return ret
 }
 
+// InvokedPaths returns the fully qualified paths of all calls to the typed
+// functions (Byte, String, Bool, etc) in the order of their invocation.
+// Duplicated paths are included.
+func (s *Service) InvokedPaths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.invokedPaths == nil {
+		return nil
+	}
+	ret := make([]string, len(s.invokedPaths))
+	copy(ret, s.invokedPaths)
+	return ret
+}
+
 // UpdateValues adds or overwrites the internal path => value map.
 func (s *Service) UpdateValues(pv PathValue) {
 	pv.set(s.Storage)
@@ -244,6 +356,7 @@ func (s *Service) Byte(p cfgpath.Path) ([]byte, error) {
 	}
 	ps := p.String()
 	s.byteInvokes[ps]++
+	s.invokedPaths = append(s.invokedPaths, ps)
 
 	switch {
 	case s.hasVal(p):
@@ -257,7 +370,9 @@ func (s *Service) Byte(p cfgpath.Path) ([]byte, error) {
 
 // ByteInvokes returns the number of Byte() invocations.
 func (s *Service) ByteInvokes() Invocations {
-	return s.byteInvokes
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.byteInvokes.clone()
 }
 
 // String returns a string value
@@ -269,6 +384,7 @@ func (s *Service) String(p cfgpath.Path) (string, error) {
 	}
 	ps := p.String()
 	s.stringInvokes[ps]++
+	s.invokedPaths = append(s.invokedPaths, ps)
 
 	switch {
 	case s.hasVal(p):
@@ -282,7 +398,9 @@ func (s *Service) String(p cfgpath.Path) (string, error) {
 
 // StringInvokes returns the number of String() invocations.
 func (s *Service) StringInvokes() Invocations {
-	return s.stringInvokes
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stringInvokes.clone()
 }
 
 // Bool returns a bool value
@@ -294,6 +412,7 @@ func (s *Service) Bool(p cfgpath.Path) (bool, error) {
 	}
 	ps := p.String()
 	s.boolInvokes[ps]++
+	s.invokedPaths = append(s.invokedPaths, ps)
 
 	switch {
 	case s.hasVal(p):
@@ -307,7 +426,9 @@ func (s *Service) Bool(p cfgpath.Path) (bool, error) {
 
 // BoolInvokes returns the number of Bool() invocations.
 func (s *Service) BoolInvokes() Invocations {
-	return s.boolInvokes
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.boolInvokes.clone()
 }
 
 // Float64 returns a float64 value
@@ -319,6 +440,7 @@ func (s *Service) Float64(p cfgpath.Path) (float64, error) {
 	}
 	ps := p.String()
 	s.float64Invokes[ps]++
+	s.invokedPaths = append(s.invokedPaths, ps)
 
 	switch {
 	case s.hasVal(p):
@@ -332,7 +454,9 @@ func (s *Service) Float64(p cfgpath.Path) (float64, error) {
 
 // Float64Invokes returns the number of Float64() invocations.
 func (s *Service) Float64Invokes() Invocations {
-	return s.float64Invokes
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.float64Invokes.clone()
 }
 
 // Int returns an integer value
@@ -344,6 +468,7 @@ func (s *Service) Int(p cfgpath.Path) (int, error) {
 	}
 	ps := p.String()
 	s.intInvokes[ps]++
+	s.invokedPaths = append(s.invokedPaths, ps)
 
 	switch {
 	case s.hasVal(p):
@@ -357,7 +482,9 @@ func (s *Service) Int(p cfgpath.Path) (int, error) {
 
 // IntInvokes returns the number of Int() invocations.
 func (s *Service) IntInvokes() Invocations {
-	return s.intInvokes
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.intInvokes.clone()
 }
 
 // Time returns a time value
@@ -369,6 +496,7 @@ func (s *Service) Time(p cfgpath.Path) (time.Time, error) {
 	}
 	ps := p.String()
 	s.timeInvokes[ps]++
+	s.invokedPaths = append(s.invokedPaths, ps)
 
 	switch {
 	case s.hasVal(p):
@@ -382,7 +510,9 @@ func (s *Service) Time(p cfgpath.Path) (time.Time, error) {
 
 // TimeInvokes returns the number of Time() invocations.
 func (s *Service) TimeInvokes() Invocations {
-	return s.timeInvokes
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.timeInvokes.clone()
 }
 
 // Duration returns a duration value or a NotFound error.
@@ -394,6 +524,7 @@ func (s *Service) Duration(p cfgpath.Path) (time.Duration, error) {
 	}
 	ps := p.String()
 	s.durationInvokes[ps]++
+	s.invokedPaths = append(s.invokedPaths, ps)
 
 	switch {
 	case s.hasVal(p):
@@ -407,7 +538,9 @@ func (s *Service) Duration(p cfgpath.Path) (time.Duration, error) {
 
 // DurationInvokes returns the number of Duration() invocations.
 func (s *Service) DurationInvokes() Invocations {
-	return s.durationInvokes
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.durationInvokes.clone()
 }
 
 // Subscribe returns the before applied SubscriptionID and SubscriptionErr
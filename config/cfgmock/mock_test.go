@@ -161,6 +161,104 @@ func TestService_FnInvokes_Map(t *testing.T) {
 	assert.Exactly(t, 1, s.AllInvocations().PathCount())
 }
 
+func TestService_InvokedPaths(t *testing.T) {
+	s := cfgmock.NewService(cfgmock.PathValue{
+		"default/0/aa/bb/cc":  "Gopher",
+		"websites/2/aa/bb/cc": 3,
+		"stores/3/xx/yy/zz":   true,
+	})
+	def := cfgpath.MustNewByParts("aa/bb/cc")
+	_, _ = s.String(def)
+	_, _ = s.Int(def.BindWebsite(2))
+	_, _ = s.Bool(cfgpath.MustNewByParts("xx/yy/zz").BindStore(3))
+	_, _ = s.String(def)
+	_, _ = s.Float64(def.BindStore(5)) // not found but recorded
+
+	assert.Exactly(t, []string{
+		"default/0/aa/bb/cc",
+		"websites/2/aa/bb/cc",
+		"stores/3/xx/yy/zz",
+		"default/0/aa/bb/cc",
+		"stores/5/aa/bb/cc",
+	}, s.InvokedPaths())
+	assert.Exactly(t, cfgmock.Invocations{
+		"default/0/aa/bb/cc":  2,
+		"websites/2/aa/bb/cc": 1,
+		"stores/3/xx/yy/zz":   1,
+		"stores/5/aa/bb/cc":   1,
+	}, s.AllInvocations())
+	assert.Exactly(t, 2, s.StringInvokes().Sum())
+	assert.Nil(t, s.TimeInvokes())
+}
+
+func TestService_InvokedPaths_Concurrent(t *testing.T) {
+	s := cfgmock.NewService(cfgmock.PathValue{
+		"default/0/aa/bb/cc": "Gopher",
+	})
+	p := cfgpath.MustNewByParts("aa/bb/cc")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = s.String(p)
+			_, _ = s.Byte(p)
+			_ = s.StringInvokes().Sum()
+			_ = s.InvokedPaths()
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, s.InvokedPaths(), 40)
+	assert.Exactly(t, 40, s.AllInvocations().Sum())
+}
+
+func TestWrite_Expectations(t *testing.T) {
+	p := cfgpath.MustNewByParts("aa/bb/cc")
+
+	t.Run("successful sequence", func(t *testing.T) {
+		w := new(cfgmock.Write).
+			ExpectWrite("default/0/aa/bb/cc", "Gopher").
+			ExpectWrite("stores/3/aa/bb/cc", 33)
+
+		assert.NoError(t, w.Write(p, "Gopher"))
+		assert.NoError(t, w.Write(p.BindWebsite(2), 2.2))
+		assert.NoError(t, w.Write(p.BindStore(3), 33))
+
+		assert.NoError(t, w.ExpectationsWereMet())
+		assert.Exactly(t, []cfgmock.WriteArg{
+			{Path: "default/0/aa/bb/cc", Value: "Gopher"},
+			{Path: "websites/2/aa/bb/cc", Value: 2.2},
+			{Path: "stores/3/aa/bb/cc", Value: 33},
+		}, w.Written())
+		assert.Exactly(t, "stores/3/aa/bb/cc", w.ArgPath)
+		assert.Exactly(t, 33, w.ArgValue)
+	})
+
+	t.Run("unmet expectation", func(t *testing.T) {
+		w := new(cfgmock.Write).
+			ExpectWrite("stores/3/aa/bb/cc", 33).
+			ExpectWrite("default/0/aa/bb/cc", "Gopher")
+
+		assert.NoError(t, w.Write(p, "Gopher"))
+		assert.NoError(t, w.Write(p.BindStore(3), 34))
+		err := w.ExpectationsWereMet()
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+
+	t.Run("path specific failure", func(t *testing.T) {
+		w := &cfgmock.Write{
+			PathErrors: map[string]error{
+				"websites/2/aa/bb/cc": errors.NewNotImplementedf("Website scope"),
+			},
+		}
+		assert.NoError(t, w.Write(p, 1))
+		err := w.Write(p.BindWebsite(2), 2)
+		assert.True(t, errors.IsNotImplemented(err), "%+v", err)
+		assert.NoError(t, w.Write(p.BindStore(3), 3))
+		assert.Len(t, w.Written(), 3)
+	})
+}
+
 func TestInvocations_ScopeIDs(t *testing.T) {
 	iv := cfgmock.Invocations{"websites/5/web/cors/allow_credentials": 1, "default/0/web/cors/allow_credentials": 1}
 	assert.Exactly(t, scope.TypeIDs{scope.DefaultTypeID, scope.Website.Pack(5)}, iv.ScopeIDs())
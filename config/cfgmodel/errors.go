@@ -18,4 +18,5 @@ const (
 	errScopePermissionInsufficient = `[cfgmodel] Scope permission insufficient: Have %q; Want %q; Route: %q`
 	errValueNotFoundInOptions      = `[cfgmodel] The value '%s' cannot be found within the allowed Options():\n%s`
 	errIntCSVFailedToConvertToInt  = `[cfgmodel] IntCsv.Get: Cannot cannot convert %q to type int: %v`
+	errIntOutOfRange               = `[cfgmodel] Value %d out of range [%d, %d]; Route: %q`
 )
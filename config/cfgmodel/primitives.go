@@ -215,6 +215,54 @@ func (i Int) Write(w config.Writer, v int, h scope.TypeID) error {
 	return i.baseValue.Write(w, v, h)
 }
 
+// IntRange represents a path in config.Getter which handles int values which
+// must be within the bounds Min and Max, both inclusive.
+type IntRange struct {
+	Int
+	Min int
+	Max int
+}
+
+// NewIntRange creates a new IntRange cfgmodel with a given path and the
+// inclusive bounds min and max.
+func NewIntRange(path string, min, max int, opts ...Option) IntRange {
+	return IntRange{
+		Int: NewInt(path, opts...),
+		Min: min,
+		Max: max,
+	}
+}
+
+// Get returns an int value from ScopedGetter like Int.Get. Returns a NotValid
+// error if the value, or the applied default value, lies outside of Min and
+// Max.
+func (ir IntRange) Get(sg config.Scoped) (int, error) {
+	v, err := ir.Int.Get(sg)
+	if err != nil {
+		return 0, errors.Wrap(err, "[cfgmodel] IntRange.Get")
+	}
+	if err := ir.validate(v); err != nil {
+		return 0, errors.Wrap(err, "[cfgmodel] IntRange.Get")
+	}
+	return v, nil
+}
+
+// Write writes an int value and returns a NotValid error if the value lies
+// outside of Min and Max.
+func (ir IntRange) Write(w config.Writer, v int, h scope.TypeID) error {
+	if err := ir.validate(v); err != nil {
+		return errors.Wrap(err, "[cfgmodel] IntRange.Write")
+	}
+	return ir.Int.Write(w, v, h)
+}
+
+func (ir IntRange) validate(v int) error {
+	if v < ir.Min || v > ir.Max {
+		return errors.NewNotValidf(errIntOutOfRange, v, ir.Min, ir.Max, ir.route)
+	}
+	return nil
+}
+
 // Float64 represents a path in config.Getter which handles float64 values.
 type Float64 struct{ baseValue }
 
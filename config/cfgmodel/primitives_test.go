@@ -619,6 +619,66 @@ func TestIntWrite(t *testing.T) {
 	assert.Exactly(t, 27182, mw.ArgValue.(int))
 }
 
+func TestIntRangeGet(t *testing.T) {
+
+	const pathMaxRecipients = "contact/email/max_recipients"
+	wantPath := cfgpath.MustNewByParts(pathMaxRecipients)
+	b := cfgmodel.NewIntRange(pathMaxRecipients, 1, 50)
+
+	tests := []struct {
+		sg         config.Scoped
+		want       int
+		wantErrBhf errors.BehaviourFunc
+	}{
+		{cfgmock.NewService(cfgmock.PathValue{wantPath.String(): 1}).NewScoped(1, 1), 1, nil},
+		{cfgmock.NewService(cfgmock.PathValue{wantPath.String(): 50}).NewScoped(1, 1), 50, nil},
+		{cfgmock.NewService(cfgmock.PathValue{wantPath.String(): 51}).NewScoped(1, 1), 0, errors.IsNotValid},
+		{cfgmock.NewService(cfgmock.PathValue{wantPath.String(): -3}).NewScoped(1, 1), 0, errors.IsNotValid},
+		// missing value without a default value returns zero which is out of range
+		{cfgmock.NewService().NewScoped(1, 1), 0, errors.IsNotValid},
+	}
+	for i, test := range tests {
+		have, err := b.Get(test.sg)
+		if test.wantErrBhf != nil {
+			assert.True(t, test.wantErrBhf(err), "Index %d => %+v", i, err)
+		} else {
+			assert.NoError(t, err, "Index %d => %+v", i, err)
+		}
+		assert.Exactly(t, test.want, have, "Index %d", i)
+	}
+
+	// missing value falls back to the default value of the field
+	bd := cfgmodel.NewIntRange(pathMaxRecipients, 1, 50, cfgmodel.WithField(&element.Field{ID: cfgpath.NewRoute("max_recipients"), Default: 5}))
+	have, err := bd.Get(cfgmock.NewService().NewScoped(1, 1))
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, 5, have)
+
+	// unexpected errors get passed through
+	sm := cfgmock.Service{
+		IntFn: func(path string) (int, error) {
+			return 0, errors.NewFatalf("Unexpected error")
+		},
+	}
+	_, err = b.Get(sm.NewScoped(1, 1))
+	assert.True(t, errors.IsFatal(err), "%+v", err)
+}
+
+func TestIntRangeWrite(t *testing.T) {
+
+	const pathMaxRecipients = "contact/email/max_recipients"
+	b := cfgmodel.NewIntRange(pathMaxRecipients, 1, 50)
+
+	mw := &cfgmock.Write{}
+	assert.NoError(t, b.Write(mw, 42, scope.DefaultTypeID))
+	assert.Exactly(t, cfgpath.MustNewByParts(pathMaxRecipients).String(), mw.ArgPath)
+	assert.Exactly(t, 42, mw.ArgValue.(int))
+
+	mw = &cfgmock.Write{}
+	err := b.Write(mw, 0, scope.DefaultTypeID)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+	assert.Empty(t, mw.ArgPath)
+}
+
 func TestFloat64GetWithCfgStruct(t *testing.T) {
 
 	const pathWebCorsF64 = "web/cors/float64"
@@ -39,6 +39,9 @@ const sSeparator = "/"
 
 var bSeparator = []byte(sSeparator)
 
+// bGroups scope string of a path bound with BindGroup.
+var bGroups = []byte("groups")
+
 // Path represents a configuration path bound to a scope.
 type Path struct {
 	Route
@@ -49,6 +52,9 @@ type Path struct {
 	RouteLevelValid bool
 	// routeValidated internal flag to avoid running twice the route valid process
 	routeValidated bool
+	// groupScope internal flag set by BindGroup to write the group scope
+	// instead of falling back to default.
+	groupScope bool
 }
 
 // New creates a new validated Path. Scope is assigned to Default.
@@ -99,6 +105,7 @@ func MustNewByParts(parts ...string) Path {
 // supported and falls back to default. Fluent API design.
 func (p Path) Bind(s scope.TypeID) Path {
 	p.ScopeID = s
+	p.groupScope = false
 	return p
 }
 
@@ -109,6 +116,16 @@ func (p Path) BindWebsite(id int64) Path {
 	return p
 }
 
+// BindGroup binds a path to a group scope and its ID. Contrary to Bind the
+// path gets written with the scope string "groups". Only used by
+// implementations supporting a group scope between website and store, like
+// config.Scoped. Fluent API design.
+func (p Path) BindGroup(id int64) Path {
+	p.ScopeID = scope.MakeTypeID(scope.Group, id)
+	p.groupScope = true
+	return p
+}
+
 // BindStore binds a path to a store scope and its ID. Convenience helper
 // function. Fluent API design.
 func (p Path) BindStore(id int64) Path {
@@ -207,12 +224,16 @@ func (p Path) fq(buf *bytes.Buffer) error {
 	}
 
 	scp, id := p.ScopeID.Unpack()
-	if scp != scope.Website && scp != scope.Store {
-		scp = scope.Default
+	scpBytes := scp.StrBytes()
+	switch {
+	case scp == scope.Group && p.groupScope:
+		scpBytes = bGroups
+	case scp != scope.Website && scp != scope.Store:
+		scpBytes = scope.Default.StrBytes()
 		id = 0
 	}
 
-	if _, err := buf.Write(scp.StrBytes()); err != nil {
+	if _, err := buf.Write(scpBytes); err != nil {
 		return errors.NewWriteFailed(err, "[cfgpath] buf.Write")
 	}
 	if err := buf.WriteByte(Separator); err != nil {
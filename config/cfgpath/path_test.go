@@ -270,6 +270,14 @@ func BenchmarkPathHashLevel2(b *testing.B) {
 	}
 }
 
+func TestPathBindGroup(t *testing.T) {
+	p := cfgpath.MustNewByParts("aa/bb/cc")
+	assert.Exactly(t, "groups/3/aa/bb/cc", p.BindGroup(3).String())
+	assert.Exactly(t, "default/0/aa/bb/cc", p.Bind(scope.Group.Pack(3)).String())
+	assert.Exactly(t, "default/0/aa/bb/cc", p.BindGroup(3).Bind(scope.Group.Pack(3)).String())
+	assert.Exactly(t, "websites/1/aa/bb/cc", p.BindGroup(3).BindWebsite(1).String())
+}
+
 func TestSplitFQ(t *testing.T) {
 
 	tests := []struct {
@@ -0,0 +1,135 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/errors"
+)
+
+var _ Getter = (*EnvGetter)(nil)
+
+// EnvGetter decorates a Getter and returns the value of an environment
+// variable, if set, before asking the inner Getter. The name of the variable
+// gets derived from the route of the path: Prefix plus the upper cased route
+// with slashes replaced by underscores. With Prefix "CS_" the route
+// general/country/allow results in CS_GENERAL_COUNTRY_ALLOW. The scope of
+// the path gets ignored, so an environment variable overrides all scopes.
+// Malformed values return a NotValid error.
+type EnvGetter struct {
+	inner Getter
+	// Prefix gets prepended to the name of each environment variable.
+	Prefix string
+}
+
+// NewEnvGetter creates a new Getter which prefers environment variables over
+// the inner Getter.
+func NewEnvGetter(inner Getter, prefix string) *EnvGetter {
+	return &EnvGetter{
+		inner:  inner,
+		Prefix: prefix,
+	}
+}
+
+// EnvName returns the name of the environment variable for path p.
+func (eg *EnvGetter) EnvName(p cfgpath.Path) string {
+	return eg.Prefix + strings.ToUpper(strings.Replace(p.Route.String(), "/", "_", -1))
+}
+
+func (eg *EnvGetter) lookup(p cfgpath.Path) (string, string, bool) {
+	name := eg.EnvName(p)
+	v, ok := os.LookupEnv(name)
+	return name, v, ok
+}
+
+// NewScoped creates a new scope base configuration reader which reads the
+// values through the environment.
+func (eg *EnvGetter) NewScoped(websiteID, storeID int64) Scoped {
+	return NewScoped(eg, websiteID, storeID)
+}
+
+// Byte returns the environment variable or the value of the inner Getter.
+func (eg *EnvGetter) Byte(p cfgpath.Path) ([]byte, error) {
+	if _, v, ok := eg.lookup(p); ok {
+		return []byte(v), nil
+	}
+	return eg.inner.Byte(p)
+}
+
+// String returns the environment variable or the value of the inner Getter.
+func (eg *EnvGetter) String(p cfgpath.Path) (string, error) {
+	if _, v, ok := eg.lookup(p); ok {
+		return v, nil
+	}
+	return eg.inner.String(p)
+}
+
+// Bool returns the converted environment variable or the value of the inner
+// Getter.
+func (eg *EnvGetter) Bool(p cfgpath.Path) (bool, error) {
+	if name, v, ok := eg.lookup(p); ok {
+		b, err := conv.ToBoolE(v)
+		return b, errors.Wrapf(err, "[config] EnvGetter.Bool. Variable %q", name)
+	}
+	return eg.inner.Bool(p)
+}
+
+// Float64 returns the converted environment variable or the value of the
+// inner Getter.
+func (eg *EnvGetter) Float64(p cfgpath.Path) (float64, error) {
+	if name, v, ok := eg.lookup(p); ok {
+		f, err := conv.ToFloat64E(v)
+		return f, errors.Wrapf(err, "[config] EnvGetter.Float64. Variable %q", name)
+	}
+	return eg.inner.Float64(p)
+}
+
+// Int returns the converted environment variable or the value of the inner
+// Getter.
+func (eg *EnvGetter) Int(p cfgpath.Path) (int, error) {
+	if name, v, ok := eg.lookup(p); ok {
+		i, err := conv.ToIntE(v)
+		return i, errors.Wrapf(err, "[config] EnvGetter.Int. Variable %q", name)
+	}
+	return eg.inner.Int(p)
+}
+
+// Time returns the converted environment variable or the value of the inner
+// Getter.
+func (eg *EnvGetter) Time(p cfgpath.Path) (time.Time, error) {
+	if name, v, ok := eg.lookup(p); ok {
+		t, err := conv.ToTimeE(v)
+		return t, errors.Wrapf(err, "[config] EnvGetter.Time. Variable %q", name)
+	}
+	return eg.inner.Time(p)
+}
+
+// Duration returns the converted environment variable or the value of the
+// inner Getter.
+func (eg *EnvGetter) Duration(p cfgpath.Path) (time.Duration, error) {
+	if name, v, ok := eg.lookup(p); ok {
+		d, err := conv.ToDurationE(v)
+		if err != nil {
+			return 0, errors.NewNotValid(err, "[config] EnvGetter.Duration. Variable %q", name)
+		}
+		return d, nil
+	}
+	return eg.inner.Duration(p)
+}
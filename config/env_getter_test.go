@@ -0,0 +1,110 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func setEnv(t *testing.T, key, value string) func() {
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		if err := os.Unsetenv(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestEnvGetter(t *testing.T) {
+
+	p := cfgpath.MustNewByParts("web/cookie/lifetime")
+	eg := config.NewEnvGetter(cfgmock.NewService(cfgmock.PathValue{
+		p.String():              3600,
+		p.BindStore(2).String(): 7200,
+	}), "CSTEST_")
+	assert.Exactly(t, "CSTEST_WEB_COOKIE_LIFETIME", eg.EnvName(p))
+
+	t.Run("env hit overrides all scopes", func(t *testing.T) {
+		defer setEnv(t, "CSTEST_WEB_COOKIE_LIFETIME", "60")()
+
+		have, err := eg.Int(p)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 60, have)
+
+		have, err = eg.NewScoped(1, 2).Int(p.Route)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 60, have)
+
+		haveF, err := eg.Float64(p)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 60.0, haveF)
+
+		haveS, err := eg.String(p)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "60", haveS)
+	})
+
+	t.Run("env miss falls through", func(t *testing.T) {
+		have, err := eg.Int(p)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 3600, have)
+
+		have, err = eg.NewScoped(1, 2).Int(p.Route)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 7200, have)
+	})
+
+	t.Run("bool time duration", func(t *testing.T) {
+		defer setEnv(t, "CSTEST_WEB_COOKIE_LIFETIME", "1")()
+		haveB, err := eg.Bool(p)
+		assert.NoError(t, err, "%+v", err)
+		assert.True(t, haveB)
+
+		defer setEnv(t, "CSTEST_WEB_COOKIE_LIFETIME", "2016-11-07T13:14:15Z")()
+		haveT, err := eg.Time(p)
+		assert.NoError(t, err, "%+v", err)
+		assert.True(t, time.Date(2016, 11, 7, 13, 14, 15, 0, time.UTC).Equal(haveT), "%s", haveT)
+
+		defer setEnv(t, "CSTEST_WEB_COOKIE_LIFETIME", "90s")()
+		haveD, err := eg.Duration(p)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 90*time.Second, haveD)
+	})
+
+	t.Run("malformed env value", func(t *testing.T) {
+		defer setEnv(t, "CSTEST_WEB_COOKIE_LIFETIME", "one hour")()
+
+		_, err := eg.Int(p)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		_, err = eg.Bool(p)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		_, err = eg.Float64(p)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		_, err = eg.Time(p)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		_, err = eg.Duration(p)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+}
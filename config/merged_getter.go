@@ -0,0 +1,201 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/errors"
+)
+
+// Check if the interfaces are implemented
+var _ GetterPubSuber = (*MergedGetter)(nil)
+
+// MergedGetter composes multiple Getters into one. The Getters get queried in
+// the order of their occurrence and the first result which is not a NotFound
+// error wins. For example a file based default configuration can be
+// overridden by a database:
+//
+//	mg := config.NewMergedGetter(dbGetter, fileGetter)
+//
+// Thread safe.
+type MergedGetter struct {
+	getters []Getter
+
+	mu sync.Mutex
+	// lastID last generated subscription ID
+	lastID int
+	// subs maps the own subscription ID to the IDs of the sources.
+	subs map[int][]mergedSub
+}
+
+type mergedSub struct {
+	source Subscriber
+	id     int
+}
+
+// NewMergedGetter creates a new Getter which queries the getters in the
+// provided order.
+func NewMergedGetter(getters ...Getter) *MergedGetter {
+	return &MergedGetter{
+		getters: getters,
+		subs:    make(map[int][]mergedSub),
+	}
+}
+
+func (mg *MergedGetter) get(p cfgpath.Path, fn func(Getter) (interface{}, error)) (interface{}, error) {
+	var lastErr error
+	for _, g := range mg.getters {
+		v, err := fn(g)
+		if err == nil {
+			return v, nil
+		}
+		if !errors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "[config] MergedGetter. Path %q", p)
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		return nil, errors.NewNotFoundf("[config] MergedGetter: Path %q not found, no sources", p)
+	}
+	return nil, errors.NewNotFound(lastErr, "[config] MergedGetter: Path %q not found in %d sources", p, len(mg.getters))
+}
+
+// NewScoped creates a new scope base configuration reader which reads the
+// values from all sources.
+func (mg *MergedGetter) NewScoped(websiteID, storeID int64) Scoped {
+	return NewScoped(mg, websiteID, storeID)
+}
+
+// Byte returns the first found byte slice.
+func (mg *MergedGetter) Byte(p cfgpath.Path) ([]byte, error) {
+	v, err := mg.get(p, func(g Getter) (interface{}, error) { return g.Byte(p) })
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// String returns the first found string.
+func (mg *MergedGetter) String(p cfgpath.Path) (string, error) {
+	v, err := mg.get(p, func(g Getter) (interface{}, error) { return g.String(p) })
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// Bool returns the first found bool.
+func (mg *MergedGetter) Bool(p cfgpath.Path) (bool, error) {
+	v, err := mg.get(p, func(g Getter) (interface{}, error) { return g.Bool(p) })
+	if err != nil {
+		return false, err
+	}
+	return v.(bool), nil
+}
+
+// Float64 returns the first found float64.
+func (mg *MergedGetter) Float64(p cfgpath.Path) (float64, error) {
+	v, err := mg.get(p, func(g Getter) (interface{}, error) { return g.Float64(p) })
+	if err != nil {
+		return 0, err
+	}
+	return v.(float64), nil
+}
+
+// Int returns the first found int.
+func (mg *MergedGetter) Int(p cfgpath.Path) (int, error) {
+	v, err := mg.get(p, func(g Getter) (interface{}, error) { return g.Int(p) })
+	if err != nil {
+		return 0, err
+	}
+	return v.(int), nil
+}
+
+// Time returns the first found time.Time.
+func (mg *MergedGetter) Time(p cfgpath.Path) (time.Time, error) {
+	v, err := mg.get(p, func(g Getter) (interface{}, error) { return g.Time(p) })
+	if err != nil {
+		return time.Time{}, err
+	}
+	return v.(time.Time), nil
+}
+
+// Duration returns the first found time.Duration.
+func (mg *MergedGetter) Duration(p cfgpath.Path) (time.Duration, error) {
+	v, err := mg.get(p, func(g Getter) (interface{}, error) { return g.Duration(p) })
+	if err != nil {
+		return 0, err
+	}
+	return v.(time.Duration), nil
+}
+
+// Subscribe subscribes the MessageReceiver to all sources which implement the
+// Subscriber interface. Returns a NotSupported error if no source supports
+// subscriptions. If one source fails, the already created subscriptions get
+// removed.
+func (mg *MergedGetter) Subscribe(r cfgpath.Route, mr MessageReceiver) (subscriptionID int, err error) {
+	var subs []mergedSub
+	for _, g := range mg.getters {
+		s, ok := g.(Subscriber)
+		if !ok {
+			continue
+		}
+		id, err := s.Subscribe(r, mr)
+		if err != nil {
+			_ = unsubscribeMerged(subs)
+			return 0, errors.Wrapf(err, "[config] MergedGetter.Subscribe. Route %q", r)
+		}
+		subs = append(subs, mergedSub{source: s, id: id})
+	}
+	if len(subs) == 0 {
+		return 0, errors.NewNotSupportedf("[config] MergedGetter.Subscribe: No source supports subscriptions. Route %q", r)
+	}
+
+	mg.mu.Lock()
+	defer mg.mu.Unlock()
+	mg.lastID++
+	mg.subs[mg.lastID] = subs
+	return mg.lastID, nil
+}
+
+// Unsubscribe removes the subscription from all sources which implement a
+// function Unsubscribe(int) error.
+func (mg *MergedGetter) Unsubscribe(subscriptionID int) error {
+	mg.mu.Lock()
+	subs, ok := mg.subs[subscriptionID]
+	delete(mg.subs, subscriptionID)
+	mg.mu.Unlock()
+	if !ok {
+		return errors.NewNotFoundf("[config] MergedGetter.Unsubscribe: Subscription ID %d not found", subscriptionID)
+	}
+	return errors.Wrapf(unsubscribeMerged(subs), "[config] MergedGetter.Unsubscribe. ID %d", subscriptionID)
+}
+
+func unsubscribeMerged(subs []mergedSub) (err error) {
+	type unsubscriber interface {
+		Unsubscribe(subscriptionID int) error
+	}
+	for _, s := range subs {
+		if us, ok := s.source.(unsubscriber); ok {
+			if err2 := us.Unsubscribe(s.id); err2 != nil && err == nil {
+				err = err2
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type blackHoleReceiver struct{}
+
+func (blackHoleReceiver) MessageConfig(cfgpath.Path) error { return nil }
+
+func TestMergedGetter(t *testing.T) {
+
+	p := cfgpath.MustNewByParts("general/locale/code")
+
+	t.Run("value only in second source", func(t *testing.T) {
+		db := cfgmock.NewService()
+		file := cfgmock.NewService(cfgmock.PathValue{
+			p.String(): "de_CH",
+		})
+		mg := config.NewMergedGetter(db, file)
+		have, err := mg.String(p)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "de_CH", have)
+		assert.Exactly(t, 1, db.StringInvokes().Sum())
+		assert.Exactly(t, 1, file.StringInvokes().Sum())
+	})
+
+	t.Run("first source wins", func(t *testing.T) {
+		db := cfgmock.NewService(cfgmock.PathValue{
+			p.String(): "fr_CH",
+		})
+		file := cfgmock.NewService(cfgmock.PathValue{
+			p.String(): "de_CH",
+		})
+		have, err := config.NewMergedGetter(db, file).String(p)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "fr_CH", have)
+		assert.Nil(t, file.StringInvokes())
+	})
+
+	t.Run("non NotFound error short circuits", func(t *testing.T) {
+		db := cfgmock.NewService()
+		db.IntFn = func(path string) (int, error) {
+			return 0, errors.NewFatalf("Connection lost")
+		}
+		file := cfgmock.NewService(cfgmock.PathValue{
+			p.String(): 4,
+		})
+		have, err := config.NewMergedGetter(db, file).Int(p)
+		assert.Exactly(t, 0, have)
+		assert.True(t, errors.IsFatal(err), "%+v", err)
+		assert.Nil(t, file.IntInvokes())
+	})
+
+	t.Run("zero sources", func(t *testing.T) {
+		mg := config.NewMergedGetter()
+		have, err := mg.String(p)
+		assert.Empty(t, have)
+		assert.True(t, errors.IsNotFound(err), "%+v", err)
+		b, err := mg.Byte(p)
+		assert.Nil(t, b)
+		assert.True(t, errors.IsNotFound(err), "%+v", err)
+	})
+
+	t.Run("NotFound in all sources", func(t *testing.T) {
+		have, err := config.NewMergedGetter(cfgmock.NewService(), cfgmock.NewService()).Bool(p)
+		assert.False(t, have)
+		assert.True(t, errors.IsNotFound(err), "%+v", err)
+	})
+
+	t.Run("Subscribe fans out", func(t *testing.T) {
+		srv1 := config.MustNewService(config.NewInMemoryStore(), config.WithPubSub())
+		defer func() { assert.NoError(t, srv1.Close()) }()
+		srv2 := config.MustNewService(config.NewInMemoryStore(), config.WithPubSub())
+		defer func() { assert.NoError(t, srv2.Close()) }()
+
+		mg := config.NewMergedGetter(srv1, config.NewEnvGetter(cfgmock.NewService(), "CSTEST_"), srv2)
+		id, err := mg.Subscribe(p.Route, blackHoleReceiver{})
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 1, id)
+		assert.NoError(t, mg.Unsubscribe(id))
+		assert.True(t, errors.IsNotFound(mg.Unsubscribe(id)))
+	})
+
+	t.Run("Subscribe error", func(t *testing.T) {
+		mock := cfgmock.NewService()
+		mock.SubscribeFn = func(cfgpath.Route, config.MessageReceiver) (int, error) {
+			return 0, errors.NewAlreadyClosedf("Closed")
+		}
+		_, err := config.NewMergedGetter(mock).Subscribe(p.Route, blackHoleReceiver{})
+		assert.True(t, errors.IsAlreadyClosed(err), "%+v", err)
+		assert.Exactly(t, int32(1), mock.SubscribeInvokes)
+	})
+}
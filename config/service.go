@@ -155,6 +155,11 @@ func (s *Service) NewScoped(websiteID, storeID int64) Scoped {
 	return NewScoped(s, websiteID, storeID)
 }
 
+// NewScopedWriter creates a new scope base configuration writer
+func (s *Service) NewScopedWriter(websiteID, storeID int64) ScopedWriter {
+	return NewScopedWriter(s, websiteID, storeID)
+}
+
 // Write puts a value back into the Service. Example usage:
 //		// Default Scope
 //		p, err := cfgpath.NewByParts("currency/option/base") // or use cfgpath.MustNewByParts( ... )
@@ -15,6 +15,9 @@
 package config
 
 import (
+	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/corestoreio/csfw/config/cfgpath"
@@ -26,15 +29,15 @@ import (
 //type ScopedStringer interface {
 //  Parent() (scope.Scope, int64)
 //	scope.Scoper
-//	Bind(scope.Scope) ScopedGetter
 //	String(r cfgpath.Route, s ...scope.Scope) (string, error)
 //}
 // and so on ...
 
 // Scoped is equal to Getter but not an interface and the underlying
-// implementation takes care of providing the correct scope: default, website or
-// store and bubbling up the scope chain from store -> website -> default if a
-// value won't get found in the desired scope. The cfgpath.Route for each
+// implementation takes care of providing the correct scope: default, website,
+// group or store and bubbling up the scope chain from store -> group -> website
+// -> default if a value won't get found in the desired scope. The group scope
+// gets only considered if a GroupID has been set. The cfgpath.Route for each
 // primitive type represents always a path like "section/group/element" without
 // the scope string and scope ID.
 //
@@ -45,9 +48,10 @@ import (
 // access the ScopedGetter from a store.Store, store.Website type the second
 // argument must already be internally pre-filled.
 //
-// WebsiteID and StoreID must be in a relation like enforced in the database
-// tables via foreign keys. Empty storeID triggers the website scope. Empty
-// websiteID and empty storeID are triggering the default scope.
+// WebsiteID, GroupID and StoreID must be in a relation like enforced in the
+// database tables via foreign keys. Empty storeID triggers the group scope if
+// a groupID has been set otherwise the website scope. Empty websiteID and
+// empty storeID are triggering the default scope.
 //
 // You can use the function NewScoped() to create a new object but not
 // mandatory. Returned error has mostly the behaviour of NotFound. Debug logging
@@ -57,30 +61,56 @@ type Scoped struct {
 	// storage.
 	Root      Getter
 	WebsiteID int64
-	StoreID   int64
+	// GroupID optional ID of the store group. Zero skips the group scope
+	// when bubbling up.
+	GroupID int64
+	StoreID int64
+	// Separator splits the value in the function Strings. Zero value falls
+	// back to the constant ListSeparator.
+	Separator rune
+	// Scope restricts bubbling up for all functions like the optional scope
+	// argument does. The optional argument takes precedence. Zero value
+	// (scope.Absent) bubbles up from the ScopeID(). Use function Bind.
+	Scope scope.Type
 }
 
+// ListSeparator default separator for the slice functions of type Scoped.
+const ListSeparator = ','
+
 // NewScopedService instantiates a ScopedGetter implementation.  Getter
-// specifies the root Getter which does not know about any scope.
-func NewScoped(r Getter, websiteID, storeID int64) Scoped {
-	return Scoped{
+// specifies the root Getter which does not know about any scope. The optional
+// groupID enables the group scope between the store and website scope.
+func NewScoped(r Getter, websiteID, storeID int64, groupID ...int64) Scoped {
+	ss := Scoped{
 		Root:      r,
 		WebsiteID: websiteID,
 		StoreID:   storeID,
 	}
+	if len(groupID) > 0 {
+		ss.GroupID = groupID[0]
+	}
+	return ss
 }
 
-// IsValid checks if the object has been set up correctly.
+// IsValid checks if the object has been set up correctly. A GroupID requires
+// a WebsiteID.
 func (ss Scoped) IsValid() bool {
+	if ss.GroupID > 0 && ss.WebsiteID == 0 {
+		return false
+	}
 	return ss.Root != nil && ((ss.WebsiteID == 0 && ss.StoreID == 0) ||
 		(ss.WebsiteID > 0 && ss.StoreID == 0) ||
 		(ss.WebsiteID > 0 && ss.StoreID > 0))
 }
 
 // ParentID tells you the parent underlying scope and its ID. Store falls back
-// to website and website falls back to default.
+// to group, if set, or website. Group falls back to website and website falls
+// back to default.
 func (ss Scoped) ParentID() scope.TypeID {
-	if ss.StoreID > 0 {
+	if ss.StoreID > 0 && ss.GroupID > 0 {
+		return scope.Group.Pack(ss.GroupID)
+	}
+	if ss.StoreID > 0 || ss.GroupID > 0 {
 		return scope.Website.Pack(ss.WebsiteID)
 	}
 	return scope.DefaultTypeID
@@ -92,6 +122,9 @@ func (ss Scoped) ScopeID() scope.TypeID {
 	if ss.StoreID > 0 {
 		return scope.Store.Pack(ss.StoreID)
 	}
+	if ss.GroupID > 0 {
+		return scope.Group.Pack(ss.GroupID)
+	}
 	if ss.WebsiteID > 0 {
 		return scope.Website.Pack(ss.WebsiteID)
 	}
@@ -109,23 +142,44 @@ func (ss Scoped) ScopeIDs() scope.TypeIDs {
 	return ids[:]
 }
 
-func (ss Scoped) isAllowedStore(s ...scope.Type) bool {
-	scp := ss.ScopeID().Type()
+// Bind returns a copy of Scoped which reads for all functions only in the scope
+// s and its parents. For example a store bound Scoped with Bind(scope.Website)
+// skips the store scope and reads from website and default scope. The optional
+// scope argument of the functions still takes precedence.
+func (ss Scoped) Bind(s scope.Type) Scoped {
+	ss.Scope = s
+	return ss
+}
+
+func (ss Scoped) scope(s ...scope.Type) scope.Type {
 	if len(s) > 0 && s[0] > scope.Absent {
-		scp = s[0]
+		return s[0]
 	}
-	return ss.StoreID > 0 && scope.PermStoreReverse.Has(scp)
+	if ss.Scope > scope.Absent {
+		return ss.Scope
+	}
+	return ss.ScopeID().Type()
+}
+
+func (ss Scoped) isAllowedStore(s ...scope.Type) bool {
+	return ss.StoreID > 0 && scope.PermStoreReverse.Has(ss.scope(s...))
+}
+
+func (ss Scoped) isAllowedGroup(s ...scope.Type) bool {
+	return ss.GroupID > 0 && scope.PermGroupReverse.Has(ss.scope(s...))
 }
 
+// isAllowedWebsite includes the group scope if a GroupID has been set because
+// a group bubbles up to its website.
 func (ss Scoped) isAllowedWebsite(s ...scope.Type) bool {
-	scp := ss.ScopeID().Type()
-	if len(s) > 0 && s[0] > scope.Absent {
-		scp = s[0]
+	perm := scope.PermWebsiteReverse
+	if ss.GroupID > 0 {
+		perm = perm.Set(scope.Group)
 	}
-	return ss.WebsiteID > 0 && scope.PermWebsiteReverse.Has(scp)
+	return ss.WebsiteID > 0 && perm.Has(ss.scope(s...))
 }
 
-// Byte traverses through the scopes store->website->default to find
+// Byte traverses through the scopes store->group->website->default to find
 // a matching byte slice value.
 func (ss Scoped) Byte(r cfgpath.Route, s ...scope.Type) ([]byte, error) {
 	// fallback to next parent scope if value does not exists
@@ -142,6 +196,14 @@ func (ss Scoped) Byte(r cfgpath.Route, s ...scope.Type) ([]byte, error) {
 			return v, err
 		}
 	}
+	if ss.isAllowedGroup(s...) {
+		p = p.BindGroup(ss.GroupID)
+		v, err := ss.Root.Byte(p)
+		if !errors.IsNotFound(err) || err == nil {
+			// value found or err is not a NotFound error
+			return v, err
+		}
+	}
 	if ss.isAllowedWebsite(s...) {
 		p = p.BindWebsite(ss.WebsiteID)
 		v, err := ss.Root.Byte(p)
@@ -154,7 +216,7 @@ func (ss Scoped) Byte(r cfgpath.Route, s ...scope.Type) ([]byte, error) {
 	return ss.Root.Byte(p)
 }
 
-// String traverses through the scopes store->website->default to find
+// String traverses through the scopes store->group->website->default to find
 // a matching string value.
 func (ss Scoped) String(r cfgpath.Route, s ...scope.Type) (string, error) {
 	// fallback to next parent scope if value does not exists
@@ -172,6 +234,15 @@ func (ss Scoped) String(r cfgpath.Route, s ...scope.Type) (string, error) {
 		}
 	}
 
+	if ss.isAllowedGroup(s...) {
+		p = p.BindGroup(ss.GroupID)
+		v, err := ss.Root.String(p)
+		if !errors.IsNotFound(err) || err == nil {
+			// value found or err is not a NotFound error
+			return v, err
+		}
+	}
+
 	if ss.isAllowedWebsite(s...) {
 		p = p.BindWebsite(ss.WebsiteID)
 		v, err := ss.Root.String(p)
@@ -184,7 +255,7 @@ func (ss Scoped) String(r cfgpath.Route, s ...scope.Type) (string, error) {
 	return ss.Root.String(p)
 }
 
-// Bool traverses through the scopes store->website->default to find
+// Bool traverses through the scopes store->group->website->default to find
 // a matching bool value.
 func (ss Scoped) Bool(r cfgpath.Route, s ...scope.Type) (bool, error) {
 	// fallback to next parent scope if value does not exists
@@ -200,7 +271,16 @@ func (ss Scoped) Bool(r cfgpath.Route, s ...scope.Type) (bool, error) {
 			// value found or err is not a NotFound error
 			return v, err
 		}
-	} // if not found in store scope go to website scope
+	} // if not found in store scope go to group scope
+
+	if ss.isAllowedGroup(s...) {
+		p = p.BindGroup(ss.GroupID)
+		v, err := ss.Root.Bool(p)
+		if !errors.IsNotFound(err) || err == nil {
+			// value found or err is not a NotFound error
+			return v, err
+		}
+	} // if not found in group scope go to website scope
 
 	if ss.isAllowedWebsite(s...) {
 		p = p.BindWebsite(ss.WebsiteID)
@@ -214,7 +294,7 @@ func (ss Scoped) Bool(r cfgpath.Route, s ...scope.Type) (bool, error) {
 	return ss.Root.Bool(p)
 }
 
-// Float64 traverses through the scopes store->website->default to find
+// Float64 traverses through the scopes store->group->website->default to find
 // a matching float64 value.
 func (ss Scoped) Float64(r cfgpath.Route, s ...scope.Type) (float64, error) {
 	// fallback to next parent scope if value does not exists
@@ -230,7 +310,16 @@ func (ss Scoped) Float64(r cfgpath.Route, s ...scope.Type) (float64, error) {
 			// value found or err is not a NotFound error
 			return v, err
 		}
-	} // if not found in store scope go to website scope
+	} // if not found in store scope go to group scope
+
+	if ss.isAllowedGroup(s...) {
+		p = p.BindGroup(ss.GroupID)
+		v, err := ss.Root.Float64(p)
+		if !errors.IsNotFound(err) || err == nil {
+			// value found or err is not a NotFound error
+			return v, err
+		}
+	} // if not found in group scope go to website scope
 
 	if ss.isAllowedWebsite(s...) {
 		p = p.BindWebsite(ss.WebsiteID)
@@ -244,7 +333,7 @@ func (ss Scoped) Float64(r cfgpath.Route, s ...scope.Type) (float64, error) {
 	return ss.Root.Float64(p)
 }
 
-// Int traverses through the scopes store->website->default to find
+// Int traverses through the scopes store->group->website->default to find
 // a matching int value.
 func (ss Scoped) Int(r cfgpath.Route, s ...scope.Type) (int, error) {
 	// fallback to next parent scope if value does not exists
@@ -260,7 +349,16 @@ func (ss Scoped) Int(r cfgpath.Route, s ...scope.Type) (int, error) {
 			// value found or err is not a NotFound error
 			return v, err
 		}
-	} // if not found in store scope go to website scope
+	} // if not found in store scope go to group scope
+
+	if ss.isAllowedGroup(s...) {
+		p = p.BindGroup(ss.GroupID)
+		v, err := ss.Root.Int(p)
+		if !errors.IsNotFound(err) || err == nil {
+			// value found or err is not a NotFound error
+			return v, err
+		}
+	} // if not found in group scope go to website scope
 
 	if ss.isAllowedWebsite(s...) {
 		p = p.BindWebsite(ss.WebsiteID)
@@ -274,7 +372,7 @@ func (ss Scoped) Int(r cfgpath.Route, s ...scope.Type) (int, error) {
 	return ss.Root.Int(p)
 }
 
-// Time traverses through the scopes store->website->default to find
+// Time traverses through the scopes store->group->website->default to find
 // a matching time.Time value.
 func (ss Scoped) Time(r cfgpath.Route, s ...scope.Type) (time.Time, error) {
 	// fallback to next parent scope if value does not exists
@@ -290,7 +388,16 @@ func (ss Scoped) Time(r cfgpath.Route, s ...scope.Type) (time.Time, error) {
 			// value found or err is not a NotFound error
 			return v, err
 		}
-	} // if not found in store scope go to website scope
+	} // if not found in store scope go to group scope
+
+	if ss.isAllowedGroup(s...) {
+		p = p.BindGroup(ss.GroupID)
+		v, err := ss.Root.Time(p)
+		if !errors.IsNotFound(err) || err == nil {
+			// value found or err is not a NotFound error
+			return v, err
+		}
+	} // if not found in group scope go to website scope
 
 	if ss.isAllowedWebsite(s...) {
 		p = p.BindWebsite(ss.WebsiteID)
@@ -304,7 +411,7 @@ func (ss Scoped) Time(r cfgpath.Route, s ...scope.Type) (time.Time, error) {
 	return ss.Root.Time(p)
 }
 
-// Duration traverses through the scopes store->website->default to find
+// Duration traverses through the scopes store->group->website->default to find
 // a matching time.Duration value.
 func (ss Scoped) Duration(r cfgpath.Route, s ...scope.Type) (time.Duration, error) {
 	// fallback to next parent scope if value does not exists
@@ -320,7 +427,16 @@ func (ss Scoped) Duration(r cfgpath.Route, s ...scope.Type) (time.Duration, erro
 			// value found or err is not a NotFound error
 			return v, errors.Wrapf(err, "[config] Duration Scope Store. Path %q", p)
 		}
-	} // if not found in store scope go to website scope
+	} // if not found in store scope go to group scope
+
+	if ss.isAllowedGroup(s...) {
+		p = p.BindGroup(ss.GroupID)
+		v, err := ss.Root.Duration(p)
+		if !errors.IsNotFound(err) || err == nil {
+			// value found or err is not a NotFound error
+			return v, errors.Wrapf(err, "[config] Duration Scope Group. Path %q", p)
+		}
+	} // if not found in group scope go to website scope
 
 	if ss.isAllowedWebsite(s...) {
 		p = p.BindWebsite(ss.WebsiteID)
@@ -337,3 +453,108 @@ func (ss Scoped) Duration(r cfgpath.Route, s ...scope.Type) (time.Duration, erro
 	}
 	return v, nil
 }
+
+// Strings traverses through the scopes store->group->website->default to find a
+// matching string value and splits it by the Separator. Whitespace around each
+// entry gets trimmed and empty entries are dropped.
+func (ss Scoped) Strings(r cfgpath.Route, s ...scope.Type) ([]string, error) {
+	v, err := ss.String(r, s...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[config] Strings. Route %q", r)
+	}
+	return ss.split(v), nil
+}
+
+// Ints traverses through the scopes store->group->website->default to find a
+// matching string value, splits it by the Separator and parses each entry as
+// an int. Whitespace around each entry gets trimmed and empty entries are
+// dropped. A non-numeric entry returns a NotValid error.
+func (ss Scoped) Ints(r cfgpath.Route, s ...scope.Type) ([]int, error) {
+	v, err := ss.String(r, s...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[config] Ints. Route %q", r)
+	}
+	vals := ss.split(v)
+	ret := make([]int, len(vals))
+	for i, val := range vals {
+		if ret[i], err = strconv.Atoi(val); err != nil {
+			return nil, errors.NewNotValidf("[config] Ints. Route %q: Cannot parse %q at index %d: %s", r, val, i, err)
+		}
+	}
+	return ret, nil
+}
+
+// JSON traverses through the scopes store->group->website->default to find a
+// matching byte value and decodes it into dest. A decoding failure returns a
+// NotValid error containing the route.
+func (ss Scoped) JSON(r cfgpath.Route, dest interface{}, s ...scope.Type) error {
+	v, err := ss.Byte(r, s...)
+	if err != nil {
+		return errors.Wrapf(err, "[config] JSON. Route %q", r)
+	}
+	if err := json.Unmarshal(v, dest); err != nil {
+		return errors.NewNotValid(err, "[config] JSON.Unmarshal. Route %q", r)
+	}
+	return nil
+}
+
+// Values traverses for each route through the scopes
+// store->group->website->default to find a matching byte value. The returned
+// map contains as key the fully qualified path bound to the scope of
+// ScopeID(), regardless in which scope the value has been found. Routes
+// without a value are omitted. If reading of some routes fails with an error
+// other than NotFound, the values of all other routes and an error containing
+// the first failure and all failed routes get returned.
+func (ss Scoped) Values(rs ...cfgpath.Route) (map[string][]byte, error) {
+	ret := make(map[string][]byte, len(rs))
+	var firstErr error
+	var failed []string
+	for _, r := range rs {
+		p, err := cfgpath.New(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[config] Values. Route %q", r)
+		}
+		v, err := ss.Byte(r)
+		switch {
+		case err == nil:
+			ret[ss.bind(p).String()] = v
+		case errors.IsNotFound(err):
+			// omit
+		default:
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed = append(failed, r.String())
+		}
+	}
+	if firstErr != nil {
+		return ret, errors.Wrapf(firstErr, "[config] Values: %d of %d routes failed: %q", len(failed), len(rs), failed)
+	}
+	return ret, nil
+}
+
+// bind binds p to ScopeID(). A group gets bound with BindGroup because Bind
+// falls back to the default scope for a group.
+func (ss Scoped) bind(p cfgpath.Path) cfgpath.Path {
+	if scp, id := ss.ScopeID().Unpack(); scp == scope.Group {
+		return p.BindGroup(id)
+	}
+	return p.Bind(ss.ScopeID())
+}
+
+func (ss Scoped) split(v string) []string {
+	sep := ss.Separator
+	if sep == 0 {
+		sep = ListSeparator
+	}
+	fields := strings.FieldsFunc(v, func(r rune) bool {
+		return r == sep
+	})
+	ret := fields[:0]
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
@@ -49,6 +49,9 @@ func TestScoped_IsValid(t *testing.T) {
 		{config.Scoped{Root: cfg, WebsiteID: 0, StoreID: 1}, false},
 		{config.Scoped{Root: cfg, WebsiteID: 1, StoreID: -1}, false},
 		{config.Scoped{Root: cfg, WebsiteID: -1, StoreID: -1}, false},
+		{config.Scoped{Root: cfg, WebsiteID: 1, GroupID: 2, StoreID: 1}, true},
+		{config.Scoped{Root: cfg, WebsiteID: 1, GroupID: 2}, true},
+		{config.Scoped{Root: cfg, GroupID: 2}, false},
 	}
 	for i, test := range tests {
 		if have, want := test.s.IsValid(), test.want; have != want {
@@ -171,6 +174,249 @@ func TestScopedServicePath(t *testing.T) {
 	}
 }
 
+func TestScoped_Strings(t *testing.T) {
+
+	route := cfgpath.NewRoute("general/country/allow")
+	basePath := cfgpath.MustNew(route)
+
+	t.Run("store falls back to website", func(t *testing.T) {
+		cg := cfgmock.NewService(cfgmock.PathValue{
+			basePath.String():                " DE, AT ",
+			basePath.BindWebsite(2).String(): "CH, , LI,",
+		})
+		have, err := cg.NewScoped(2, 5).Strings(route, scope.Store)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, []string{"CH", "LI"}, have)
+		assert.Exactly(t, scope.TypeIDs{scope.Website.Pack(2), scope.Store.Pack(5)}, cg.AllInvocations().ScopeIDs())
+	})
+	t.Run("default scope", func(t *testing.T) {
+		cg := cfgmock.NewService(cfgmock.PathValue{
+			basePath.String(): " DE, AT ",
+		})
+		have, err := cg.NewScoped(2, 5).Strings(route, scope.Store)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, []string{"DE", "AT"}, have)
+	})
+	t.Run("custom separator", func(t *testing.T) {
+		scpd := cfgmock.NewService(cfgmock.PathValue{
+			basePath.String(): "simple\ngrouped\n\nconfigurable",
+		}).NewScoped(0, 0)
+		scpd.Separator = '\n'
+		have, err := scpd.Strings(route)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, []string{"simple", "grouped", "configurable"}, have)
+	})
+	t.Run("empty value", func(t *testing.T) {
+		have, err := cfgmock.NewService(cfgmock.PathValue{
+			basePath.String(): "",
+		}).NewScoped(0, 0).Strings(route)
+		assert.NoError(t, err, "%+v", err)
+		assert.Empty(t, have)
+	})
+	t.Run("not found", func(t *testing.T) {
+		have, err := cfgmock.NewService().NewScoped(1, 1).Strings(route)
+		assert.Nil(t, have)
+		assert.True(t, errors.IsNotFound(err), "%+v", err)
+	})
+}
+
+func TestScoped_Ints(t *testing.T) {
+
+	route := cfgpath.NewRoute("customer/group/ids")
+	basePath := cfgpath.MustNew(route)
+
+	t.Run("store falls back to website", func(t *testing.T) {
+		cg := cfgmock.NewService(cfgmock.PathValue{
+			basePath.String():                "1,2",
+			basePath.BindWebsite(3).String(): " 4, 5 ,,-6",
+		})
+		have, err := cg.NewScoped(3, 7).Ints(route, scope.Store)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, []int{4, 5, -6}, have)
+		assert.Exactly(t, scope.TypeIDs{scope.Website.Pack(3), scope.Store.Pack(7)}, cg.AllInvocations().ScopeIDs())
+	})
+	t.Run("restricted to default scope", func(t *testing.T) {
+		cg := cfgmock.NewService(cfgmock.PathValue{
+			basePath.String():                "1,2",
+			basePath.BindWebsite(3).String(): "4,5",
+		})
+		have, err := cg.NewScoped(3, 7).Ints(route, scope.Default)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, []int{1, 2}, have)
+	})
+	t.Run("empty value", func(t *testing.T) {
+		have, err := cfgmock.NewService(cfgmock.PathValue{
+			basePath.String(): "",
+		}).NewScoped(0, 0).Ints(route)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, []int{}, have)
+	})
+	t.Run("non numeric", func(t *testing.T) {
+		have, err := cfgmock.NewService(cfgmock.PathValue{
+			basePath.String(): "1,2,x3",
+		}).NewScoped(0, 0).Ints(route)
+		assert.Nil(t, have)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		assert.Contains(t, err.Error(), `"x3" at index 2`)
+	})
+	t.Run("not found", func(t *testing.T) {
+		have, err := cfgmock.NewService().NewScoped(1, 1).Ints(route)
+		assert.Nil(t, have)
+		assert.True(t, errors.IsNotFound(err), "%+v", err)
+	})
+}
+
+func TestScoped_JSON(t *testing.T) {
+
+	route := cfgpath.NewRoute("catalog/layered_navigation/config")
+	basePath := cfgpath.MustNew(route)
+
+	type layeredNav struct {
+		DisplayCount bool   `json:"display_count"`
+		PriceStep    int    `json:"price_step"`
+		Algorithm    string `json:"algorithm"`
+	}
+
+	t.Run("struct with store fallback to website", func(t *testing.T) {
+		cg := cfgmock.NewService(cfgmock.PathValue{
+			basePath.String():                `{"display_count":false,"price_step":10,"algorithm":"auto"}`,
+			basePath.BindWebsite(2).String(): []byte(`{"display_count":true,"price_step":100,"algorithm":"manual"}`),
+		})
+		var have layeredNav
+		err := cg.NewScoped(2, 4).JSON(route, &have, scope.Store)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, layeredNav{DisplayCount: true, PriceStep: 100, Algorithm: "manual"}, have)
+		assert.Exactly(t, scope.TypeIDs{scope.Website.Pack(2), scope.Store.Pack(4)}, cg.AllInvocations().ScopeIDs())
+	})
+	t.Run("map", func(t *testing.T) {
+		cg := cfgmock.NewService(cfgmock.PathValue{
+			basePath.String(): `{"a":"b","c":"d"}`,
+		})
+		var have map[string]string
+		err := cg.NewScoped(0, 0).JSON(route, &have)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, map[string]string{"a": "b", "c": "d"}, have)
+	})
+	t.Run("malformed", func(t *testing.T) {
+		cg := cfgmock.NewService(cfgmock.PathValue{
+			basePath.String(): `{"a":"b"`,
+		})
+		var have map[string]string
+		err := cg.NewScoped(0, 0).JSON(route, &have)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		assert.Contains(t, err.Error(), "catalog/layered_navigation/config")
+	})
+	t.Run("not found", func(t *testing.T) {
+		var have map[string]string
+		err := cfgmock.NewService().NewScoped(1, 0).JSON(route, &have)
+		assert.True(t, errors.IsNotFound(err), "%+v", err)
+		assert.Nil(t, have)
+	})
+}
+
+func TestScoped_Values(t *testing.T) {
+
+	rCode := cfgpath.NewRoute("general/locale/code")
+	rTZ := cfgpath.NewRoute("general/locale/timezone")
+	rMissing := cfgpath.NewRoute("general/locale/weekend")
+	rFail := cfgpath.NewRoute("general/locale/firstday")
+
+	t.Run("present and absent paths", func(t *testing.T) {
+		cg := cfgmock.NewService(cfgmock.PathValue{
+			cfgpath.MustNew(rCode).String():              "de_DE",
+			cfgpath.MustNew(rCode).BindStore(4).String(): "de_CH",
+			cfgpath.MustNew(rTZ).BindWebsite(2).String(): "Europe/Zurich",
+		})
+		have, err := cg.NewScoped(2, 4).Values(rCode, rTZ, rMissing)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, map[string][]byte{
+			"stores/4/general/locale/code":     []byte("de_CH"),
+			"stores/4/general/locale/timezone": []byte("Europe/Zurich"),
+		}, have)
+	})
+
+	t.Run("default scope", func(t *testing.T) {
+		cg := cfgmock.NewService(cfgmock.PathValue{
+			cfgpath.MustNew(rCode).String(): "de_DE",
+		})
+		have, err := cg.NewScoped(0, 0).Values(rCode, rMissing)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, map[string][]byte{
+			"default/0/general/locale/code": []byte("de_DE"),
+		}, have)
+	})
+
+	t.Run("non NotFound failure", func(t *testing.T) {
+		cg := cfgmock.NewService(cfgmock.PathValue{
+			cfgpath.MustNew(rCode).String(): "de_DE",
+		})
+		cg.ByteFn = func(path string) ([]byte, error) {
+			if strings.HasSuffix(path, rFail.String()) {
+				return nil, errors.NewFatalf("Database gone")
+			}
+			return nil, errors.NewNotFoundf("Path %q", path)
+		}
+		have, err := cg.NewScoped(0, 0).Values(rFail, rCode, rMissing)
+		assert.True(t, errors.IsFatal(err), "%+v", err)
+		assert.Contains(t, err.Error(), "1 of 3 routes failed")
+		assert.Exactly(t, map[string][]byte{
+			"default/0/general/locale/code": []byte("de_DE"),
+		}, have)
+	})
+
+	t.Run("invalid route", func(t *testing.T) {
+		have, err := cfgmock.NewService().NewScoped(0, 0).Values(cfgpath.NewRoute("general"))
+		assert.Nil(t, have)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+}
+
+func TestScoped_Bind(t *testing.T) {
+
+	r := cfgpath.NewRoute("general/locale/code")
+	cg := cfgmock.NewService(cfgmock.PathValue{
+		cfgpath.MustNew(r).String():                "en_US",
+		cfgpath.MustNew(r).BindWebsite(2).String(): "de_DE",
+		cfgpath.MustNew(r).BindStore(4).String():   "de_CH",
+	})
+	sg := cg.NewScoped(2, 4)
+
+	tests := []struct {
+		sg   config.Scoped
+		arg  []scope.Type
+		want string
+	}{
+		{sg, nil, "de_CH"},
+		{sg.Bind(scope.Store), nil, "de_CH"},
+		{sg.Bind(scope.Website), nil, "de_DE"},
+		{sg.Bind(scope.Default), nil, "en_US"},
+		{sg.Bind(scope.Absent), nil, "de_CH"},
+		// optional argument takes precedence
+		{sg.Bind(scope.Website), []scope.Type{scope.Store}, "de_CH"},
+		{sg.Bind(scope.Store), []scope.Type{scope.Default}, "en_US"},
+	}
+	for i, test := range tests {
+		have, err := test.sg.String(r, test.arg...)
+		if err != nil {
+			t.Fatalf("Index %d => %+v", i, err)
+		}
+		assert.Exactly(t, test.want, have, "Index %d", i)
+	}
+
+	// Bind returns a copy
+	assert.Exactly(t, scope.Absent, sg.Scope)
+	assert.Exactly(t, scope.Store.Pack(4), sg.Bind(scope.Website).ScopeID())
+
+	// website bound service bubbles up to default
+	cgW := cfgmock.NewService(cfgmock.PathValue{
+		cfgpath.MustNew(r).String():              "en_US",
+		cfgpath.MustNew(r).BindStore(4).String(): "de_CH",
+	})
+	have, err := cgW.NewScoped(2, 4).Bind(scope.Website).String(r)
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "en_US", have)
+}
+
 var benchmarkScopedServiceString string
 
 // BenchmarkScopedServiceStringStore-4	 1000000	      2218 ns/op	     320 B/op	       9 allocs/op => Go 1.5.2
@@ -251,6 +497,88 @@ func TestScopedServicePermission(t *testing.T) {
 	assert.Exactly(t, []string{"default/0/aa/bb/cc", "stores/1/aa/bb/cc", "websites/1/aa/bb/cc"}, sm.StringInvokes().Paths())
 }
 
+func TestScoped_Group(t *testing.T) {
+
+	basePath := cfgpath.MustNewByParts("aa/bb/cc")
+
+	t.Run("found in group before website", func(t *testing.T) {
+		sm := cfgmock.NewService(cfgmock.PathValue{
+			basePath.Bind(scope.DefaultTypeID).String(): "a",
+			basePath.BindWebsite(1).String():            "b",
+			basePath.BindGroup(2).String():              "g",
+		})
+		have, err := config.NewScoped(sm, 1, 3, 2).String(basePath.Route)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "g", have)
+		assert.Exactly(t, scope.TypeIDs{scope.Group.Pack(2), scope.Store.Pack(3)}, sm.StringInvokes().ScopeIDs())
+	})
+	t.Run("group bound without store", func(t *testing.T) {
+		sm := cfgmock.NewService(cfgmock.PathValue{
+			basePath.BindWebsite(1).String(): "b",
+			basePath.BindGroup(2).String():   "7",
+		})
+		have, err := config.NewScoped(sm, 1, 0, 2).Int(basePath.Route)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 7, have)
+	})
+	t.Run("group bound without store falls back to website", func(t *testing.T) {
+		sm := cfgmock.NewService(cfgmock.PathValue{
+			basePath.Bind(scope.DefaultTypeID).String(): "a",
+			basePath.BindWebsite(1).String():            "b",
+		})
+		have, err := config.NewScoped(sm, 1, 0, 2).String(basePath.Route)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "b", have)
+		assert.Exactly(t, scope.TypeIDs{scope.Website.Pack(1), scope.Group.Pack(2)}, sm.StringInvokes().ScopeIDs())
+	})
+	t.Run("falls back to website", func(t *testing.T) {
+		sm := cfgmock.NewService(cfgmock.PathValue{
+			basePath.Bind(scope.DefaultTypeID).String(): "a",
+			basePath.BindWebsite(1).String():            "b",
+		})
+		have, err := config.NewScoped(sm, 1, 3, 2).String(basePath.Route)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "b", have)
+		assert.Exactly(t, scope.TypeIDs{scope.Website.Pack(1), scope.Group.Pack(2), scope.Store.Pack(3)}, sm.StringInvokes().ScopeIDs())
+	})
+	t.Run("without GroupID", func(t *testing.T) {
+		sm := cfgmock.NewService(cfgmock.PathValue{
+			basePath.BindWebsite(1).String(): "b",
+			basePath.BindGroup(2).String():   "g",
+		})
+		have, err := config.NewScoped(sm, 1, 3).String(basePath.Route)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "b", have)
+	})
+	t.Run("restricted to group falls back to website", func(t *testing.T) {
+		sm := cfgmock.NewService(cfgmock.PathValue{
+			basePath.Bind(scope.DefaultTypeID).String(): "a",
+			basePath.BindWebsite(1).String():            "b",
+			basePath.BindStore(3).String():              "c",
+		})
+		have, err := config.NewScoped(sm, 1, 3, 2).String(basePath.Route, scope.Group)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "b", have)
+	})
+	t.Run("Values keyed by group path", func(t *testing.T) {
+		sm := cfgmock.NewService(cfgmock.PathValue{
+			basePath.BindWebsite(1).String(): "b",
+		})
+		have, err := config.NewScoped(sm, 1, 0, 2).Values(basePath.Route)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, map[string][]byte{"groups/2/aa/bb/cc": []byte("b")}, have)
+	})
+	t.Run("restricted to website", func(t *testing.T) {
+		sm := cfgmock.NewService(cfgmock.PathValue{
+			basePath.BindWebsite(1).String(): "b",
+			basePath.BindGroup(2).String():   "g",
+		})
+		have, err := config.NewScoped(sm, 1, 3, 2).String(basePath.Route, scope.Website)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "b", have)
+	})
+}
+
 func TestScopedService_Parent(t *testing.T) {
 	tests := []struct {
 		sg               config.Scoped
@@ -262,6 +590,8 @@ func TestScopedService_Parent(t *testing.T) {
 		{config.NewScoped(nil, 33, 1), scope.Store, 1, scope.Website, 33},
 		{config.NewScoped(nil, 3, 0), scope.Website, 3, scope.Default, 0},
 		{config.NewScoped(nil, 0, 0), scope.Default, 0, scope.Default, 0},
+		{config.NewScoped(nil, 33, 1, 5), scope.Store, 1, scope.Group, 5},
+		{config.NewScoped(nil, 33, 0, 5), scope.Group, 5, scope.Website, 33},
 	}
 	for _, test := range tests {
 		haveScp, haveID := test.sg.ParentID().Unpack()
@@ -0,0 +1,101 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/errors"
+)
+
+// ScopedSetter writes a configuration value to a route bound to a scope.
+type ScopedSetter interface {
+	Write(r cfgpath.Route, v interface{}, s ...scope.Type) error
+}
+
+// ScopedWriter is the writing counterpart of type Scoped. It binds a
+// cfgpath.Route to the scope of the WebsiteID and StoreID before writing the
+// value to the Root Writer. Empty storeID triggers the website scope. Empty
+// websiteID and empty storeID are triggering the default scope.
+type ScopedWriter struct {
+	// Root writes the values to the storage.
+	Root      Writer
+	WebsiteID int64
+	StoreID   int64
+}
+
+var _ ScopedSetter = (*ScopedWriter)(nil)
+
+// NewScopedWriter instantiates a ScopedSetter implementation. Writer specifies
+// the root Writer which does not know about any scope.
+func NewScopedWriter(w Writer, websiteID, storeID int64) ScopedWriter {
+	return ScopedWriter{
+		Root:      w,
+		WebsiteID: websiteID,
+		StoreID:   storeID,
+	}
+}
+
+// IsValid checks if the object has been set up correctly.
+func (sw ScopedWriter) IsValid() bool {
+	return sw.Root != nil && ((sw.WebsiteID == 0 && sw.StoreID == 0) ||
+		(sw.WebsiteID > 0 && sw.StoreID == 0) ||
+		(sw.WebsiteID > 0 && sw.StoreID > 0))
+}
+
+// ScopeID tells you the current underlying scope and its ID to which this
+// writer has been bound to.
+func (sw ScopedWriter) ScopeID() scope.TypeID {
+	return Scoped{WebsiteID: sw.WebsiteID, StoreID: sw.StoreID}.ScopeID()
+}
+
+// Write writes the value v to the route r bound to the scope of this writer.
+// The optional argument s overrides the bound scope, for example to write a
+// default value from a store bound writer. Writing to the website or store
+// scope without a WebsiteID or StoreID returns a NotValid error.
+func (sw ScopedWriter) Write(r cfgpath.Route, v interface{}, s ...scope.Type) error {
+	if !sw.IsValid() {
+		return errors.NewNotValidf("[config] ScopedWriter.Write: Invalid setup. WebsiteID %d StoreID %d. Route %q", sw.WebsiteID, sw.StoreID, r)
+	}
+
+	p, err := cfgpath.New(r)
+	if err != nil {
+		return errors.Wrapf(err, "[config] ScopedWriter.Write. Route %q", r)
+	}
+
+	scp := sw.ScopeID().Type()
+	if len(s) > 0 && s[0] > scope.Absent {
+		scp = s[0]
+	}
+
+	switch scp {
+	case scope.Store:
+		if sw.StoreID < 1 {
+			return errors.NewNotValidf("[config] ScopedWriter.Write: Store scope not allowed without a StoreID. Route %q", r)
+		}
+		p = p.BindStore(sw.StoreID)
+	case scope.Website:
+		if sw.WebsiteID < 1 {
+			return errors.NewNotValidf("[config] ScopedWriter.Write: Website scope not allowed without a WebsiteID. Route %q", r)
+		}
+		p = p.BindWebsite(sw.WebsiteID)
+	case scope.Default:
+		p.ScopeID = scope.DefaultTypeID
+	default:
+		return errors.NewNotSupportedf("[config] ScopedWriter.Write: Scope %s not supported. Route %q", scp, r)
+	}
+
+	return errors.Wrapf(sw.Root.Write(p, v), "[config] ScopedWriter.Write. Path %q", p)
+}
@@ -0,0 +1,81 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopedWriter_Write(t *testing.T) {
+
+	route := cfgpath.NewRoute("aa/bb/cc")
+	tests := []struct {
+		websiteID, storeID int64
+		perm               scope.Type
+		wantPath           string
+		wantErrBhf         errors.BehaviourFunc
+	}{
+		{0, 0, scope.Absent, "default/0/aa/bb/cc", nil},
+		{3, 0, scope.Absent, "websites/3/aa/bb/cc", nil},
+		{3, 5, scope.Absent, "stores/5/aa/bb/cc", nil},
+		{3, 5, scope.Website, "websites/3/aa/bb/cc", nil},
+		{3, 5, scope.Default, "default/0/aa/bb/cc", nil},
+		{3, 0, scope.Default, "default/0/aa/bb/cc", nil},
+		{3, 0, scope.Store, "", errors.IsNotValid},
+		{0, 0, scope.Website, "", errors.IsNotValid},
+		{0, 0, scope.Store, "", errors.IsNotValid},
+		{0, 5, scope.Absent, "", errors.IsNotValid},
+		{3, 5, scope.Group, "", errors.IsNotSupported},
+	}
+	for i, test := range tests {
+		mw := &cfgmock.Write{}
+		sw := config.NewScopedWriter(mw, test.websiteID, test.storeID)
+		haveErr := sw.Write(route, 4711, test.perm)
+		if test.wantErrBhf != nil {
+			assert.True(t, test.wantErrBhf(haveErr), "Index %d => %+v", i, haveErr)
+			assert.Empty(t, mw.ArgPath, "Index %d", i)
+			continue
+		}
+		assert.NoError(t, haveErr, "Index %d => %+v", i, haveErr)
+		assert.Exactly(t, test.wantPath, mw.ArgPath, "Index %d", i)
+		assert.Exactly(t, 4711, mw.ArgValue, "Index %d", i)
+	}
+}
+
+func TestScopedWriter_Service(t *testing.T) {
+	srv := config.MustNewService(config.NewInMemoryStore())
+	route := cfgpath.NewRoute("aa/bb/cc")
+
+	assert.NoError(t, srv.NewScopedWriter(1, 2).Write(route, "store 2"))
+	assert.NoError(t, srv.NewScopedWriter(1, 2).Write(route, "website 1", scope.Website))
+
+	have, err := srv.NewScoped(1, 2).String(route)
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "store 2", have)
+
+	have, err = srv.NewScoped(1, 0).String(route)
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "website 1", have)
+
+	sw := config.NewScopedWriter(srv, 0, 0)
+	assert.True(t, errors.IsNotValid(sw.Write(cfgpath.NewRoute("aa/bb"), 1)))
+}
@@ -27,14 +27,19 @@ type (
 		//SetValueId(valueId int) @todo
 		//GetValueId()@todo
 
-		//AfterLoad($object); object must be an interface @todo
-		//BeforeSave($object);
-		//AfterSave($object);
+		// AfterLoad converts the value loaded from the database into its
+		// output representation.
+		AfterLoad(value string) (string, error)
+		// BeforeSave converts the value into the representation stored in the
+		// database.
+		BeforeSave(value string) (string, error)
+		//AfterSave($object); object must be an interface @todo
 		//BeforeDelete($object);
 		//AfterDelete($object);
 
-		// Validate @todo
-		Validate( /*data object*/ ) bool
+		// Validate checks the value and returns a NotValid error if the value
+		// cannot be saved.
+		Validate(value string) error
 
 		//GetEntityValueId(entity *CSEntityType) hmmmm
 		//SetEntityValueId(entity *CSEntityType, valueId int) hmmmm
@@ -87,5 +92,13 @@ func (ab *AttributeBackend) IsStatic() bool           { return ab.a.IsStatic() }
 func (ab *AttributeBackend) GetTable() string         { return "" }
 func (ab *AttributeBackend) GetType() string          { return ab.a.BackendType() }
 func (ab *AttributeBackend) GetEntityIDField() string { return "" }
-func (ab *AttributeBackend) Validate() bool           { return true }
 func (ab *AttributeBackend) IsScalar() bool           { return true }
+
+// AfterLoad returns the value unchanged.
+func (ab *AttributeBackend) AfterLoad(value string) (string, error) { return value, nil }
+
+// BeforeSave returns the value unchanged.
+func (ab *AttributeBackend) BeforeSave(value string) (string, error) { return value, nil }
+
+// Validate accepts all values.
+func (ab *AttributeBackend) Validate(value string) error { return nil }
@@ -14,20 +14,152 @@
 
 package eav
 
-// AttributeBackendDatetime handles date times @todo
+import (
+	"time"
+
+	"github.com/corestoreio/errors"
+)
+
+// DatetimeFormat defines the format of a datetime value in the database.
+const DatetimeFormat = "2006-01-02 15:04:05"
+
+// datetimeInputFormats lists the accepted formats of an incoming datetime
+// value. Values with a time zone get converted to UTC.
+var datetimeInputFormats = [...]string{
+	DatetimeFormat,
+	"2006-01-02",
+	"2006-01-02 15:04",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+}
+
+var _ AttributeBackendModeller = (*BackendDatetime)(nil)
+
+// BackendDatetime handles date times. Use AttributeBackendDatetime to create
+// it.
+type BackendDatetime struct {
+	*AttributeBackend
+}
+
+// AttributeBackendDatetime handles date times. BeforeSave converts Magento
+// and RFC3339 formatted values into DatetimeFormat and AfterLoad formats the
+// stored values as RFC3339. Empty values are allowed.
 // @see magento2/site/app/code/Magento/Eav/Model/Entity/Attribute/Backend/Datetime.php
-func AttributeBackendDatetime() *AttributeBackend {
-	return NewAttributeBackend()
+func AttributeBackendDatetime() *BackendDatetime {
+	return &BackendDatetime{
+		AttributeBackend: NewAttributeBackend(),
+	}
+}
+
+// Config runs the configuration functions
+func (bd *BackendDatetime) Config(configs ...AttributeBackendConfig) AttributeBackendModeller {
+	bd.AttributeBackend.Config(configs...)
+	return bd
+}
+
+func parseDatetime(value string) (time.Time, error) {
+	for _, f := range datetimeInputFormats {
+		if t, err := time.Parse(f, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.NewNotValidf("[eav] AttributeBackendDatetime: Cannot parse datetime %q", value)
+}
+
+// Validate returns a NotValid error if value cannot be parsed.
+func (bd *BackendDatetime) Validate(value string) error {
+	if value == "" {
+		return nil
+	}
+	_, err := parseDatetime(value)
+	return err
+}
+
+// BeforeSave converts value into DatetimeFormat.
+func (bd *BackendDatetime) BeforeSave(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	t, err := parseDatetime(value)
+	if err != nil {
+		return "", errors.Wrap(err, "[eav] AttributeBackendDatetime.BeforeSave")
+	}
+	return t.Format(DatetimeFormat), nil
 }
 
-// AttributeBackendTimeCreated @todo
+// AfterLoad converts the stored value into RFC3339.
+func (bd *BackendDatetime) AfterLoad(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	t, err := parseDatetime(value)
+	if err != nil {
+		return "", errors.Wrap(err, "[eav] AttributeBackendDatetime.AfterLoad")
+	}
+	return t.Format(time.RFC3339), nil
+}
+
+var _ AttributeBackendModeller = (*BackendTimeCreated)(nil)
+var _ AttributeBackendModeller = (*BackendTimeUpdated)(nil)
+
+// BackendTimeCreated sets the creation time. Use AttributeBackendTimeCreated
+// to create it.
+type BackendTimeCreated struct {
+	*AttributeBackend
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// AttributeBackendTimeCreated sets the value to the current time in
+// DatetimeFormat when the entity gets saved for the first time, i.e. while
+// the value is empty.
 // @see magento2/site/app/code/Magento/Eav/Model/Entity/Attribute/Backend/Time/Created.php
-func AttributeBackendTimeCreated() *AttributeBackend {
-	return NewAttributeBackend()
+func AttributeBackendTimeCreated() *BackendTimeCreated {
+	return &BackendTimeCreated{
+		AttributeBackend: NewAttributeBackend(),
+		Now:              time.Now,
+	}
+}
+
+// Config runs the configuration functions
+func (bc *BackendTimeCreated) Config(configs ...AttributeBackendConfig) AttributeBackendModeller {
+	bc.AttributeBackend.Config(configs...)
+	return bc
 }
 
-// AttributeBackendTimeUpdated @todo
+// BeforeSave returns the current time if value is empty, otherwise value.
+func (bc *BackendTimeCreated) BeforeSave(value string) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+	return bc.Now().UTC().Format(DatetimeFormat), nil
+}
+
+// BackendTimeUpdated sets the update time. Use AttributeBackendTimeUpdated to
+// create it.
+type BackendTimeUpdated struct {
+	*AttributeBackend
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// AttributeBackendTimeUpdated sets the value to the current time in
+// DatetimeFormat each time the entity gets saved.
 // @see magento2/site/app/code/Magento/Eav/Model/Entity/Attribute/Backend/Time/Updated.php
-func AttributeBackendTimeUpdated() *AttributeBackend {
-	return NewAttributeBackend()
+func AttributeBackendTimeUpdated() *BackendTimeUpdated {
+	return &BackendTimeUpdated{
+		AttributeBackend: NewAttributeBackend(),
+		Now:              time.Now,
+	}
+}
+
+// Config runs the configuration functions
+func (bu *BackendTimeUpdated) Config(configs ...AttributeBackendConfig) AttributeBackendModeller {
+	bu.AttributeBackend.Config(configs...)
+	return bu
+}
+
+// BeforeSave returns the current time.
+func (bu *BackendTimeUpdated) BeforeSave(_ string) (string, error) {
+	return bu.Now().UTC().Format(DatetimeFormat), nil
 }
@@ -0,0 +1,124 @@
+// Copyright 2015-2017, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eav_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/eav"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributeBackendDatetime(t *testing.T) {
+	be := eav.AttributeBackendDatetime().Config(eav.AttributeBackendIdx(3))
+
+	tests := []struct {
+		value    string
+		wantSave string
+		wantLoad string
+	}{
+		{"", "", ""},
+		{"2016-09-21 14:35:03", "2016-09-21 14:35:03", "2016-09-21T14:35:03Z"},
+		{"2016-09-21", "2016-09-21 00:00:00", "2016-09-21T00:00:00Z"},
+		{"2016-09-21 14:35", "2016-09-21 14:35:00", "2016-09-21T14:35:00Z"},
+		{"2016-09-21T14:35:03Z", "2016-09-21 14:35:03", "2016-09-21T14:35:03Z"},
+		{"2016-09-21T16:35:03+02:00", "2016-09-21 14:35:03", "2016-09-21T14:35:03Z"},
+	}
+	for _, test := range tests {
+		assert.NoError(t, be.Validate(test.value), "Value %q", test.value)
+
+		haveSave, err := be.BeforeSave(test.value)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Exactly(t, test.wantSave, haveSave, "Value %q", test.value)
+
+		haveLoad, err := be.AfterLoad(haveSave)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Exactly(t, test.wantLoad, haveLoad, "Value %q", test.value)
+
+		// round trip
+		haveSave2, err := be.BeforeSave(haveLoad)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Exactly(t, haveSave, haveSave2, "Value %q", test.value)
+	}
+}
+
+func TestAttributeBackendDatetime_Invalid(t *testing.T) {
+	be := eav.AttributeBackendDatetime()
+
+	for _, value := range []string{"21.09.2016", "2016-13-01 00:00:00", "yesterday"} {
+		assert.True(t, errors.IsNotValid(be.Validate(value)), "Value %q", value)
+
+		have, err := be.BeforeSave(value)
+		assert.True(t, errors.IsNotValid(err), "Value %q: %+v", value, err)
+		assert.Empty(t, have)
+
+		have, err = be.AfterLoad(value)
+		assert.True(t, errors.IsNotValid(err), "Value %q: %+v", value, err)
+		assert.Empty(t, have)
+	}
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.now = fc.now.Add(time.Hour)
+	return fc.now
+}
+
+func TestAttributeBackendTimeCreated(t *testing.T) {
+	be := eav.AttributeBackendTimeCreated()
+	be.Now = (&fakeClock{now: time.Date(2016, 9, 21, 13, 0, 0, 0, time.UTC)}).Now
+
+	created, err := be.BeforeSave("")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, "2016-09-21 14:00:00", created)
+
+	// 2nd save keeps the created time
+	have, err := be.BeforeSave(created)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, created, have)
+}
+
+func TestAttributeBackendTimeUpdated(t *testing.T) {
+	// Now stays reachable after Config
+	be := eav.AttributeBackendTimeUpdated().Config(eav.AttributeBackendIdx(2)).(*eav.BackendTimeUpdated)
+	be.Now = (&fakeClock{now: time.Date(2016, 9, 21, 13, 0, 0, 0, time.UTC)}).Now
+
+	updated, err := be.BeforeSave("")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, "2016-09-21 14:00:00", updated)
+
+	updated, err = be.BeforeSave(updated)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, "2016-09-21 15:00:00", updated)
+}
@@ -15,6 +15,8 @@
 package eav
 
 import (
+	"context"
+
 	"github.com/corestoreio/csfw/storage/csdb"
 	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/util/slices"
@@ -27,7 +29,7 @@ import (
 // @see magento2/app/code/Magento/Eav/Model/Resource/Attribute/Collection.php::_initSelect()
 func GetAttributeSelectSql(dbrSess dbr.Session, aat EntityTypeAdditionalAttributeTabler, entityTypeID, websiteID int64) (*dbr.Select, error) {
 
-	ta, err := TableCollection.Structure(TableIndexAttribute)
+	ta, err := TableCollection.Table(TableIndexAttribute)
 	if err != nil {
 		return nil, errors.Wrap(err, "")
 	}
@@ -91,3 +93,20 @@ func GetAttributeSelectSql(dbrSess dbr.Session, aat EntityTypeAdditionalAttribut
 	}
 	return selectSql, nil
 }
+
+// LoadAttributes loads the full attribute configuration into dest, which must
+// be a pointer to a slice of pointers to structs. See GetAttributeSelectSql.
+func LoadAttributes(dbrSess dbr.Session, aat EntityTypeAdditionalAttributeTabler, entityTypeID, websiteID int64, dest interface{}) (int, error) {
+	return LoadAttributesContext(context.Background(), dbrSess, aat, entityTypeID, websiteID, dest)
+}
+
+// LoadAttributesContext same as LoadAttributes but the context cancels the
+// query.
+func LoadAttributesContext(ctx context.Context, dbrSess dbr.Session, aat EntityTypeAdditionalAttributeTabler, entityTypeID, websiteID int64, dest interface{}) (int, error) {
+	sel, err := GetAttributeSelectSql(dbrSess, aat, entityTypeID, websiteID)
+	if err != nil {
+		return 0, errors.Wrap(err, "[eav] LoadAttributesContext.GetAttributeSelectSql")
+	}
+	n, err := sel.LoadStructsContext(ctx, dest)
+	return n, errors.Wrapf(err, "[eav] LoadAttributesContext.LoadStructsContext. EntityTypeID %d WebsiteID %d", entityTypeID, websiteID)
+}
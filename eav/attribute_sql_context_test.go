@@ -0,0 +1,70 @@
+// Copyright 2015-2017, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eav_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corestoreio/csfw/eav"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type attrTables struct {
+	taa *csdb.Table
+}
+
+func (at attrTables) TableAdditionalAttribute() (*csdb.Table, error) { return at.taa, nil }
+func (at attrTables) TableEavWebsite() (*csdb.Table, error)          { return nil, nil }
+
+func TestLoadAttributesContext_Canceled(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	defer func(tc *csdb.Tables) { eav.TableCollection = tc }(eav.TableCollection)
+	eav.TableCollection = csdb.MustNewTables(csdb.WithTable(eav.TableIndexAttribute, "eav_attribute",
+		&csdb.Column{Field: "attribute_id", Key: "PRI"},
+		&csdb.Column{Field: "attribute_code"},
+	))
+	aat := attrTables{
+		taa: csdb.NewTable("customer_eav_attribute",
+			&csdb.Column{Field: "attribute_id", Key: "PRI"},
+			&csdb.Column{Field: "is_visible"},
+		),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var attrs []*struct {
+		AttributeID   int64  `db:"attribute_id"`
+		AttributeCode string `db:"attribute_code"`
+	}
+	// The canceled context aborts the load before the query reaches the
+	// database, hence no query expectation.
+	n, err := eav.LoadAttributesContext(ctx, *dbc.NewSession(), aat, 1, 4, &attrs)
+	assert.Exactly(t, context.Canceled, errors.Cause(err), "%+v", err)
+	assert.Exactly(t, 0, n)
+	assert.Empty(t, attrs)
+}
@@ -18,5 +18,5 @@ import "github.com/corestoreio/csfw/storage/csdb"
 
 var (
 	// TableCollection handles all tables and its columns. init() in generated Go file will set the value.
-	TableCollection csdb.Tables
+	TableCollection *csdb.Tables
 )
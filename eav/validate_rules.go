@@ -0,0 +1,93 @@
+// Copyright 2015-2017, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eav
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/corestoreio/csfw/util/php/phpserialize"
+	"github.com/corestoreio/errors"
+)
+
+// PHPUnserializeToJSON converts a PHP serialized string, like the column
+// validate_rules of table customer_eav_attribute, into JSON. Supported are
+// arrays, strings, integers, floats, booleans and null. Arrays with the keys
+// 0 to n-1 become JSON arrays, all other arrays become JSON objects. An empty
+// string returns nil. Malformed input returns a NotValid error and PHP
+// objects or references return a NotSupported error.
+func PHPUnserializeToJSON(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	pv, err := phpserialize.UnSerialize([]byte(s))
+	if err != nil {
+		return nil, errors.NewNotValid(err, "[eav] PHPUnserializeToJSON.UnSerialize")
+	}
+	v, err := phpValueToJSON(pv)
+	if err != nil {
+		return nil, errors.Wrap(err, "[eav] PHPUnserializeToJSON")
+	}
+	return json.Marshal(v)
+}
+
+func phpValueToJSON(pv phpserialize.PhpValue) (interface{}, error) {
+	switch v := pv.(type) {
+	case nil, bool, int, float64, string:
+		return v, nil
+	case phpserialize.PhpArray:
+		return phpArrayToJSON(v)
+	}
+	return nil, errors.NewNotSupportedf("[eav] Unsupported PHP value of type %T", pv)
+}
+
+func phpArrayToJSON(pa phpserialize.PhpArray) (interface{}, error) {
+	list := make([]interface{}, len(pa))
+	isList := true
+	for i := range list {
+		v, ok := pa[i]
+		if !ok {
+			isList = false
+			break
+		}
+		jv, err := phpValueToJSON(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[eav] Array index %d", i)
+		}
+		list[i] = jv
+	}
+	if isList {
+		return list, nil
+	}
+
+	obj := make(map[string]interface{}, len(pa))
+	for k, v := range pa {
+		var key string
+		switch kt := k.(type) {
+		case string:
+			key = kt
+		case int:
+			key = fmt.Sprintf("%d", kt)
+		default:
+			return nil, errors.NewNotSupportedf("[eav] Unsupported PHP array key of type %T", k)
+		}
+		jv, err := phpValueToJSON(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[eav] Array key %q", key)
+		}
+		obj[key] = jv
+	}
+	return obj, nil
+}
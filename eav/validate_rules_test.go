@@ -0,0 +1,53 @@
+// Copyright 2015-2017, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eav_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/eav"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPHPUnserializeToJSON(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    string
+		wantErr errors.BehaviourFunc
+	}{
+		{"", "", nil},
+		{`a:2:{s:15:"max_text_length";i:255;s:15:"min_text_length";i:1;}`, `{"max_text_length":255,"min_text_length":1}`, nil},
+		{`a:3:{s:16:"input_validation";s:5:"email";s:10:"date_range";a:2:{i:0;s:10:"2016-01-01";i:1;s:10:"2016-12-31";}s:8:"required";b:1;}`,
+			`{"date_range":["2016-01-01","2016-12-31"],"input_validation":"email","required":true}`, nil},
+		{`a:2:{i:1;d:0.5;i:3;N;}`, `{"1":0.5,"3":null}`, nil},
+		{`a:0:{}`, `[]`, nil},
+		{`a:2:{s:15:"max_text_length";i:255;s:15:"min_text_le`, "", errors.IsNotValid},
+		{`x:1;`, "", errors.IsNotValid},
+		{`a:1:{s:4:"user";O:8:"stdClass":0:{}}`, "", errors.IsNotSupported},
+	}
+	for i, test := range tests {
+		have, err := eav.PHPUnserializeToJSON(test.have)
+		if test.wantErr != nil {
+			assert.True(t, test.wantErr(err), "Index %d: %+v", i, err)
+			assert.Nil(t, have, "Index %d", i)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Index %d: %+v", i, err)
+		}
+		assert.Exactly(t, test.want, string(have), "Index %d", i)
+	}
+}
@@ -150,6 +150,36 @@ func TestDisallowedWildcardOrigin(t *testing.T) {
 	corstest.TestDisallowedWildcardOrigin(t, s, req)
 }
 
+func TestAllowedOrigin_PerWebsite(t *testing.T) {
+	s := newCorsService(cfgmock.PathValue{
+		backend.AllowedOrigins.MustFQWebsite(1): "http://euro.com",
+		backend.AllowedOrigins.MustFQWebsite(2): "http://*.oz.com",
+	})
+
+	tests := []struct {
+		websiteID  int64
+		storeID    int64
+		origin     string
+		wantOrigin string
+	}{
+		{1, 1, "http://euro.com", "http://euro.com"},
+		{1, 1, "http://shop.oz.com", ""},
+		{2, 5, "http://shop.oz.com", "http://shop.oz.com"},
+		{2, 5, "http://euro.com", ""},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", "https://corestore.io/catalog/product/id/33454", nil)
+		req = req.WithContext(scope.WithContext(req.Context(), test.websiteID, test.storeID))
+		req.Header.Set("Origin", test.origin)
+
+		rec := httptest.NewRecorder()
+		s.WithCORS(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(rec, req)
+		assert.Exactly(t, test.wantOrigin, rec.Header().Get("Access-Control-Allow-Origin"), "Index %d", i)
+	}
+}
+
 func TestAllowedOriginFunc(t *testing.T) {
 	s := newCorsService(cfgmock.PathValue{
 		backend.AllowOriginRegex.MustFQWebsite(2): "^http://foo",
@@ -16,9 +16,11 @@ package cors
 
 import (
 	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/errors"
 )
 
 // Settings general settings for the cors service. Those settings will be
@@ -29,8 +31,15 @@ type Settings struct {
 	// origins will be allowed. An origin may contain a wildcard (*) to replace
 	// 0 or more characters (i.e.: http://*.domain.com). Usage of wildcards
 	// implies a small performance penality. Only one wildcard can be used per
-	// origin. Default value is ["*"]. Normalized list of plain allowed origins.
+	// origin. An origin with the prefix "regexp:" gets compiled into a regular
+	// expression which must match the whole origin (i.e.:
+	// regexp:https://[a-z0-9-]+\.domain\.com). Exact
+	// origins get checked first, then regular expressions and then wildcards.
+	// Default value is ["*"]. Normalized list of plain allowed origins.
 	AllowedOrigins []string
+	// allowedROrigins a list of allowed origins as regular expressions. Used
+	// in ScopedConfig.isOriginAllowed()
+	allowedROrigins []*regexp.Regexp
 	// allowedWOrigins a list of allowed origins containing wildcards. Used in
 	// ScopedConfig.isOriginAllowed()
 	allowedWOrigins []wildcard
@@ -86,12 +95,15 @@ func WithDefaultConfig(h scope.TypeID) Option {
 // functions will optimize the internal structure of the Settings struct.
 func WithSettings(stng Settings, scopeIDs ...scope.TypeID) Option {
 	exposedHeaders := convert(stng.ExposedHeaders, http.CanonicalHeaderKey)
-	allowedOriginsAll, allowedOrigins, allowedWOrigins := convertAllowedOrigins(stng.AllowedOrigins...)
+	allowedOriginsAll, allowedOrigins, allowedROrigins, allowedWOrigins, errOrigins := convertAllowedOrigins(stng.AllowedOrigins...)
 	am := convert(stng.AllowedMethods, strings.ToUpper)
 
 	allowedHeadersAll, allowedHeaders := convertAllowedHeaders(stng.AllowedHeaders...)
 
 	return func(s *Service) error {
+		if errOrigins != nil {
+			return errors.Wrap(errOrigins, "[cors] WithSettings.convertAllowedOrigins")
+		}
 		sc := s.findScopedConfig(scopeIDs...)
 
 		sc.ExposedHeaders = exposedHeaders
@@ -103,6 +115,7 @@ func WithSettings(stng Settings, scopeIDs ...scope.TypeID) Option {
 		if len(allowedOrigins) > 0 {
 			sc.AllowedOrigins = allowedOrigins
 		}
+		sc.allowedROrigins = allowedROrigins
 		if len(allowedWOrigins) > 0 {
 			sc.allowedWOrigins = allowedWOrigins
 		}
@@ -128,7 +141,10 @@ func WithSettings(stng Settings, scopeIDs ...scope.TypeID) Option {
 	}
 }
 
-func convertAllowedOrigins(domains ...string) (allowedOriginsAll bool, allowedOrigins []string, allowedWOrigins []wildcard) {
+// regexpOriginPrefix marks an allowed origin as a regular expression.
+const regexpOriginPrefix = "regexp:"
+
+func convertAllowedOrigins(domains ...string) (allowedOriginsAll bool, allowedOrigins []string, allowedROrigins []*regexp.Regexp, allowedWOrigins []wildcard, err error) {
 	if len(domains) == 0 {
 		// Default is all origins
 		allowedOriginsAll = true
@@ -136,12 +152,24 @@ func convertAllowedOrigins(domains ...string) (allowedOriginsAll bool, allowedOr
 	}
 
 	for _, origin := range domains {
+		if strings.HasPrefix(origin, regexpOriginPrefix) {
+			// anchor the pattern to match the whole origin, otherwise
+			// https://x.example.com.evil.io matches https://.*\.example\.com
+			r, errC := regexp.Compile("^(?:" + origin[len(regexpOriginPrefix):] + ")$")
+			if errC != nil {
+				err = errors.NewNotValidf("[cors] Allowed origin %q: %s", origin, errC)
+				return
+			}
+			allowedROrigins = append(allowedROrigins, r)
+			continue
+		}
 		// Normalize
 		origin = strings.ToLower(origin)
 		if origin == "*" {
 			// If "*" is present in the list, turn the whole list into a match all
 			allowedOriginsAll = true
 			allowedOrigins = nil
+			allowedROrigins = nil
 			allowedWOrigins = nil
 			return
 		} else if i := strings.IndexByte(origin, '*'); i >= 0 {
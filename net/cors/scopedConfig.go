@@ -218,6 +218,11 @@ func (sc *ScopedConfig) isOriginAllowed(origin string) bool {
 			return true
 		}
 	}
+	for _, r := range sc.allowedROrigins {
+		if r.MatchString(origin) {
+			return true
+		}
+	}
 	for _, w := range sc.allowedWOrigins {
 		if w.match(origin) {
 			return true
@@ -253,6 +253,78 @@ func TestDisallowedWildcardOrigin(t *testing.T) {
 	corstest.TestDisallowedWildcardOrigin(t, s, req)
 }
 
+func TestRegexOrigin(t *testing.T) {
+	s := getBaseCorsService(
+		cors.WithSettings(cors.Settings{AllowedOrigins: []string{
+			"http://exact.com",
+			`regexp:^https://[a-z0-9-]+\.example\.com$`,
+			"http://*.bar.com",
+		}}),
+	)
+	tests := []struct {
+		origin     string
+		wantOrigin string
+	}{
+		{"http://exact.com", "http://exact.com"},
+		{"https://shop.example.com", "https://shop.example.com"},
+		{"https://shop-2.example.com", "https://shop-2.example.com"},
+		{"https://example.com", ""},
+		{"https://shop.example.com.evil.io", ""},
+		{"https://a.b.example.com", ""},
+		{"http://foo.bar.com", "http://foo.bar.com"},
+	}
+	for _, test := range tests {
+		req := reqWithStore("GET")
+		req.Header.Set("Origin", test.origin)
+		rec := httptest.NewRecorder()
+		s.WithCORS(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(rec, req)
+		assert.Exactly(t, test.wantOrigin, rec.Header().Get("Access-Control-Allow-Origin"), "Origin %q", test.origin)
+	}
+}
+
+func TestRegexOrigin_Unanchored(t *testing.T) {
+	s := getBaseCorsService(
+		cors.WithSettings(cors.Settings{AllowedOrigins: []string{
+			`regexp:https://.*\.example\.com`,
+		}}),
+	)
+	tests := []struct {
+		origin     string
+		wantOrigin string
+	}{
+		{"https://x.example.com", "https://x.example.com"},
+		{"https://x.example.com.evil.io", ""},
+		{"evil://https://x.example.com", ""},
+	}
+	for _, test := range tests {
+		req := reqWithStore("GET")
+		req.Header.Set("Origin", test.origin)
+		rec := httptest.NewRecorder()
+		s.WithCORS(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(rec, req)
+		assert.Exactly(t, test.wantOrigin, rec.Header().Get("Access-Control-Allow-Origin"), "Origin %q", test.origin)
+	}
+
+	// applying the settings again without a regular expression removes it
+	assert.NoError(t, s.Options(cors.WithSettings(cors.Settings{AllowedOrigins: []string{"http://exact.com"}})))
+	req := reqWithStore("GET")
+	req.Header.Set("Origin", "https://x.example.com")
+	rec := httptest.NewRecorder()
+	s.WithCORS(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(rec, req)
+	assert.Exactly(t, "", rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestRegexOrigin_Invalid(t *testing.T) {
+	s := cors.MustNew(cors.WithRootConfig(cfgmock.NewService()))
+	err := s.Options(cors.WithSettings(cors.Settings{AllowedOrigins: []string{"regexp:[a-z+"}}))
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
 func TestAllowedOriginFunc(t *testing.T) {
 	r, _ := regexp.Compile("^http://foo")
 	s := getBaseCorsService(
@@ -47,11 +47,33 @@ type Configuration struct {
 	// Path: net/jwt/single_usage
 	SingleTokenUsage cfgmodel.Bool
 
+	// RefreshWindow defines the duration before the expiration of a token in
+	// which a new token gets issued.
+	// Path: net/jwt/refresh_window
+	RefreshWindow cfgmodel.Duration
+
 	// HmacPassword handles the password. Will panic if you
 	// do not set the cfgmodel.Encryptor
 	// Path: net/jwt/hmac_password
 	HmacPassword cfgmodel.Obscure
 
+	// KeyID identifies the current key and gets written into the kid header
+	// of a new token. Enables the key rotation.
+	// Path: net/jwt/key_id
+	KeyID cfgmodel.Str
+
+	// KeyIDPrevious identifies the previous HMAC password, RSA or ECDSA key
+	// of the current signing method. Tokens with this key ID in their kid
+	// header stay valid during the key rotation.
+	// Path: net/jwt/key_id_previous
+	KeyIDPrevious cfgmodel.Str
+
+	// HmacPasswordPrevious handles the previous password which verifies the
+	// tokens of KeyIDPrevious. Will panic if you do not set the
+	// cfgmodel.Encryptor
+	// Path: net/jwt/hmac_password_previous
+	HmacPasswordPrevious cfgmodel.Obscure
+
 	// HmacPasswordPerUser if enable each logged in user will have their own
 	// randomly generated password.
 	// TODO(cs) think and implement. we also may need a map to map a user to his/her password and a 2nd field in config which defines the claim key for the username.
@@ -68,6 +90,17 @@ type Configuration struct {
 	// Path: net/jwt/rsa_key_password
 	RSAKeyPassword cfgmodel.Obscure
 
+	// RSAKeyPrevious handles the previous RSA private key which verifies the
+	// tokens of KeyIDPrevious. Will panic if you do not set the
+	// cfgmodel.Encryptor
+	// Path: net/jwt/rsa_key_previous
+	RSAKeyPrevious cfgmodel.Obscure
+
+	// RSAKeyPasswordPrevious handles the password for the previous RSA
+	// private key. Will panic if you do not set the cfgmodel.Encryptor
+	// Path: net/jwt/rsa_key_password_previous
+	RSAKeyPasswordPrevious cfgmodel.Obscure
+
 	// ECDSAKey handles the ECDSA private key. Will panic if you
 	// do not set the cfgmodel.Encryptor
 	// Path: net/jwt/ecdsa_key
@@ -77,6 +110,17 @@ type Configuration struct {
 	// Will panic if you do not set the cfgmodel.Encryptor
 	// Path: net/jwt/ecdsa_key_password
 	ECDSAKeyPassword cfgmodel.Obscure
+
+	// ECDSAKeyPrevious handles the previous ECDSA private key which verifies
+	// the tokens of KeyIDPrevious. Will panic if you do not set the
+	// cfgmodel.Encryptor
+	// Path: net/jwt/ecdsa_key_previous
+	ECDSAKeyPrevious cfgmodel.Obscure
+
+	// ECDSAKeyPasswordPrevious handles the password for the previous ECDSA
+	// private key. Will panic if you do not set the cfgmodel.Encryptor
+	// Path: net/jwt/ecdsa_key_password_previous
+	ECDSAKeyPasswordPrevious cfgmodel.Obscure
 }
 
 // New initializes the backend configuration models containing the cfgpath.Route
@@ -94,12 +138,20 @@ func New(cfgStruct element.SectionSlice, opts ...cfgmodel.Option) *Configuration
 	be.Expiration = cfgmodel.NewDuration(`net/jwt/expiration`, opts...)
 	be.Skew = cfgmodel.NewDuration(`net/jwt/skew`, opts...)
 	be.SingleTokenUsage = cfgmodel.NewBool(`net/jwt/single_usage`, append(opts, cfgmodel.WithSource(cfgsource.EnableDisable))...)
+	be.RefreshWindow = cfgmodel.NewDuration(`net/jwt/refresh_window`, opts...)
 	be.HmacPassword = cfgmodel.NewObscure(`net/jwt/hmac_password`, opts...)
+	be.KeyID = cfgmodel.NewStr(`net/jwt/key_id`, opts...)
+	be.KeyIDPrevious = cfgmodel.NewStr(`net/jwt/key_id_previous`, opts...)
+	be.HmacPasswordPrevious = cfgmodel.NewObscure(`net/jwt/hmac_password_previous`, opts...)
 	be.HmacPasswordPerUser = cfgmodel.NewBool(`net/jwt/hmac_password_per_user`, append(opts, cfgmodel.WithSource(cfgsource.EnableDisable))...)
 	be.RSAKey = cfgmodel.NewObscure(`net/jwt/rsa_key`, opts...)
 	be.RSAKeyPassword = cfgmodel.NewObscure(`net/jwt/rsa_key_password`, opts...)
+	be.RSAKeyPrevious = cfgmodel.NewObscure(`net/jwt/rsa_key_previous`, opts...)
+	be.RSAKeyPasswordPrevious = cfgmodel.NewObscure(`net/jwt/rsa_key_password_previous`, opts...)
 	be.ECDSAKey = cfgmodel.NewObscure(`net/jwt/ecdsa_key`, opts...)
 	be.ECDSAKeyPassword = cfgmodel.NewObscure(`net/jwt/ecdsa_key_password`, opts...)
+	be.ECDSAKeyPrevious = cfgmodel.NewObscure(`net/jwt/ecdsa_key_previous`, opts...)
+	be.ECDSAKeyPasswordPrevious = cfgmodel.NewObscure(`net/jwt/ecdsa_key_password_previous`, opts...)
 
 	return be
 }
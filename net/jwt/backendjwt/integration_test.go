@@ -17,8 +17,10 @@ package backendjwt_test
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -87,6 +89,8 @@ func TestServiceWithBackend_HMACSHA_Website(t *testing.T) {
 		pb.Skew.MustFQ():         "4m",
 		pb.Skew.MustFQWebsite(1): "6m1s",
 
+		pb.RefreshWindow.MustFQWebsite(1): "3m",
+
 		pb.HmacPassword.MustFQ():         "pw1",
 		pb.HmacPassword.MustFQWebsite(1): "pw2",
 	})
@@ -104,6 +108,7 @@ func TestServiceWithBackend_HMACSHA_Website(t *testing.T) {
 	assert.False(t, scNew.Disabled, "Disabled")
 	assert.Exactly(t, "5m1s", scNew.Expire.String(), "Expire")
 	assert.Exactly(t, "6m1s", scNew.Skew.String(), "Skew")
+	assert.Exactly(t, "3m0s", scNew.RefreshWindow.String(), "RefreshWindow")
 	assert.Exactly(t, "HS512", scNew.SigningMethod.Alg(), "SigningMethod")
 	assert.False(t, scNew.Key.IsEmpty())
 	assert.NotNil(t, scNew.ErrorHandler)
@@ -258,6 +263,184 @@ func TestServiceWithBackend_RSAFail(t *testing.T) {
 	assert.True(t, errors.IsNotSupported(err))
 }
 
+func TestServiceWithBackend_SigningMethodPerWebsite(t *testing.T) {
+
+	rsaKey, err := ioutil.ReadFile(filepath.Join("..", "..", "..", "util", "csjwt", "test", "test_rsa_np"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	jwts, pb := getJwts(
+		cfgmodel.WithEncrypter(noopCrypt{}),
+		cfgmodel.WithDecrypter(noopCrypt{}),
+	)
+
+	cfgSrv := cfgmock.NewService(cfgmock.PathValue{
+		pb.SigningMethod.MustFQWebsite(1): "HS256",
+		pb.HmacPassword.MustFQWebsite(1):  "pw1",
+		pb.SigningMethod.MustFQWebsite(2): "RS256",
+		pb.RSAKey.MustFQWebsite(2):        rsaKey,
+	})
+
+	sc1, err := jwts.ConfigByScopedGetter(cfgSrv.NewScoped(1, 0))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	sc2, err := jwts.ConfigByScopedGetter(cfgSrv.NewScoped(2, 0))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, csjwt.HS256, sc1.SigningMethod.Alg())
+	assert.Exactly(t, csjwt.RS256, sc2.SigningMethod.Alg())
+
+	tk1, err := jwts.NewToken(scope.Website.Pack(1), jwtclaim.Map{"xfoo": "bar"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	tk2, err := jwts.NewToken(scope.Website.Pack(2), jwtclaim.Map{"xfoo": "bar"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// each website validates its own token
+	_, err = jwts.ParseScoped(scope.Website.Pack(1), tk1.Raw)
+	assert.NoError(t, err, "%+v", err)
+	_, err = jwts.ParseScoped(scope.Website.Pack(2), tk2.Raw)
+	assert.NoError(t, err, "%+v", err)
+
+	// cross validation must fail
+	_, err = jwts.ParseScoped(scope.Website.Pack(2), tk1.Raw)
+	assert.Error(t, err, "HS256 token must not validate in the RS256 website")
+	_, err = jwts.ParseScoped(scope.Website.Pack(1), tk2.Raw)
+	assert.Error(t, err, "RS256 token must not validate in the HS256 website")
+
+	// same with a request
+	req := httptest.NewRequest("GET", "http://corestore.io/customer/account", nil)
+	jwt.SetHeaderAuthorization(req, tk1.Raw)
+	_, err = sc2.ParseFromRequest(jwts.Blacklist, req)
+	assert.Error(t, err, "HS256 token must not validate in the RS256 website")
+	_, err = sc1.ParseFromRequest(jwts.Blacklist, req)
+	assert.NoError(t, err, "%+v", err)
+}
+
+func TestServiceWithBackend_KeyRotation(t *testing.T) {
+
+	newToken := func(jwts *jwt.Service, pb *backendjwt.Configuration, pv cfgmock.PathValue) csjwt.Token {
+		sc, err := jwts.ConfigByScopedGetter(cfgmock.NewService(pv).NewScoped(1, 0))
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Exactly(t, csjwt.HS384, sc.SigningMethod.Alg())
+		tk, err := jwts.NewToken(scope.Website.Pack(1), jwtclaim.Map{"xfoo": "bar"})
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		return tk
+	}
+
+	jwtsOld, pbOld := getJwts(cfgmodel.WithEncrypter(noopCrypt{}), cfgmodel.WithDecrypter(noopCrypt{}))
+	tkOld := newToken(jwtsOld, pbOld, cfgmock.PathValue{
+		pbOld.SigningMethod.MustFQWebsite(1): "HS384",
+		pbOld.HmacPassword.MustFQWebsite(1):  "pwOld",
+		pbOld.KeyID.MustFQWebsite(1):         "k1",
+	})
+
+	jwtsOther, pbOther := getJwts(cfgmodel.WithEncrypter(noopCrypt{}), cfgmodel.WithDecrypter(noopCrypt{}))
+	tkOther := newToken(jwtsOther, pbOther, cfgmock.PathValue{
+		pbOther.SigningMethod.MustFQWebsite(1): "HS384",
+		pbOther.HmacPassword.MustFQWebsite(1):  "pwOld",
+		pbOther.KeyID.MustFQWebsite(1):         "k0",
+	})
+
+	jwts, pb := getJwts(cfgmodel.WithEncrypter(noopCrypt{}), cfgmodel.WithDecrypter(noopCrypt{}))
+	tkNew := newToken(jwts, pb, cfgmock.PathValue{
+		pb.SigningMethod.MustFQWebsite(1):        "HS384",
+		pb.HmacPassword.MustFQWebsite(1):         "pwNew",
+		pb.KeyID.MustFQWebsite(1):                "k2",
+		pb.KeyIDPrevious.MustFQWebsite(1):        "k1",
+		pb.HmacPasswordPrevious.MustFQWebsite(1): "pwOld",
+	})
+	kid, err := tkNew.Header.Get(jwtclaim.HeaderKid)
+	assert.NoError(t, err)
+	assert.Exactly(t, "k2", kid)
+
+	// token of the previous key and of the current key are valid
+	for i, raw := range [][]byte{tkOld.Raw, tkNew.Raw} {
+		tk, err := jwts.ParseScoped(scope.Website.Pack(1), raw)
+		assert.NoError(t, err, "Index %d => %+v", i, err)
+		assert.True(t, tk.Valid, "Index %d", i)
+	}
+
+	// unknown kid gets rejected although the password matches
+	_, err = jwts.ParseScoped(scope.Website.Pack(1), tkOther.Raw)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+func TestServiceWithBackend_KeyRotation_RSA(t *testing.T) {
+
+	readKey := func(name string) []byte {
+		k, err := ioutil.ReadFile(filepath.Join("..", "..", "..", "util", "csjwt", "test", name))
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		return k
+	}
+	keyOld := readKey("sample_key")
+	keyNew := readKey("test_rsa_np")
+
+	newService := func(pv func(pb *backendjwt.Configuration) cfgmock.PathValue) *jwt.Service {
+		jwts, pb := getJwts(cfgmodel.WithEncrypter(noopCrypt{}), cfgmodel.WithDecrypter(noopCrypt{}))
+		sc, err := jwts.ConfigByScopedGetter(cfgmock.NewService(pv(pb)).NewScoped(1, 0))
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Exactly(t, csjwt.RS384, sc.SigningMethod.Alg())
+		return jwts
+	}
+
+	jwtsOld := newService(func(pb *backendjwt.Configuration) cfgmock.PathValue {
+		return cfgmock.PathValue{
+			pb.SigningMethod.MustFQWebsite(1): "RS384",
+			pb.RSAKey.MustFQWebsite(1):        keyOld,
+			pb.KeyID.MustFQWebsite(1):         "k1",
+		}
+	})
+	tkOld, err := jwtsOld.NewToken(scope.Website.Pack(1), jwtclaim.Map{"xfoo": "bar"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	jwts := newService(func(pb *backendjwt.Configuration) cfgmock.PathValue {
+		return cfgmock.PathValue{
+			pb.SigningMethod.MustFQWebsite(1):  "RS384",
+			pb.RSAKey.MustFQWebsite(1):         keyNew,
+			pb.KeyID.MustFQWebsite(1):          "k2",
+			pb.KeyIDPrevious.MustFQWebsite(1):  "k1",
+			pb.RSAKeyPrevious.MustFQWebsite(1): keyOld,
+		}
+	})
+	tkNew, err := jwts.NewToken(scope.Website.Pack(1), jwtclaim.Map{"xfoo": "bar"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	for i, raw := range [][]byte{tkOld.Raw, tkNew.Raw} {
+		tk, err := jwts.ParseScoped(scope.Website.Pack(1), raw)
+		assert.NoError(t, err, "Index %d => %+v", i, err)
+		assert.True(t, tk.Valid, "Index %d", i)
+	}
+
+	// a missing previous key gets reported
+	jwtsMissing, pb := getJwts(cfgmodel.WithEncrypter(noopCrypt{}), cfgmodel.WithDecrypter(noopCrypt{}))
+	_, err = jwtsMissing.ConfigByScopedGetter(cfgmock.NewService(cfgmock.PathValue{
+		pb.SigningMethod.MustFQWebsite(1): "ES256",
+		pb.ECDSAKey.MustFQWebsite(1):      readKey("ec256-private.pem"),
+		pb.KeyID.MustFQWebsite(1):         "k2",
+		pb.KeyIDPrevious.MustFQWebsite(1): "k1",
+	}).NewScoped(1, 0))
+	assert.True(t, errors.IsEmpty(err), "%+v", err)
+}
+
 // TestServiceWithBackend_WithRunMode_Valid_Request tests that a request
 // contains a valid token, loads atomically the backend configuration and
 // switches the stores
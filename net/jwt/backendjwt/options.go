@@ -28,7 +28,7 @@ import (
 func (be *Configuration) PrepareOptionFactory() jwt.OptionFactoryFunc {
 	return func(sg config.Scoped) []jwt.Option {
 		var (
-			opts [7]jwt.Option
+			opts [8]jwt.Option
 			i    int // used as index in opts
 		)
 
@@ -63,6 +63,13 @@ func (be *Configuration) PrepareOptionFactory() jwt.OptionFactoryFunc {
 		opts[i] = jwt.WithSingleTokenUsage(isSU, sg.ScopeIDs()...)
 		i++
 
+		rw, err := be.RefreshWindow.Get(sg)
+		if err != nil {
+			return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtRefreshWindow.Get"))
+		}
+		opts[i] = jwt.WithRefreshWindow(rw, sg.ScopeIDs()...)
+		i++
+
 		// todo: avoid the next code and use OptionFactories to apply a signing method. Example in ratelimit package.
 
 		signingMethod, err := be.SigningMethod.Get(sg)
@@ -109,9 +116,22 @@ func (be *Configuration) PrepareOptionFactory() jwt.OptionFactoryFunc {
 			return jwt.OptionsError(errors.Errorf("[jwt] Unknown signing method: %q", signingMethod.Alg()))
 		}
 
+		keyID, err := be.KeyID.Get(sg)
+		if err != nil {
+			return jwt.OptionsError(errors.Wrap(err, "[backendjwt] NetJwtKeyID.Get"))
+		}
+
 		// WithSigningMethod must be added at the end of the slice to overwrite
 		// default signing methods
-		opts[i] = jwt.WithKey(key, sg.ScopeIDs()...)
+		if keyID == "" {
+			opts[i] = jwt.WithKey(key, sg.ScopeIDs()...)
+		} else {
+			keys, err := be.keySet(sg, signingMethod, keyID, key)
+			if err != nil {
+				return jwt.OptionsError(errors.Wrap(err, "[backendjwt] keySet"))
+			}
+			opts[i] = jwt.WithKeySet(keyID, keys, sg.ScopeIDs()...)
+		}
 		i++
 		opts[i] = jwt.WithSigningMethod(signingMethod, sg.ScopeIDs()...)
 		i++
@@ -119,3 +139,63 @@ func (be *Configuration) PrepareOptionFactory() jwt.OptionFactoryFunc {
 		return opts[:]
 	}
 }
+
+// keySet creates the key set for the key rotation. The previous key gets
+// loaded depending on the algorithm of the signing method.
+func (be *Configuration) keySet(sg config.Scoped, sm csjwt.Signer, keyID string, key csjwt.Key) (map[string]csjwt.Key, error) {
+	keys := map[string]csjwt.Key{keyID: key}
+
+	prevID, err := be.KeyIDPrevious.Get(sg)
+	if err != nil {
+		return nil, errors.Wrap(err, "[backendjwt] NetJwtKeyIDPrevious.Get")
+	}
+	if prevID == "" || prevID == keyID {
+		return keys, nil
+	}
+
+	var prevKey csjwt.Key
+	switch sm.Alg() {
+	case csjwt.RS256, csjwt.RS384, csjwt.RS512:
+		rsaKey, err := be.RSAKeyPrevious.Get(sg)
+		if err != nil {
+			return nil, errors.Wrap(err, "[backendjwt] NetJwtRSAKeyPrevious.Get")
+		}
+		if len(rsaKey) == 0 {
+			return nil, errors.NewEmptyf("[backendjwt] Previous RSA key for key ID %q is empty", prevID)
+		}
+		rsaPW, err := be.RSAKeyPasswordPrevious.Get(sg)
+		if err != nil {
+			return nil, errors.Wrap(err, "[backendjwt] NetJwtRSAKeyPasswordPrevious.Get")
+		}
+		prevKey = csjwt.WithRSAPrivateKeyFromPEM(rsaKey, rsaPW)
+	case csjwt.ES256, csjwt.ES384, csjwt.ES512:
+		ecdsaKey, err := be.ECDSAKeyPrevious.Get(sg)
+		if err != nil {
+			return nil, errors.Wrap(err, "[backendjwt] NetJwtECDSAKeyPrevious.Get")
+		}
+		if len(ecdsaKey) == 0 {
+			return nil, errors.NewEmptyf("[backendjwt] Previous ECDSA key for key ID %q is empty", prevID)
+		}
+		ecdsaPW, err := be.ECDSAKeyPasswordPrevious.Get(sg)
+		if err != nil {
+			return nil, errors.Wrap(err, "[backendjwt] NetJwtECDSAKeyPasswordPrevious.Get")
+		}
+		prevKey = csjwt.WithECPrivateKeyFromPEM(ecdsaKey, ecdsaPW)
+	case csjwt.HS256, csjwt.HS384, csjwt.HS512:
+		prevPW, err := be.HmacPasswordPrevious.Get(sg)
+		if err != nil {
+			return nil, errors.Wrap(err, "[backendjwt] NetJwtHmacPasswordPrevious.Get")
+		}
+		if len(prevPW) == 0 {
+			return nil, errors.NewEmptyf("[backendjwt] Previous HMAC password for key ID %q is empty", prevID)
+		}
+		prevKey = csjwt.WithPassword(prevPW)
+	default:
+		return nil, errors.NewNotSupportedf("[backendjwt] Key rotation not supported for signing method %q", sm.Alg())
+	}
+	if prevKey.Error != nil {
+		return nil, errors.Wrapf(prevKey.Error, "[backendjwt] Previous key for key ID %q", prevID)
+	}
+	keys[prevID] = prevKey
+	return keys, nil
+}
@@ -81,6 +81,16 @@ func NewConfigStructure() (element.SectionSlice, error) {
 							Scopes:    scope.PermWebsite,
 							Default:   `false`,
 						},
+						element.Field{
+							// Path: net/jwt/refresh_window
+							ID:        cfgpath.NewRoute("refresh_window"),
+							Label:     text.Chars(`Token Refresh Window`),
+							Comment:   text.Chars(`A valid token which expires within this duration gets replaced by a new token in the response. Per second (s), minute (i), hour (h) or day (d). Empty or zero disables the refresh.`),
+							Type:      element.TypeText,
+							SortOrder: 32,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+						},
 						element.Field{
 							// Path: net/jwt/signing_method
 							ID:        cfgpath.NewRoute("signing_method"),
@@ -100,6 +110,35 @@ func NewConfigStructure() (element.SectionSlice, error) {
 							Visible:   element.VisibleYes,
 							Scopes:    scope.PermWebsite,
 						},
+						element.Field{
+							// Path: net/jwt/key_id
+							ID:        cfgpath.NewRoute("key_id"),
+							Label:     text.Chars(`Key ID`),
+							Comment:   text.Chars(`Identifies the current key in the kid header of a token. If empty, key rotation is disabled.`),
+							Type:      element.TypeText,
+							SortOrder: 41,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+						},
+						element.Field{
+							// Path: net/jwt/key_id_previous
+							ID:        cfgpath.NewRoute("key_id_previous"),
+							Label:     text.Chars(`Previous Key ID`),
+							Comment:   text.Chars(`Tokens with this key ID stay valid and get verified with the previous HMAC password, RSA or ECDSA key.`),
+							Type:      element.TypeText,
+							SortOrder: 42,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+						},
+						element.Field{
+							// Path: net/jwt/hmac_password_previous
+							ID:        cfgpath.NewRoute("hmac_password_previous"),
+							Label:     text.Chars(`Previous HMAC Token Password`),
+							Type:      element.TypeObscure,
+							SortOrder: 43,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+						},
 						element.Field{
 							// Path: net/jwt/hmac_password_per_user
 							ID:        cfgpath.NewRoute("hmac_password_per_user"),
@@ -130,6 +169,25 @@ func NewConfigStructure() (element.SectionSlice, error) {
 							Visible:   element.VisibleYes,
 							Scopes:    scope.PermWebsite,
 						},
+						element.Field{
+							// Path: net/jwt/rsa_key_previous
+							ID:        cfgpath.NewRoute("rsa_key_previous"),
+							Label:     text.Chars(`Previous Private RSA Key`),
+							Comment:   text.Chars(`Verifies the tokens of the previous key ID.`),
+							Type:      element.TypeObscure,
+							SortOrder: 61,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+						},
+						element.Field{
+							// Path: net/jwt/rsa_key_password_previous
+							ID:        cfgpath.NewRoute("rsa_key_password_previous"),
+							Label:     text.Chars(`Previous Private RSA Key Password`),
+							Type:      element.TypeObscure,
+							SortOrder: 62,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+						},
 						element.Field{
 							// Path: net/jwt/ecdsa_key
 							ID:        cfgpath.NewRoute("ecdsa_key"),
@@ -150,6 +208,25 @@ func NewConfigStructure() (element.SectionSlice, error) {
 							Visible:   element.VisibleYes,
 							Scopes:    scope.PermWebsite,
 						},
+						element.Field{
+							// Path: net/jwt/ecdsa_key_previous
+							ID:        cfgpath.NewRoute("ecdsa_key_previous"),
+							Label:     text.Chars(`Previous Private ECDSA Key`),
+							Comment:   text.Chars(`Verifies the tokens of the previous key ID.`),
+							Type:      element.TypeObscure,
+							SortOrder: 81,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+						},
+						element.Field{
+							// Path: net/jwt/ecdsa_key_password_previous
+							ID:        cfgpath.NewRoute("ecdsa_key_password_previous"),
+							Label:     text.Chars(`Previous Private ECDSA Key Password`),
+							Type:      element.TypeObscure,
+							SortOrder: 82,
+							Visible:   element.VisibleYes,
+							Scopes:    scope.PermWebsite,
+						},
 					),
 				},
 			),
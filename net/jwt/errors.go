@@ -23,6 +23,7 @@ const (
 	errUnknownSigningMethod            = "[jwt] Unknown signing method - Have: %q Want: %q"
 	errUnknownSigningMethodOptions     = "[jwt] Unknown signing method - Have: %q Want: ES, HS or RS"
 	errKeyEmpty                        = "[jwt] Provided key argument is empty"
+	errKeyIDNotFound                   = "[jwt] Key ID %q not found in key set"
 
 	// ErrTokenBlacklisted returned by the middleware if the token can be found
 	// within the black list.
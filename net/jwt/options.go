@@ -15,6 +15,7 @@
 package jwt
 
 import (
+	"strings"
 	"time"
 
 	"github.com/corestoreio/csfw/net/mw"
@@ -59,16 +60,45 @@ func WithTemplateToken(f func() csjwt.Token, scopeIDs ...scope.TypeID) Option {
 
 // WithSigningMethod this option function lets you overwrite the default 256 bit
 // signing method for a specific scope. Used incorrectly token decryption can fail.
+// With a key set, see WithKeySet, the signing method applies to all keys of
+// the same algorithm.
 func WithSigningMethod(sm csjwt.Signer, scopeIDs ...scope.TypeID) Option {
 	return func(s *Service) error {
 		sc := s.findScopedConfig(scopeIDs...)
 		sc.SigningMethod = sm
-		sc.Verifier = csjwt.NewVerification(sm)
+		if len(sc.Keys) > 0 {
+			// copy because the parent scope shares the map
+			signers := make(map[string]csjwt.Signer, len(sc.Keys))
+			for kid, k := range sc.Keys {
+				signers[kid] = sc.KeySigners[kid]
+				if k.Algorithm() == signerAlgorithm(sm) {
+					signers[kid] = sm
+				}
+			}
+			sc.KeySigners = signers
+			sc.newVerifier(sc.keySetSigners()...)
+		} else {
+			sc.newVerifier(sm)
+		}
 		sc.initKeyFunc()
 		return s.updateScopedConfig(sc)
 	}
 }
 
+// signerAlgorithm returns the key algorithm ES, HS or RS of the signing
+// method. RSA-PSS signing methods return RS.
+func signerAlgorithm(sm csjwt.Signer) string {
+	switch alg := sm.Alg(); {
+	case strings.HasPrefix(alg, csjwt.ES):
+		return csjwt.ES
+	case strings.HasPrefix(alg, csjwt.HS):
+		return csjwt.HS
+	case strings.HasPrefix(alg, csjwt.RS), strings.HasPrefix(alg, "PS"):
+		return csjwt.RS
+	}
+	return ""
+}
+
 // WithExpiration sets expiration duration depending on the scope
 func WithExpiration(d time.Duration, scopeIDs ...scope.TypeID) Option {
 	return func(s *Service) error {
@@ -122,13 +152,94 @@ func WithKey(key csjwt.Key, scopeIDs ...scope.TypeID) Option {
 		}
 
 		sc.Key = key
-		sc.Verifier = csjwt.NewVerification(sc.SigningMethod)
+		sc.KeyID = ""
+		sc.Keys = nil
+		sc.KeySigners = nil
+		sc.newVerifier(sc.SigningMethod)
+		sc.initKeyFunc()
+
+		return s.updateScopedConfig(sc)
+	}
+}
+
+// WithKeySet sets a set of keys identified by their key ID for the rotation of
+// keys. The key with the ID currentKeyID signs new tokens and its ID gets
+// written into the kid header. Tokens get verified with the key of their kid
+// header, so tokens signed with a previous key stay valid as long as the key
+// can be found in the set. The signing method of each key gets derived from
+// the key, see csjwt.Key.SigningMethod, so the keys can have different
+// algorithms. Because the bit size of HMAC and RSA keys cannot be derived,
+// they use the 256 bit signing methods. You can provide your own signing
+// method by using additionally the function WithSigningMethod(), which must be
+// called after this function and applies to all keys of the same algorithm.
+func WithKeySet(currentKeyID string, keys map[string]csjwt.Key, scopeIDs ...scope.TypeID) Option {
+	if _, ok := keys[currentKeyID]; !ok {
+		return func(s *Service) error {
+			return errors.NewNotFoundf(errKeyIDNotFound, currentKeyID)
+		}
+	}
+	signers := make(map[string]csjwt.Signer, len(keys))
+	for kid, k := range keys {
+		if k.Error != nil {
+			return func(s *Service) error {
+				return errors.Wrapf(k.Error, "[jwt] Key Error. Key ID %q", kid)
+			}
+		}
+		if k.IsEmpty() {
+			return func(s *Service) error {
+				return errors.NewEmptyf(errKeyEmpty)
+			}
+		}
+		signers[kid] = k.SigningMethod()
+	}
+	return func(s *Service) error {
+		sc := s.findScopedConfig(scopeIDs...)
+
+		sc.Key = keys[currentKeyID]
+		sc.KeyID = currentKeyID
+		sc.Keys = keys
+		sc.KeySigners = signers
+		sc.SigningMethod = signers[currentKeyID]
+		sc.newVerifier(sc.keySetSigners()...)
 		sc.initKeyFunc()
 
 		return s.updateScopedConfig(sc)
 	}
 }
 
+// WithTokenCookieName sets the name of the cookie which contains the token.
+// The cookie gets checked if the Authorization header does not contain a
+// bearer token. An empty name disables the cookie.
+func WithTokenCookieName(name string, scopeIDs ...scope.TypeID) Option {
+	return func(s *Service) error {
+		sc := s.findScopedConfig(scopeIDs...)
+		var v csjwt.Verification
+		if sc.Verifier != nil {
+			v = *sc.Verifier // copy because the parent scope shares the pointer
+		}
+		v.CookieName = name
+		sc.Verifier = &v
+		return s.updateScopedConfig(sc)
+	}
+}
+
+// WithTokenFormInputName sets the name of the URL query parameter or HTML form
+// input field which contains the token. It gets checked if neither the
+// Authorization header nor the cookie contain a token. An empty name disables
+// the lookup.
+func WithTokenFormInputName(name string, scopeIDs ...scope.TypeID) Option {
+	return func(s *Service) error {
+		sc := s.findScopedConfig(scopeIDs...)
+		var v csjwt.Verification
+		if sc.Verifier != nil {
+			v = *sc.Verifier // copy because the parent scope shares the pointer
+		}
+		v.FormInputName = name
+		sc.Verifier = &v
+		return s.updateScopedConfig(sc)
+	}
+}
+
 // WithStoreCodeFieldName sets the name of the key in the token claims section
 // to extract the store code.
 func WithStoreCodeFieldName(name string, scopeIDs ...scope.TypeID) Option {
@@ -150,6 +261,16 @@ func WithUnauthorizedHandler(uh mw.ErrorHandler, scopeIDs ...scope.TypeID) Optio
 	}
 }
 
+// WithRefreshWindow enables the refreshing of a token in WithToken if the
+// token expires within the duration d. A zero duration disables it.
+func WithRefreshWindow(d time.Duration, scopeIDs ...scope.TypeID) Option {
+	return func(s *Service) error {
+		sc := s.findScopedConfig(scopeIDs...)
+		sc.RefreshWindow = d
+		return s.updateScopedConfig(sc)
+	}
+}
+
 // WithSingleTokenUsage if set to true for each request a token can be only used
 // once. The JTI (JSON Token Identifier) gets added to the blacklist until it
 // expires.
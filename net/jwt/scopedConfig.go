@@ -16,6 +16,7 @@ package jwt
 
 import (
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/corestoreio/csfw/net/mw"
@@ -34,6 +35,18 @@ type ScopedConfig struct {
 	// where ever. If key would be lower case then %#v still prints every field
 	// of the csjwt.Key.
 	Key csjwt.Key
+	// KeyID identifies the current Key. If not empty, new tokens contain the
+	// key ID in the kid header and parsed tokens get verified with the key of
+	// their kid header from the Keys set. Use WithKeySet.
+	KeyID string
+	// Keys contains all keys, identified by their key ID, which are allowed
+	// to verify a token. Useful to accept the tokens signed with the previous
+	// key during a key rotation. Must contain the current KeyID.
+	Keys map[string]csjwt.Key
+	// KeySigners contains the signing method of each key in Keys. A token
+	// gets only verified if its algorithm matches the signing method of the
+	// key found by its kid header.
+	KeySigners map[string]csjwt.Signer
 	// Expire defines the duration when the token is about to expire
 	Expire time.Duration
 	// Skew duration of time skew we allow between signer and verifier.
@@ -41,9 +54,9 @@ type ScopedConfig struct {
 	// SigningMethod how to sign the JWT. For default value see the OptionFuncs
 	SigningMethod csjwt.Signer
 	// Verifier token parser and verifier bound to ONE signing method. Setting a
-	// new SigningMethod also overwrites the JWTVerify pointer. TODO(newbies):
-	// For Verification add Options for setting custom Unmarshaler, HTTP FORM
-	// input name and cookie name.
+	// new SigningMethod also overwrites the JWTVerify pointer but keeps the
+	// cookie name and the HTML form input name. TODO(newbies): For
+	// Verification add Options for setting custom Unmarshaler.
 	Verifier *csjwt.Verification
 	// KeyFunc will receive the parsed token and should return the key for
 	// validating.
@@ -61,6 +74,12 @@ type ScopedConfig struct {
 	// once. The JTI (JSON Token Identifier) gets added to the blacklist until it
 	// expires.
 	SingleTokenUsage bool
+	// RefreshWindow if greater zero a valid token which expires within this
+	// duration gets replaced by a new token in WithToken. The new token
+	// contains the same claims with a new expiration and gets set in the
+	// Authorization header of the response. Default value zero disables the
+	// refresh.
+	RefreshWindow time.Duration
 }
 
 var defaultUnauthorizedHandler = mw.ErrorWithStatusCode(http.StatusUnauthorized)
@@ -92,6 +111,10 @@ func (sc ScopedConfig) TemplateToken() (tk csjwt.Token) {
 		// must be a pointer because of the unmarshalling function
 		// default claim defines a map[string]interface{}
 		tk = csjwt.NewToken(&jwtclaim.Map{})
+		if sc.KeyID != "" {
+			// default header does not support the kid field
+			tk.Header = jwtclaim.NewHeadSegments()
+		}
 	}
 	_ = tk.Claims.Set(jwtclaim.KeyTimeSkew, sc.Skew)
 	return
@@ -129,6 +152,26 @@ func (sc ScopedConfig) Parse(rawToken []byte) (csjwt.Token, error) {
 	return dst, errors.Wrap(err, "[jwt] ScopedConfig.Verifier.Parse")
 }
 
+// needsRefresh reports if the valid token expires within the RefreshWindow.
+func (sc ScopedConfig) needsRefresh(tk csjwt.Token) bool {
+	if sc.RefreshWindow <= 0 || !tk.Valid {
+		return false
+	}
+	exp := tk.Claims.Expires()
+	return exp > 0 && exp <= sc.RefreshWindow
+}
+
+// newVerifier creates a new Verifier for the signing methods and applies the
+// cookie and form input name of the previous Verifier.
+func (sc *ScopedConfig) newVerifier(sms ...csjwt.Signer) {
+	v := csjwt.NewVerification(sms...)
+	if sc.Verifier != nil {
+		v.CookieName = sc.Verifier.CookieName
+		v.FormInputName = sc.Verifier.FormInputName
+	}
+	sc.Verifier = v
+}
+
 // initKeyFunc generates a closure for a specific scope to compare if the
 // algorithm in the token matches with the current algorithm.
 func (sc *ScopedConfig) initKeyFunc() {
@@ -140,15 +183,56 @@ func (sc *ScopedConfig) initKeyFunc() {
 	}
 	key := sc.Key
 	keyErr := sc.Key.Error
+	keys := sc.Keys
+	signers := sc.KeySigners
 	sc.KeyFunc = func(t *csjwt.Token) (csjwt.Key, error) {
-		if have, want := t.Alg(), alg; have != want {
+		if len(keys) == 0 {
+			if have, want := t.Alg(), alg; have != want {
+				return csjwt.Key{}, errors.NewNotImplementedf(errUnknownSigningMethod, have, want)
+			}
+			if keyErr != nil {
+				return csjwt.Key{}, errors.Wrap(keyErr, "[jwt] ScopedConfig.initKeyFunc.Key.Error")
+			}
+			return key, nil
+		}
+		kid, err := t.Header.Get(jwtclaim.HeaderKid)
+		if err != nil {
+			return csjwt.Key{}, errors.Wrap(err, "[jwt] ScopedConfig.initKeyFunc.Header.Get")
+		}
+		k, ok := keys[kid]
+		if !ok {
+			return csjwt.Key{}, errors.NewNotFoundf(errKeyIDNotFound, kid)
+		}
+		var want string
+		if sm := signers[kid]; sm != nil {
+			want = sm.Alg()
+		}
+		if have := t.Alg(); have != want {
 			return csjwt.Key{}, errors.NewNotImplementedf(errUnknownSigningMethod, have, want)
 		}
-		if keyErr != nil {
-			return csjwt.Key{}, errors.Wrap(sc.Key.Error, "[jwt] ScopedConfig.initKeyFunc.Key.Error")
+		return k, nil
+	}
+}
+
+// keySetSigners returns the distinct signing methods of the key set sorted by
+// their key ID.
+func (sc *ScopedConfig) keySetSigners() []csjwt.Signer {
+	kids := make([]string, 0, len(sc.KeySigners))
+	for kid := range sc.KeySigners {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	sms := make([]csjwt.Signer, 0, len(kids))
+	seen := make(map[string]bool, len(kids))
+	for _, kid := range kids {
+		sm := sc.KeySigners[kid]
+		if !seen[sm.Alg()] {
+			seen[sm.Alg()] = true
+			sms = append(sms, sm)
 		}
-		return key, nil
 	}
+	return sms
 }
 
 func newScopedConfig(target, parent scope.TypeID) *ScopedConfig {
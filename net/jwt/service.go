@@ -17,6 +17,7 @@ package jwt
 import (
 	"github.com/corestoreio/csfw/store/scope"
 	"github.com/corestoreio/csfw/util/csjwt"
+	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/log"
 )
@@ -83,7 +84,6 @@ func New(opts ...Option) (*Service, error) {
 // Claimer field.
 func (s *Service) NewToken(scopeID scope.TypeID, claim ...csjwt.Claimer) (csjwt.Token, error) {
 	var empty csjwt.Token
-	now := csjwt.TimeFunc()
 
 	sc, err := s.ConfigByScopeID(scopeID, 0)
 	if err != nil {
@@ -98,23 +98,52 @@ func (s *Service) NewToken(scopeID scope.TypeID, claim ...csjwt.Claimer) (csjwt.
 		}
 	}
 
+	return s.signToken(sc, tk)
+}
+
+// refreshToken creates a new signed token with the claims of the token tk and
+// a new expiration, issued at and ID.
+func (s *Service) refreshToken(sc ScopedConfig, tk csjwt.Token) (csjwt.Token, error) {
+	ntk := sc.TemplateToken()
+	ntk.Header = tk.Header
+	if err := csjwt.MergeClaims(ntk.Claims, tk.Claims); err != nil {
+		return csjwt.Token{}, errors.Wrap(err, "[jwt] refreshToken.MergeClaims")
+	}
+	ntk, err := s.signToken(sc, ntk)
+	if err != nil {
+		return csjwt.Token{}, errors.Wrap(err, "[jwt] refreshToken.signToken")
+	}
+	ntk.Valid = true
+	return ntk, nil
+}
+
+// signToken sets the claims ExpiresAt, IssuedAt and ID and signs the token.
+func (s *Service) signToken(sc ScopedConfig, tk csjwt.Token) (csjwt.Token, error) {
+	var empty csjwt.Token
+	now := csjwt.TimeFunc()
+
 	if err := tk.Claims.Set(claimExpiresAt, now.Add(sc.Expire).Unix()); err != nil {
-		return empty, errors.Wrap(err, "[jwt] NewToken.Claims.Set EXP")
+		return empty, errors.Wrap(err, "[jwt] signToken.Claims.Set EXP")
 	}
 	if err := tk.Claims.Set(claimIssuedAt, now.Unix()); err != nil {
-		return empty, errors.Wrap(err, "[jwt] NewToken.Claims.Set IAT")
+		return empty, errors.Wrap(err, "[jwt] signToken.Claims.Set IAT")
 	}
 
 	jti, err := s.JTI.NewID()
 	if err != nil {
-		return empty, errors.Wrap(err, "[jwt] NewToken.Claims.Set JTI.NewID")
+		return empty, errors.Wrap(err, "[jwt] signToken.Claims.Set JTI.NewID")
 	}
 	if err := tk.Claims.Set(claimKeyID, jti); err != nil {
-		return empty, errors.Wrapf(err, "[jwt] NewToken.Claims.Set KID: %q", jti)
+		return empty, errors.Wrapf(err, "[jwt] signToken.Claims.Set KID: %q", jti)
+	}
+	if sc.KeyID != "" {
+		if err := tk.Header.Set(jwtclaim.HeaderKid, sc.KeyID); err != nil {
+			return empty, errors.Wrapf(err, "[jwt] signToken.Header.Set kid: %q", sc.KeyID)
+		}
 	}
 
 	tk.Raw, err = tk.SignedString(sc.SigningMethod, sc.Key)
-	return tk, errors.Wrap(err, "[jwt] NewToken.SignedString")
+	return tk, errors.Wrap(err, "[jwt] signToken.SignedString")
 }
 
 // Logout adds a token securely to a blacklist with the expiration duration. If
@@ -141,7 +170,7 @@ func (s *Service) Parse(rawToken []byte) (csjwt.Token, error) {
 
 // ParseScoped parses a token based on the applied scope and the scope ID.
 // Different configurations are passed to the token parsing function. The black
-// list will be checked for containing entries.
+// list will be checked if it contains the JTI of the token.
 func (s *Service) ParseScoped(scopeID scope.TypeID, rawToken []byte) (csjwt.Token, error) {
 	var empty csjwt.Token
 
@@ -158,7 +187,13 @@ func (s *Service) ParseScoped(scopeID scope.TypeID, rawToken []byte) (csjwt.Toke
 	var inBL bool
 	isValid := token.Valid && len(token.Raw) > 0
 	if isValid {
-		inBL = s.Blacklist.Has(token.Raw)
+		// Logout stores the JTI in the blacklist. Tokens without a JTI can
+		// only be blocked by their raw value.
+		id, err := extractJTI(token)
+		if err != nil {
+			id = token.Raw
+		}
+		inBL = s.Blacklist.Has(id)
 	}
 	if isValid && !inBL {
 		return token, nil
@@ -32,6 +32,12 @@ func SetHeaderAuthorization(req *http.Request, token []byte) {
 	req.Header.Set("Authorization", "Bearer "+string(token))
 }
 
+// SetResponseHeaderAuthorization convenience function to set the Authorization
+// Bearer Header on a response for a given token.
+func SetResponseHeaderAuthorization(w http.ResponseWriter, token []byte) {
+	w.Header().Set("Authorization", "Bearer "+string(token))
+}
+
 // WithRunMode sets the initial runMode, loads the token configuration, parses
 // and validates a token and if the token contains a new store code it changes
 // the scope for the context.
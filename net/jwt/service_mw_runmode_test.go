@@ -502,4 +502,70 @@ func TestService_WithRunMode_DifferentScopes(t *testing.T) {
 	assert.Exactly(t, int32(30), *calledFinalHandler, "calledFinalHandler 2*(3*5)")
 }
 
+func TestService_WithRunMode_StoreCodeFieldName(t *testing.T) {
+
+	cfg := cfgmock.NewService()
+	jm := jwt.MustNew(
+		jwt.WithRootConfig(cfg),
+		jwt.WithServiceErrorHandler(mw.ErrorWithPanic),
+		jwt.WithErrorHandler(mw.ErrorWithPanic, scope.Website.Pack(1)),
+		jwt.WithUnauthorizedHandler(mw.ErrorWithPanic, scope.Website.Pack(1)),
+		jwt.WithStoreCodeFieldName("euro_store", scope.Website.Pack(1)),
+	)
+	jm.Log = log.BlackHole{EnableDebug: true, EnableInfo: true}
+
+	var haveCodes []string
+	sf := &storemock.Find{
+		DefaultStoreIDFn: func(_ scope.TypeID) (int64, int64, error) {
+			return 2, 1, nil // store AT in website euro
+		},
+		StoreIDbyCodeFn: func(_ scope.TypeID, code string) (int64, int64, error) {
+			haveCodes = append(haveCodes, code)
+			if code == "de" {
+				return 1, 1, nil
+			}
+			return 0, 0, errors.NewNotFoundf("Store %q not found", code)
+		},
+	}
+
+	tests := []struct {
+		claim       jwtclaim.Map
+		wantStoreID int64
+		wantCodes   []string
+	}{
+		// custom claim key resolves the store
+		{jwtclaim.Map{"euro_store": "de"}, 1, []string{"de"}},
+		// default claim key gets ignored and the default store applies
+		{jwtclaim.Map{jwt.StoreCodeFieldName: "de"}, 2, nil},
+		// missing claim falls through
+		{jwtclaim.Map{"xfoo": "bar"}, 2, nil},
+	}
+	for i, test := range tests {
+		haveCodes = nil
+		var haveStoreID int64
+		final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, sID, ok := scope.FromContext(r.Context())
+			assert.True(t, ok, "Index %d", i)
+			haveStoreID = sID
+			w.WriteHeader(http.StatusTeapot)
+		})
+		authHandler := jm.WithRunMode(scope.RunModeFunc(func(_ *http.Request) scope.TypeID {
+			return scope.Website.Pack(1)
+		}), sf)(final)
+
+		tk, err := jm.NewToken(scope.Website.Pack(1), test.claim)
+		if err != nil {
+			t.Fatalf("Index %d => %+v", i, err)
+		}
+		req := httptest.NewRequest("GET", "http://scope-euro.xyz", nil)
+		jwt.SetHeaderAuthorization(req, tk.Raw)
+		w := httptest.NewRecorder()
+		authHandler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusTeapot, w.Code, "Index %d", i)
+		assert.Exactly(t, test.wantStoreID, haveStoreID, "Index %d", i)
+		assert.Exactly(t, test.wantCodes, haveCodes, "Index %d", i)
+	}
+}
+
 // todo add test for form with input field: access_token
@@ -23,8 +23,10 @@ import (
 )
 
 // WithToken parses and validates a token depending on the scope. A check to the
-// blacklist will be performed. The token gets added to the context for further
-// processing for the next middlewares. This function depends on the runMode and
+// blacklist will be performed. If the token expires within the configured
+// RefreshWindow, a new token with the same claims gets set in the
+// Authorization header of the response. The token gets added to the context
+// for further processing for the next middlewares. This function depends on the runMode and
 // its scope which must exists in the requests context. WithToken() does not
 // change the scope of the previously initialized runMode and its scope.
 func (s *Service) WithToken(next http.Handler) http.Handler {
@@ -57,6 +59,20 @@ func (s *Service) WithToken(next http.Handler) http.Handler {
 			return
 		}
 
+		if scpCfg.needsRefresh(token) {
+			ntk, err := s.refreshToken(scpCfg, token)
+			if err != nil {
+				// the current token is still valid, so we can proceed.
+				s.Log.Info("jwt.Service.WithToken.refreshToken.Error", log.Err(err))
+			} else {
+				if s.Log.IsDebug() {
+					s.Log.Debug("jwt.Service.WithToken.refreshToken", log.Stringer("scope", scpCfg.ScopeID), log.Duration("refresh_window", scpCfg.RefreshWindow), loghttp.Request("request", r))
+				}
+				SetResponseHeaderAuthorization(w, ntk.Raw)
+				token = ntk
+			}
+		}
+
 		// add token to the context
 		ctx := withContext(r.Context(), token)
 
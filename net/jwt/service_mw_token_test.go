@@ -15,14 +15,18 @@
 package jwt_test
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/corestoreio/csfw/config/cfgmock"
 	"github.com/corestoreio/csfw/net/jwt"
 	"github.com/corestoreio/csfw/storage/containable"
 	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/csjwt"
 	"github.com/corestoreio/csfw/util/csjwt/jwtclaim"
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/log"
@@ -180,3 +184,173 @@ func TestService_WithToken_SingleUsage(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 	assert.Contains(t, w.Body.String(), http.StatusText(http.StatusUnauthorized)+"\n")
 }
+
+func TestService_WithToken_Refresh(t *testing.T) {
+
+	// tokenExpire applies to the token created in the default scope, the
+	// request runs in website 77 with an expiration of one hour.
+	newHandler := func(t *testing.T, tokenExpire time.Duration) (*jwt.Service, http.Handler, []byte) {
+		jm, err := jwt.New(
+			jwt.WithExpiration(tokenExpire),
+			jwt.WithRefreshWindow(time.Minute),
+			jwt.WithExpiration(time.Hour, scope.Website.Pack(77)),
+			jwt.WithServiceErrorHandler(func(err error) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					panic(fmt.Sprintf("Should not get called: %+v", err))
+				})
+			}),
+			jwt.WithRootConfig(cfgmock.NewService()),
+		)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		jm.Log = log.BlackHole{EnableDebug: true, EnableInfo: true}
+
+		theToken, err := jm.NewToken(scope.DefaultTypeID, jwtclaim.Map{
+			"xfoo": "bar",
+		})
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tk, ok := jwt.FromContext(r.Context())
+			assert.True(t, ok)
+			assert.True(t, tk.Valid)
+			xfoo, _ := tk.Claims.Get("xfoo")
+			assert.Exactly(t, "bar", xfoo)
+			w.WriteHeader(http.StatusOK)
+		})
+		return jm, jm.WithToken(final), theToken.Raw
+	}
+
+	newRequest := func(token []byte) *http.Request {
+		req := httptest.NewRequest("GET", "http://auth7.xyz", nil)
+		req = req.WithContext(scope.WithContext(req.Context(), 77, 0))
+		jwt.SetHeaderAuthorization(req, token)
+		return req
+	}
+
+	t.Run("near expiry issues new token", func(t *testing.T) {
+		jm, authHandler, token := newHandler(t, 30*time.Second)
+
+		w := httptest.NewRecorder()
+		authHandler.ServeHTTP(w, newRequest(token))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		header := w.Header().Get("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			t.Fatalf("Missing Bearer token in response header: %q", header)
+		}
+		newToken := []byte(strings.TrimPrefix(header, "Bearer "))
+		assert.NotEqual(t, string(token), string(newToken))
+
+		tk, err := jm.Parse(newToken)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		xfoo, _ := tk.Claims.Get("xfoo")
+		assert.Exactly(t, "bar", xfoo)
+		assert.True(t, tk.Claims.Expires() > 59*time.Minute, "Expires: %s", tk.Claims.Expires())
+	})
+
+	t.Run("fresh token untouched", func(t *testing.T) {
+		_, authHandler, token := newHandler(t, time.Hour)
+
+		w := httptest.NewRecorder()
+		authHandler.ServeHTTP(w, newRequest(token))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Authorization"))
+	})
+}
+
+func TestService_WithToken_CookieAndFormInput(t *testing.T) {
+	jm, err := jwt.New(
+		jwt.WithTokenCookieName("jwt_cookie", scope.Website.Pack(88)),
+		jwt.WithTokenFormInputName("jwt_form", scope.Website.Pack(88)),
+		// the cookie and form names must survive a new signing method
+		jwt.WithKey(csjwt.WithPasswordRandom(), scope.Website.Pack(88)),
+		jwt.WithErrorHandler(func(err error) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			})
+		}, scope.Website.Pack(88)),
+		jwt.WithServiceErrorHandler(func(err error) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic(fmt.Sprintf("Should not get called: %+v", err))
+			})
+		}),
+		jwt.WithRootConfig(cfgmock.NewService()),
+	)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	jm.Log = log.BlackHole{EnableDebug: true, EnableInfo: true}
+
+	newToken := func(src string) []byte {
+		tk, err := jm.NewToken(scope.Website.Pack(88), jwtclaim.Map{
+			"src": src,
+		})
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		return tk.Raw
+	}
+
+	authHandler := jm.WithToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tk, ok := jwt.FromContext(r.Context())
+		assert.True(t, ok)
+		src, _ := tk.Claims.Get("src")
+		fmt.Fprint(w, src)
+	}))
+
+	newRequest := func(url string) *http.Request {
+		req := httptest.NewRequest("GET", url, nil)
+		return req.WithContext(scope.WithContext(req.Context(), 88, 0))
+	}
+
+	tests := []struct {
+		name    string
+		req     *http.Request
+		wantSrc string
+	}{
+		{"header", func() *http.Request {
+			req := newRequest("http://auth8.xyz")
+			jwt.SetHeaderAuthorization(req, newToken("header"))
+			return req
+		}(), "header"},
+		{"cookie", func() *http.Request {
+			req := newRequest("http://auth8.xyz")
+			req.AddCookie(&http.Cookie{Name: "jwt_cookie", Value: string(newToken("cookie"))})
+			return req
+		}(), "cookie"},
+		{"query", newRequest("http://auth8.xyz?jwt_form=" + string(newToken("query"))), "query"},
+		{"header before cookie and query", func() *http.Request {
+			req := newRequest("http://auth8.xyz?jwt_form=" + string(newToken("query")))
+			req.AddCookie(&http.Cookie{Name: "jwt_cookie", Value: string(newToken("cookie"))})
+			jwt.SetHeaderAuthorization(req, newToken("header"))
+			return req
+		}(), "header"},
+		{"cookie before query", func() *http.Request {
+			req := newRequest("http://auth8.xyz?jwt_form=" + string(newToken("query")))
+			req.AddCookie(&http.Cookie{Name: "jwt_cookie", Value: string(newToken("cookie"))})
+			return req
+		}(), "cookie"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			authHandler.ServeHTTP(w, test.req)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Exactly(t, test.wantSrc, w.Body.String())
+		})
+	}
+
+	t.Run("other scope ignores the cookie", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "http://auth8.xyz", nil)
+		req = req.WithContext(scope.WithContext(req.Context(), 89, 0))
+		req.AddCookie(&http.Cookie{Name: "jwt_cookie", Value: string(newToken("cookie"))})
+		w := httptest.NewRecorder()
+		authHandler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -249,3 +249,169 @@ func TestService_NewToken_Merge_Fail(t *testing.T) {
 	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
 	assert.Empty(t, theToken.Raw)
 }
+
+func TestService_KeySet_Rotation(t *testing.T) {
+
+	keyOld := csjwt.WithPassword([]byte(`Rotate me`))
+	keyNew := csjwt.WithPassword([]byte(`I am the new one`))
+
+	jwtsOld := jwt.MustNew(jwt.WithKeySet("k1", map[string]csjwt.Key{"k1": keyOld}))
+	tkOld, err := jwtsOld.NewToken(scope.DefaultTypeID, jwtclaim.Map{"xfoo": "bar"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	kid, err := tkOld.Header.Get(jwtclaim.HeaderKid)
+	assert.NoError(t, err)
+	assert.Exactly(t, "k1", kid)
+
+	jwts := jwt.MustNew(jwt.WithKeySet("k2", map[string]csjwt.Key{
+		"k1": keyOld,
+		"k2": keyNew,
+	}))
+
+	// new tokens contain the current kid
+	tkNew, err := jwts.NewToken(scope.DefaultTypeID, jwtclaim.Map{"xfoo": "bar"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	kid, err = tkNew.Header.Get(jwtclaim.HeaderKid)
+	assert.NoError(t, err)
+	assert.Exactly(t, "k2", kid)
+
+	for i, raw := range [][]byte{tkOld.Raw, tkNew.Raw} {
+		tk, err := jwts.Parse(raw)
+		if err != nil {
+			t.Fatalf("Index %d: %+v", i, err)
+		}
+		assert.True(t, tk.Valid, "Index %d", i)
+		xfoo, _ := tk.Claims.Get("xfoo")
+		assert.Exactly(t, "bar", xfoo, "Index %d", i)
+	}
+
+	// new token cannot be verified by the old key set
+	_, err = jwtsOld.Parse(tkNew.Raw)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	assert.Contains(t, err.Error(), `Key ID "k2" not found`)
+
+	// unknown kid gets rejected
+	jwtsUnknown := jwt.MustNew(jwt.WithKeySet("k3", map[string]csjwt.Key{"k3": keyNew}))
+	tkUnknown, err := jwtsUnknown.NewToken(scope.DefaultTypeID)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	tk, err := jwts.Parse(tkUnknown.Raw)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	assert.Contains(t, err.Error(), `Key ID "k3" not found`)
+	assert.False(t, tk.Valid)
+}
+
+func TestService_KeySet_Errors(t *testing.T) {
+
+	_, err := jwt.New(jwt.WithKeySet("k2", map[string]csjwt.Key{"k1": csjwt.WithPasswordRandom()}))
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	_, err = jwt.New(jwt.WithKeySet("k1", map[string]csjwt.Key{
+		"k1": csjwt.WithPasswordRandom(),
+		"k2": csjwt.Key{},
+	}))
+	assert.True(t, errors.IsEmpty(err), "Error: %+v", err)
+}
+
+func TestService_KeySet_SigningMethods(t *testing.T) {
+
+	keyRSA := csjwt.WithRSAPrivateKeyFromFile("../../util/csjwt/test/test_rsa_np")
+	keyES := csjwt.WithECPrivateKeyFromFile("../../util/csjwt/test/ec384-private.pem")
+	keyHS := csjwt.WithPassword([]byte(`Rotate me`))
+
+	newToken := func(jwts *jwt.Service) csjwt.Token {
+		tk, err := jwts.NewToken(scope.DefaultTypeID, jwtclaim.Map{"xfoo": "bar"})
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		return tk
+	}
+
+	// the signing method gets derived from each key
+	tkRSA := newToken(jwt.MustNew(jwt.WithKeySet("rsa", map[string]csjwt.Key{"rsa": keyRSA})))
+	assert.Exactly(t, csjwt.RS256, tkRSA.Alg())
+	tkES := newToken(jwt.MustNew(jwt.WithKeySet("es", map[string]csjwt.Key{"es": keyES})))
+	assert.Exactly(t, csjwt.ES384, tkES.Alg())
+
+	jwts := jwt.MustNew(jwt.WithKeySet("es", map[string]csjwt.Key{
+		"rsa": keyRSA,
+		"es":  keyES,
+		"hs":  keyHS,
+	}))
+	for i, raw := range [][]byte{tkRSA.Raw, tkES.Raw, newToken(jwts).Raw} {
+		tk, err := jwts.Parse(raw)
+		if err != nil {
+			t.Fatalf("Index %d: %+v", i, err)
+		}
+		assert.True(t, tk.Valid, "Index %d", i)
+	}
+
+	// WithSigningMethod applies to all keys of the same algorithm
+	jwts = jwt.MustNew(
+		jwt.WithKeySet("rsa", map[string]csjwt.Key{"rsa": keyRSA, "es": keyES}),
+		jwt.WithSigningMethod(csjwt.NewSigningMethodRS512()),
+	)
+	tk := newToken(jwts)
+	assert.Exactly(t, csjwt.RS512, tk.Alg())
+	_, err := jwts.Parse(tk.Raw)
+	assert.NoError(t, err, "%+v", err)
+	_, err = jwts.Parse(tkES.Raw)
+	assert.NoError(t, err, "%+v", err)
+	_, err = jwts.Parse(tkRSA.Raw)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+// fakeBL a blacklist backend with an adjustable clock, like a Redis
+// backend would expire the keys.
+type fakeBL struct {
+	now  time.Time
+	keys map[string]time.Time
+}
+
+func (b *fakeBL) Set(id []byte, exp time.Duration) error {
+	b.keys[string(id)] = b.now.Add(exp)
+	return nil
+}
+
+func (b *fakeBL) Has(id []byte) bool {
+	exp, ok := b.keys[string(id)]
+	return ok && b.now.Before(exp)
+}
+
+func TestService_Blacklist_Backend(t *testing.T) {
+
+	bl := &fakeBL{
+		now:  time.Now(),
+		keys: make(map[string]time.Time),
+	}
+	jwts := jwt.MustNew(jwt.WithBlacklist(bl))
+
+	theToken, err := jwts.NewToken(scope.DefaultTypeID, jwtclaim.Map{"xfoo": "bar"})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	tk, err := jwts.Parse(theToken.Raw)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	if err := jwts.Logout(tk); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Len(t, bl.keys, 1)
+
+	// blacklisted token gets rejected
+	_, err = jwts.Parse(theToken.Raw)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	// the blacklist entry expires before the token. The token itself stays
+	// valid because the parser validates against the real time.
+	bl.now = bl.now.Add(time.Hour + time.Second)
+	tk, err = jwts.Parse(theToken.Raw)
+	assert.NoError(t, err, "Error: %+v", err)
+	assert.True(t, tk.Valid)
+}
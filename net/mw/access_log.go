@@ -0,0 +1,98 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mw
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/corestoreio/log"
+)
+
+// RequestIDHeader identifies the header which contains the ID of a request.
+// The ID gets read from the response header and if empty from the request
+// header.
+const RequestIDHeader = "X-Request-Id"
+
+// WithAccessLog logs each completed request at info level with the method,
+// path, status code, written bytes, duration and the request ID. Set the
+// logger with the option SetLogger.
+func WithAccessLog(opts ...Option) Middleware {
+	ob := newOptionBox(opts...)
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !ob.log.IsInfo() {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w}
+			h.ServeHTTP(sw, r)
+
+			id := w.Header().Get(RequestIDHeader)
+			if id == "" {
+				id = r.Header.Get(RequestIDHeader)
+			}
+			ob.log.Info("mw.WithAccessLog",
+				log.String("method", r.Method),
+				log.String("path", r.URL.Path),
+				log.Int("status", sw.Status()),
+				log.Int("bytes", sw.size),
+				log.Duration("duration", time.Since(start)),
+				log.String("request_id", id),
+			)
+		})
+	}
+}
+
+// statusWriter records the status code and the number of written bytes.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (sw *statusWriter) WriteHeader(code int) {
+	if sw.status == 0 {
+		sw.status = code
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *statusWriter) Write(p []byte) (int, error) {
+	if sw.status == 0 {
+		sw.status = http.StatusOK
+	}
+	n, err := sw.ResponseWriter.Write(p)
+	sw.size += n
+	return n, err
+}
+
+// Flush implements http.Flusher.
+func (sw *statusWriter) Flush() {
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Status returns the written status code. Defaults to 200 if the handler
+// did not write anything.
+func (sw *statusWriter) Status() int {
+	if sw.status == 0 {
+		return http.StatusOK
+	}
+	return sw.status
+}
@@ -0,0 +1,68 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mw_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/log/logw"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAccessLog(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		handler http.HandlerFunc
+		want    string
+		wantID  string
+	}{
+		{
+			"200",
+			"/catalog/product/id/3452",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(mw.RequestIDHeader, "gopher/0815-2")
+				_, _ = w.Write([]byte(`Confirmed landing`))
+			},
+			`INFO: mw.WithAccessLog method: "GET" path: "/catalog/product/id/3452" status: 200 bytes: 17 duration:`,
+			"gopher/0815-2", // response header wins
+		},
+		{
+			"404",
+			"/not/found",
+			http.NotFound,
+			`INFO: mw.WithAccessLog method: "GET" path: "/not/found" status: 404 bytes: 19 duration:`,
+			"gopher/0815-1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			finalCH := mw.ChainFunc(test.handler, mw.WithAccessLog(mw.SetLogger(logw.NewLog(logw.WithWriter(&buf)))))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "http://corestore.io"+test.path, nil)
+			r.Header.Set(mw.RequestIDHeader, "gopher/0815-1")
+			finalCH.ServeHTTP(w, r)
+
+			assert.Contains(t, buf.String(), test.want)
+			assert.Contains(t, buf.String(), `request_id: "`+test.wantID+`"`)
+		})
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mw
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	csnet "github.com/corestoreio/csfw/net"
+)
+
+// CompressMinSize defines the default minimum size in bytes of a response
+// body to get compressed. Smaller bodies get sent uncompressed because the
+// compression overhead would be bigger than the savings.
+const CompressMinSize = 1024
+
+// CompressSkipContentTypes contains the default prefixes of content types
+// which are already compressed.
+var CompressSkipContentTypes = []string{
+	"image/",
+	"audio/",
+	"video/",
+	"font/woff",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-bzip2",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/pdf",
+}
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(ioutil.Discard)
+	},
+}
+
+var flateWriterPool = sync.Pool{
+	New: func() interface{} {
+		w, err := flate.NewWriter(ioutil.Discard, flate.DefaultCompression)
+		if err != nil {
+			panic(err)
+		}
+		return w
+	},
+}
+
+// WithCompression is a middleware which applies the GZIP or deflate algorithm
+// to the response body depending on the HTTP Accept-Encoding header. GZIP has
+// priority before deflate. An encoding with a quality value of zero counts as
+// not accepted. Bodies smaller than the minimum size, bodies of a skipped
+// content type and bodies which already have a Content-Encoding get written
+// uncompressed. The http.Flusher interface will be preserved.
+func WithCompression(opts ...Option) Middleware {
+	ob := newOptionBox(opts...)
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			enc := negotiateEncoding(r.Header.Get(csnet.AcceptEncoding))
+			if enc == "" {
+				h.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add(csnet.Vary, csnet.AcceptEncoding)
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				ob:             ob,
+				encoding:       enc,
+			}
+			defer cw.close()
+			h.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding returns the preferred supported encoding of the
+// Accept-Encoding header or an empty string.
+func negotiateEncoding(header string) string {
+	var gz, def bool
+	for _, part := range strings.Split(header, ",") {
+		name := part
+		if i := strings.IndexByte(part, ';'); i >= 0 {
+			name = part[:i]
+			if q := strings.TrimSpace(part[i+1:]); strings.HasPrefix(q, "q=") {
+				if f, err := strconv.ParseFloat(q[2:], 64); err == nil && f == 0 {
+					continue
+				}
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case csnet.CompressGZIP:
+			gz = true
+		case csnet.CompressDeflate:
+			def = true
+		}
+	}
+	switch {
+	case gz:
+		return csnet.CompressGZIP
+	case def:
+		return csnet.CompressDeflate
+	}
+	return ""
+}
+
+// compressWriter buffers the response body until the minimum size has been
+// reached and then decides whether to compress or not.
+type compressWriter struct {
+	http.ResponseWriter
+	ob       *optionBox
+	encoding string
+
+	code    int
+	buf     []byte
+	decided bool
+	zw      io.WriteCloser
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.code == 0 {
+		cw.code = code
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.zw != nil {
+		return cw.zw.Write(p)
+	}
+	if cw.decided {
+		return cw.ResponseWriter.Write(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) < cw.ob.compressMinSize {
+		return len(p), nil
+	}
+	if err := cw.decide(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes the buffered data to the client. It implements http.Flusher.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		_ = cw.decide()
+	}
+	switch zw := cw.zw.(type) {
+	case *gzip.Writer:
+		_ = zw.Flush()
+	case *flate.Writer:
+		_ = zw.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// decide writes the header and the buffered body, compressed or not.
+func (cw *compressWriter) decide() error {
+	cw.decided = true
+	hdr := cw.Header()
+	if hdr.Get(csnet.ContentType) == "" && len(cw.buf) > 0 {
+		hdr.Set(csnet.ContentType, http.DetectContentType(cw.buf))
+	}
+	if cw.code == 0 {
+		cw.code = http.StatusOK
+	}
+
+	if len(cw.buf) < cw.ob.compressMinSize || hdr.Get(csnet.ContentEncoding) != "" || cw.skipContentType(hdr.Get(csnet.ContentType)) {
+		cw.ResponseWriter.WriteHeader(cw.code)
+		return cw.writeBuf(cw.ResponseWriter)
+	}
+
+	hdr.Set(csnet.ContentEncoding, cw.encoding)
+	hdr.Del(csnet.ContentLength)
+	cw.ResponseWriter.WriteHeader(cw.code)
+
+	switch cw.encoding {
+	case csnet.CompressGZIP:
+		zw := gzipWriterPool.Get().(*gzip.Writer)
+		zw.Reset(cw.ResponseWriter)
+		cw.zw = zw
+	case csnet.CompressDeflate:
+		zw := flateWriterPool.Get().(*flate.Writer)
+		zw.Reset(cw.ResponseWriter)
+		cw.zw = zw
+	}
+	return cw.writeBuf(cw.zw)
+}
+
+func (cw *compressWriter) writeBuf(w io.Writer) error {
+	if len(cw.buf) == 0 {
+		return nil
+	}
+	_, err := w.Write(cw.buf)
+	cw.buf = nil
+	return err
+}
+
+func (cw *compressWriter) skipContentType(ct string) bool {
+	ct = strings.ToLower(strings.TrimSpace(ct))
+	for _, prefix := range cw.ob.compressSkipTypes {
+		if strings.HasPrefix(ct, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// close writes the remaining buffer and returns the compressor to its pool.
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		_ = cw.decide()
+	}
+	switch zw := cw.zw.(type) {
+	case *gzip.Writer:
+		_ = zw.Close()
+		gzipWriterPool.Put(zw)
+	case *flate.Writer:
+		_ = zw.Close()
+		flateWriterPool.Put(zw)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mw_test
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	csnet "github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCompressBody = strings.Repeat(`{"general":{"country":{"allow":"DE,AT,CH"}}}`, 100)
+
+func testCompress(t *testing.T, acceptEncoding, contentType, body string, opts ...mw.Option) *httptest.ResponseRecorder {
+	finalCH := mw.ChainFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set(csnet.ContentType, contentType)
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(body))
+	}, mw.WithCompression(opts...))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://corestore.io/config/export", nil)
+	if acceptEncoding != "" {
+		r.Header.Set(csnet.AcceptEncoding, acceptEncoding)
+	}
+	finalCH.ServeHTTP(w, r)
+	assert.Exactly(t, http.StatusAccepted, w.Code)
+	return w
+}
+
+func TestWithCompression_GZIP(t *testing.T) {
+	w := testCompress(t, "deflate, gzip;q=1.0", csnet.ApplicationJSON, testCompressBody)
+	assert.Exactly(t, csnet.CompressGZIP, w.Header().Get(csnet.ContentEncoding))
+	assert.Exactly(t, csnet.AcceptEncoding, w.Header().Get(csnet.Vary))
+	assert.True(t, w.Body.Len() < len(testCompressBody), "Body length %d", w.Body.Len())
+
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Exactly(t, testCompressBody, string(data))
+}
+
+func TestWithCompression_Deflate(t *testing.T) {
+	w := testCompress(t, "gzip;q=0, deflate", "", testCompressBody)
+	assert.Exactly(t, csnet.CompressDeflate, w.Header().Get(csnet.ContentEncoding))
+	// Content type gets detected from the uncompressed body
+	assert.Exactly(t, "text/plain; charset=utf-8", w.Header().Get(csnet.ContentType))
+
+	data, err := ioutil.ReadAll(flate.NewReader(w.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Exactly(t, testCompressBody, string(data))
+}
+
+func TestWithCompression_Skipped(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		body           string
+	}{
+		{"no header", "", csnet.ApplicationJSON, testCompressBody},
+		{"unsupported encoding", "br, compress", csnet.ApplicationJSON, testCompressBody},
+		{"disabled encodings", "gzip;q=0, deflate;q=0.0", csnet.ApplicationJSON, testCompressBody},
+		{"compressed content type", "gzip", "image/png", testCompressBody},
+		{"below min size", "gzip", csnet.ApplicationJSON, `{"general":{}}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := testCompress(t, test.acceptEncoding, test.contentType, test.body)
+			assert.Empty(t, w.Header().Get(csnet.ContentEncoding))
+			assert.Exactly(t, test.body, w.Body.String())
+		})
+	}
+}
+
+func TestWithCompression_MinSize(t *testing.T) {
+	body := strings.Repeat("a", 100)
+
+	w := testCompress(t, "gzip", csnet.TextPlain, body, mw.SetCompressMinSize(101))
+	assert.Empty(t, w.Header().Get(csnet.ContentEncoding))
+	assert.Exactly(t, body, w.Body.String())
+
+	w = testCompress(t, "gzip", csnet.TextPlain, body, mw.SetCompressMinSize(100))
+	assert.Exactly(t, csnet.CompressGZIP, w.Header().Get(csnet.ContentEncoding))
+}
+
+func TestWithCompression_Flush(t *testing.T) {
+	finalCH := mw.ChainFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testCompressBody))
+		w.(http.Flusher).Flush()
+	}, mw.WithCompression())
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://corestore.io/config/export", nil)
+	r.Header.Set(csnet.AcceptEncoding, "gzip")
+	finalCH.ServeHTTP(w, r)
+	assert.True(t, w.Flushed)
+	assert.Exactly(t, csnet.CompressGZIP, w.Header().Get(csnet.ContentEncoding))
+}
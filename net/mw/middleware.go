@@ -153,16 +153,26 @@ func WithCloseNotify(opts ...Option) Middleware {
 	}
 }
 
-// WithTimeout returns a net.Handler which adds a timeout to the context.
+// WithTimeout returns a net.Handler which adds a timeout to the context and
+// cancels the context once the handler returns or the deadline has been
+// reached. Child handlers have the responsibility to obey the context deadline,
+// for example by passing it to dbr's ExecContext.
 //
-// Child handlers have the responsibility to obey the context deadline and to
-// return an appropriate error (or not) response in case of timeout.
-func WithTimeout(timeout time.Duration) Middleware {
+// The handler gets wrapped by http.TimeoutHandler. If the handler does not
+// finish in time WithTimeout responds with 503 Service Unavailable and logs
+// the request at debug level. Writes after the timeout return
+// http.ErrHandlerTimeout.
+func WithTimeout(timeout time.Duration, opts ...Option) Middleware {
+	ob := newOptionBox(opts...)
 	return func(h http.Handler) http.Handler {
+		th := http.TimeoutHandler(h, timeout, http.StatusText(http.StatusServiceUnavailable))
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ctx, _ := context.WithTimeout(r.Context(), timeout)
-			r = r.WithContext(ctx)
-			h.ServeHTTP(w, r)
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			th.ServeHTTP(w, r.WithContext(ctx))
+			if err := ctx.Err(); err == context.DeadlineExceeded && ob.log.IsDebug() {
+				ob.log.Debug("mw.WithTimeout.Done", log.Err(err), log.Duration("timeout", timeout), loghttp.Request("request", r))
+			}
 		})
 	}
 }
@@ -89,6 +89,44 @@ func TestWithTimeoutHandler(t *testing.T) {
 	assert.Equal(t, "gopher life with deadline", w.Body.String())
 }
 
+func TestWithTimeout_Fast(t *testing.T) {
+	finalCH := mw.ChainFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-CoreStore-Fast", "true")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`Landed`))
+	}, mw.WithTimeout(time.Second))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://corestore.io/catalog/product/id/3452", nil)
+	finalCH.ServeHTTP(w, r)
+	assert.Exactly(t, http.StatusAccepted, w.Code)
+	assert.Exactly(t, "true", w.Header().Get("X-CoreStore-Fast"))
+	assert.Exactly(t, "Landed", w.Body.String())
+}
+
+func TestWithTimeout_Slow(t *testing.T) {
+	release := make(chan struct{})
+	handlerDone := make(chan error, 1)
+	finalCH := mw.ChainFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		<-release // wait until WithTimeout has responded
+		w.Header().Set("X-CoreStore-Slow", "true")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`Too late`))
+		handlerDone <- err
+	}, mw.WithTimeout(10*time.Millisecond))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://corestore.io/catalog/product/id/3452", nil)
+	finalCH.ServeHTTP(w, r)
+	close(release)
+
+	assert.Exactly(t, http.ErrHandlerTimeout, <-handlerDone)
+	assert.Exactly(t, http.StatusServiceUnavailable, w.Code)
+	assert.Exactly(t, http.StatusText(http.StatusServiceUnavailable), w.Body.String())
+	assert.Empty(t, w.Header().Get("X-CoreStore-Slow"))
+}
+
 func TestWithHeader(t *testing.T) {
 	finalCH := mw.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`Confirmed landing on drone ship.`))
@@ -14,11 +14,18 @@
 
 package mw
 
-import "github.com/corestoreio/log"
+import (
+	"time"
+
+	"github.com/corestoreio/log"
+)
 
 type optionBox struct {
 	log                   log.Logger
 	methodOverrideFormKey string
+	compressMinSize       int
+	compressSkipTypes     []string
+	rateLimitIdleTimeout  time.Duration
 }
 
 // Option contains multiple functional options for middlewares.
@@ -28,6 +35,9 @@ func newOptionBox(opts ...Option) *optionBox {
 	ob := &optionBox{
 		log: log.BlackHole{}, // disabled info and debug logging
 		methodOverrideFormKey: MethodOverrideFormKey,
+		compressMinSize:       CompressMinSize,
+		compressSkipTypes:     CompressSkipContentTypes,
+		rateLimitIdleTimeout:  RateLimitIdleTimeout,
 	}
 	for _, o := range opts {
 		if o != nil {
@@ -50,3 +60,27 @@ func SetMethodOverrideFormKey(k string) Option {
 		ob.methodOverrideFormKey = k
 	}
 }
+
+// SetCompressMinSize sets the minimum size in bytes a response body must have
+// to get compressed. Defaults to CompressMinSize.
+func SetCompressMinSize(size int) Option {
+	return func(ob *optionBox) {
+		ob.compressMinSize = size
+	}
+}
+
+// SetCompressSkipContentTypes sets the prefixes of the content types which
+// must not get compressed. Defaults to CompressSkipContentTypes.
+func SetCompressSkipContentTypes(prefixes ...string) Option {
+	return func(ob *optionBox) {
+		ob.compressSkipTypes = prefixes
+	}
+}
+
+// SetRateLimitIdleTimeout sets the duration after which the token bucket of an
+// inactive client gets removed. Defaults to RateLimitIdleTimeout.
+func SetRateLimitIdleTimeout(d time.Duration) Option {
+	return func(ob *optionBox) {
+		ob.rateLimitIdleTimeout = d
+	}
+}
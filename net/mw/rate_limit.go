@@ -0,0 +1,127 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mw
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/log"
+	loghttp "github.com/corestoreio/log/http"
+)
+
+// RateLimitIdleTimeout defines the default duration after which the token
+// bucket of an inactive client gets removed.
+const RateLimitIdleTimeout = 10 * time.Minute
+
+// WithRateLimit limits the requests per key with a token bucket. Each bucket
+// holds up to burst tokens and gets refilled with rps tokens per second. A
+// request without a token receives an HTTP status 429 with a Retry-After
+// header. keyFn returns the key of the bucket, for example the IP address or
+// the store code. If keyFn is nil the host of the remote address gets used.
+// Buckets get removed after being inactive for the idle timeout, see
+// SetRateLimitIdleTimeout.
+//
+// For scope based rate limiting with different storage backends use package
+// net/ratelimit.
+func WithRateLimit(rps float64, burst int, keyFn func(*http.Request) string, opts ...Option) Middleware {
+	ob := newOptionBox(opts...)
+	if keyFn == nil {
+		keyFn = remoteHost
+	}
+	tb := &tokenBuckets{
+		rps:     rps,
+		burst:   float64(burst),
+		idle:    ob.rateLimitIdleTimeout,
+		buckets: make(map[string]*tokenBucket),
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := keyFn(r)
+			ok, retryAfter := tb.take(key, time.Now())
+			if ok {
+				h.ServeHTTP(w, r)
+				return
+			}
+			if ob.log.IsDebug() {
+				ob.log.Debug("mw.WithRateLimit.limited", log.String("key", key), log.Duration("retry_after", retryAfter), loghttp.Request("request", r))
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		})
+	}
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// tokenBuckets contains a token bucket per key. Inactive buckets get removed
+// while taking a token, at most once per idle duration.
+type tokenBuckets struct {
+	rps   float64
+	burst float64
+	idle  time.Duration
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// take removes a token from the bucket of key. If the bucket is empty it
+// returns false and the duration until the next token becomes available.
+func (tb *tokenBuckets) take(key string, now time.Time) (bool, time.Duration) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	if now.Sub(tb.lastSweep) >= tb.idle {
+		for k, b := range tb.buckets {
+			if now.Sub(b.last) >= tb.idle {
+				delete(tb.buckets, k)
+			}
+		}
+		tb.lastSweep = now
+	}
+
+	b, ok := tb.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: tb.burst, last: now}
+		tb.buckets[key] = b
+	}
+	b.tokens = math.Min(tb.burst, b.tokens+now.Sub(b.last).Seconds()*tb.rps)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	if tb.rps <= 0 {
+		return false, tb.idle
+	}
+	return false, time.Duration((1 - b.tokens) / tb.rps * float64(time.Second))
+}
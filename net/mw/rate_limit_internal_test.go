@@ -0,0 +1,48 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mw
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBuckets_Evict(t *testing.T) {
+	tb := &tokenBuckets{
+		rps:     1,
+		burst:   1,
+		idle:    time.Minute,
+		buckets: make(map[string]*tokenBucket),
+	}
+	now := time.Now()
+	ok, _ := tb.take("a", now)
+	assert.True(t, ok)
+	ok, retry := tb.take("a", now.Add(time.Second/2))
+	assert.False(t, ok)
+	assert.Exactly(t, time.Second/2, retry)
+
+	ok, _ = tb.take("b", now.Add(30*time.Second))
+	assert.True(t, ok)
+	assert.Len(t, tb.buckets, 2)
+
+	// a has been inactive for longer than a minute, b not
+	ok, _ = tb.take("c", now.Add(70*time.Second))
+	assert.True(t, ok)
+	assert.Len(t, tb.buckets, 2)
+	assert.NotNil(t, tb.buckets["b"])
+	assert.Nil(t, tb.buckets["a"])
+}
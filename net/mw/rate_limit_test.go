@@ -0,0 +1,74 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mw_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	// 20 requests per second refill one token every 50ms
+	finalCH := mw.ChainFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}, mw.WithRateLimit(20, 3, func(r *http.Request) string {
+		return r.Header.Get("X-Store")
+	}))
+
+	serve := func(store string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://corestore.io/catalog/product/id/3452", nil)
+		r.Header.Set("X-Store", store)
+		finalCH.ServeHTTP(w, r)
+		return w
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.Exactly(t, http.StatusOK, serve("de").Code, "Request %d", i)
+	}
+
+	w := serve("de")
+	assert.Exactly(t, http.StatusTooManyRequests, w.Code)
+	assert.Exactly(t, "1", w.Header().Get("Retry-After"))
+
+	// other keys have their own bucket
+	assert.Exactly(t, http.StatusOK, serve("at").Code)
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Exactly(t, http.StatusOK, serve("de").Code)
+	assert.Exactly(t, http.StatusTooManyRequests, serve("de").Code)
+}
+
+func TestWithRateLimit_RemoteAddr(t *testing.T) {
+	finalCH := mw.ChainFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}, mw.WithRateLimit(1, 1, nil))
+
+	serve := func(remoteAddr string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://corestore.io", nil)
+		r.RemoteAddr = remoteAddr
+		finalCH.ServeHTTP(w, r)
+		return w.Code
+	}
+	assert.Exactly(t, http.StatusOK, serve("192.168.0.1:4711"))
+	assert.Exactly(t, http.StatusTooManyRequests, serve("192.168.0.1:4712"))
+	assert.Exactly(t, http.StatusOK, serve("192.168.0.2:4711"))
+}
@@ -17,8 +17,10 @@ package request
 // crypto/rand => http://blog.sgmansfield.com/2016/06/managing-syscall-overhead-with-crypto-rand/
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"os"
@@ -33,7 +35,7 @@ import (
 
 // HeaderIDKeyName defines the name of the header used to transmit the request
 // ID.
-const HeaderIDKeyName = "X-Request-Id"
+const HeaderIDKeyName = mw.RequestIDHeader
 
 // ID represents a middleware for request Id generation and adding the ID to the
 // header.
@@ -44,11 +46,49 @@ type ID struct {
 	// Count defines the optional start value. If nil starts at zero. To access
 	// securely "Count" you must use the atomic package.
 	Count *uint64
-	// NewIDFunc generates a new ID. Can be nil.
+	// NewIDFunc generates a new ID. Can be nil and falls back to host
+	// prefixed sequential IDs which might collide across multiple instances.
+	// Use NewUUID for globally unique IDs.
 	NewIDFunc func(*http.Request) string
 	log.Logger
 }
 
+type keyCtxID struct{}
+
+// withContextID creates a new context with the request ID attached.
+func withContextID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, keyCtxID{}, id)
+}
+
+// IDFromContext returns the request ID which has been added by the middleware
+// of type ID. Returns false if the context contains no ID.
+func IDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(keyCtxID{}).(string)
+	return id, ok
+}
+
+// NewUUID generates a random version 4 UUID as defined in RFC 4122. It can be
+// assigned to ID.NewIDFunc to create globally unique request IDs.
+func NewUUID(*http.Request) string {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		panic(err) // todo remove panic without giving up error reporting
+	}
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // variant RFC 4122
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
 func (iw *ID) newID() func(*http.Request) string {
 
 	// algorithm taken from https://github.com/zenazn/goji/blob/master/web/middleware/request_id.go#L40-L52
@@ -103,7 +143,7 @@ func (iw *ID) With() mw.Middleware {
 				iw.Debug("request.ID.With", log.String("id", id), loghttp.Request("request", r))
 			}
 			w.Header().Set(iw.HeaderIDKeyName, id)
-			h.ServeHTTP(w, r)
+			h.ServeHTTP(w, r.WithContext(withContextID(r.Context(), id)))
 		})
 	}
 }
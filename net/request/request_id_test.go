@@ -15,11 +15,13 @@
 package request_test
 
 import (
+	"context"
 	"net/http/httptest"
 	"testing"
 
 	"net/http"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/corestoreio/csfw/net/mw"
@@ -51,6 +53,57 @@ func TestDefaultRequestPrefix(t *testing.T) {
 	assert.Exactly(t, 50, int(*idGen.Count))
 }
 
+func TestNewUUID(t *testing.T) {
+	const count = 10000
+	matchr := regexp.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$")
+	seen := make(map[string]struct{}, count)
+	for i := 0; i < count; i++ {
+		id := request.NewUUID(nil)
+		assert.True(t, matchr.MatchString(id), "ID %q is not a v4 UUID", id)
+		seen[id] = struct{}{}
+	}
+	assert.Len(t, seen, count)
+}
+
+func TestID_With_UUID(t *testing.T) {
+	id := &request.ID{NewIDFunc: request.NewUUID}
+	final := func(w http.ResponseWriter, r *http.Request) {}
+	// calling With twice must not overwrite the already applied settings
+	_ = id.With()
+	finalCH := mw.ChainFunc(final, id.With())
+
+	ids := make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		w := httptest.NewRecorder()
+		finalCH.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		have := w.Header().Get(request.HeaderIDKeyName)
+		assert.False(t, strings.Contains(have, "/"), "ID %q must not have a host prefix", have)
+		ids[have] = struct{}{}
+	}
+	assert.Len(t, ids, 100)
+	assert.Exactly(t, request.HeaderIDKeyName, id.HeaderIDKeyName)
+}
+
+func TestIDFromContext(t *testing.T) {
+	id := &request.ID{
+		NewIDFunc: func(*http.Request) string { return "gopher-4711" },
+	}
+	var called bool
+	finalCH := mw.ChainFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		have, ok := request.IDFromContext(r.Context())
+		assert.True(t, ok)
+		assert.Exactly(t, "gopher-4711", have)
+	}, id.With())
+
+	finalCH.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.True(t, called)
+
+	have, ok := request.IDFromContext(context.Background())
+	assert.False(t, ok)
+	assert.Empty(t, have)
+}
+
 func BenchmarkWithRequestID(b *testing.B) {
 	id := &request.ID{}
 	finalCH := mw.ChainFunc(func(w http.ResponseWriter, r *http.Request) {
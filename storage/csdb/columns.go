@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/util/bufferpool"
@@ -66,6 +67,9 @@ type Column struct {
 	// DataTypeSimple contains the simplified data type of the field DataType.
 	// Fo example bigint, smallint, tinyiny will result in "int".
 	dataTypeSimple string
+	// isIndexed set to true if the column is part of any index loaded with
+	// WithLoadIndexes.
+	isIndexed bool
 }
 
 // DMLLoadColumns specifies the data manipulation language for retrieving all
@@ -344,14 +348,77 @@ func (c *Column) IsUnique() bool {
 	return c.Field != "" && c.Key == columnUnique
 }
 
+// IsIndexed checks if column is part of an index. Without loaded indexes only
+// the column key (PRI, UNI or MUL) gets considered, which covers the first
+// column of an index.
+func (c *Column) IsIndexed() bool {
+	return c.Field != "" && (c.isIndexed || c.Key != "")
+}
+
 // IsAutoIncrement checks if column has an auto increment property
 func (c *Column) IsAutoIncrement() bool {
 	return c.Field != "" && c.Extra == columnAutoIncrement
 }
 
-// IsUnsigned checks if field TypeRaw contains the word unsigned.
+// IsUnsigned checks if field ColumnType contains the token unsigned, for
+// example int(10) unsigned or bigint(20) unsigned zerofill.
 func (c *Column) IsUnsigned() bool {
-	return strings.Contains(c.ColumnType, columnUnsigned)
+	for _, token := range strings.Fields(strings.ToLower(c.ColumnType)) {
+		if token == columnUnsigned {
+			return true
+		}
+	}
+	return false
+}
+
+// EnumValues returns the allowed values of an ENUM column parsed from the
+// field ColumnType, for example enum('enabled','disabled'). Returns nil for
+// all other column types.
+func (c *Column) EnumValues() []string {
+	return parseColumnTypeValues("enum", c.ColumnType)
+}
+
+// SetValues returns the allowed values of a SET column parsed from the field
+// ColumnType, for example set('red','green'). Returns nil for all other
+// column types.
+func (c *Column) SetValues() []string {
+	return parseColumnTypeValues("set", c.ColumnType)
+}
+
+// parseColumnTypeValues extracts the quoted values of a column type like
+// enum('a','b,c'). Commas within the quotes are part of the value and a
+// doubled single quote gets unescaped.
+func parseColumnTypeValues(typ, columnType string) []string {
+	if len(columnType) < len(typ)+2 || !strings.EqualFold(columnType[:len(typ)+1], typ+"(") {
+		return nil
+	}
+	list := columnType[len(typ)+1:]
+	end := strings.LastIndexByte(list, ')')
+	if end < 0 {
+		return nil
+	}
+	list = list[:end]
+
+	var vals []string
+	var buf []byte
+	inQuote := false
+	for i := 0; i < len(list); i++ {
+		ch := list[i]
+		switch {
+		case ch == '\'' && inQuote && i+1 < len(list) && list[i+1] == '\'':
+			buf = append(buf, ch)
+			i++
+		case ch == '\'':
+			if inQuote {
+				vals = append(vals, string(buf))
+				buf = buf[:0]
+			}
+			inQuote = !inQuote
+		case inQuote:
+			buf = append(buf, ch)
+		}
+	}
+	return vals
 }
 
 // IsCurrentTimestamp checks if the Default field is a current timestamp
@@ -359,6 +426,56 @@ func (c *Column) IsCurrentTimestamp() bool {
 	return c.Default.String == columnCurrentTimestamp
 }
 
+// DefaultValue returns the typed default value of the column depending on
+// DataType. Integer types return an int64, decimal, float and double a
+// float64, date, datetime and timestamp a time.Time and all other types a
+// string. A missing default or an explicit NULL returns nil. If the literal
+// cannot be parsed, for example a zero date 0000-00-00, the raw string gets
+// returned. The second return value reports true if the default is an
+// expression like CURRENT_TIMESTAMP instead of a literal. In that case the
+// first return value contains the raw expression.
+func (c *Column) DefaultValue() (interface{}, bool) {
+	if !c.Default.Valid || c.Default.String == "NULL" {
+		return nil, false
+	}
+	def := c.Default.String
+	if c.isDefaultExpression() {
+		return def, true
+	}
+	// MariaDB >= 10.2.7 quotes string literals.
+	if len(def) > 1 && def[0] == '\'' && def[len(def)-1] == '\'' {
+		def = strings.Replace(def[1:len(def)-1], "''", "'", -1)
+	}
+
+	switch c.DataType {
+	case "bigint", "int", "mediumint", "smallint", "tinyint":
+		if i, err := strconv.ParseInt(def, 10, 64); err == nil {
+			return i, false
+		}
+	case "decimal", "float", "double":
+		if f, err := strconv.ParseFloat(def, 64); err == nil {
+			return f, false
+		}
+	case "date", "datetime", "timestamp":
+		for _, layout := range [...]string{"2006-01-02 15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, def); err == nil {
+				return t, false
+			}
+		}
+	}
+	return def, false
+}
+
+// isDefaultExpression checks if the default value is a function call like
+// CURRENT_TIMESTAMP, current_timestamp() (MariaDB) or NOW() or if MySQL >= 8
+// flags the default as generated.
+func (c *Column) isDefaultExpression() bool {
+	def := strings.ToUpper(c.Default.String)
+	return strings.HasPrefix(def, columnCurrentTimestamp) ||
+		strings.HasPrefix(def, "NOW(") ||
+		strings.Contains(c.Extra, "DEFAULT_GENERATED")
+}
+
 const (
 	colTypeBool   = "bool"
 	colTypeByte   = "bytes"
@@ -456,6 +573,12 @@ func (c *Column) goPrimitive(useNullType bool) string {
 		if isNull {
 			goType = "null.Int64"
 		}
+		if c.IsUnsigned() {
+			goType = "uint64"
+			if isNull {
+				goType = "null.Uint64"
+			}
+		}
 	case colTypeString:
 		goType = "string"
 		if isNull {
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/corestoreio/csfw/storage/csdb"
 	"github.com/corestoreio/csfw/util/cstesting"
@@ -405,6 +406,17 @@ var adminUserColumns = csdb.Columns{
 	&csdb.Column{Field: "lock_expires", Pos: 19, Default: null.String{}, Null: "YES", DataType: "timestamp", CharMaxLength: null.Int64{}, Precision: null.Int64{}, Scale: null.Int64{}, ColumnType: "timestamp", Key: "", Extra: "", Comment: "Expiration Lock Dates"},
 }
 
+func TestColumn_GoPrimitive_Unsigned(t *testing.T) {
+	t.Parallel()
+	c := &csdb.Column{Field: "entity_id", DataType: "int", ColumnType: "int(10) unsigned", Null: "YES"}
+	assert.Exactly(t, "uint64", c.GoPrimitive())
+	assert.Exactly(t, "null.Uint64", c.GoPrimitiveNull())
+
+	c = &csdb.Column{Field: "entity_id", DataType: "bigint", ColumnType: "bigint(20)", Null: "YES"}
+	assert.Exactly(t, "int64", c.GoPrimitive())
+	assert.Exactly(t, "null.Int64", c.GoPrimitiveNull())
+}
+
 func TestColumnsSort(t *testing.T) {
 	//t.Parallel() a slice is not thread safe ;-)
 	//sort.Reverse(adminUserColumns) doesn't work and not yet needed
@@ -424,6 +436,51 @@ func TestColumn_IsUnsigned(t *testing.T) {
 	t.Parallel()
 	assert.True(t, adminUserColumns.ByField("lognum").IsUnsigned())
 	assert.False(t, adminUserColumns.ByField("reload_acl_flag").IsUnsigned())
+
+	tests := []struct {
+		columnType string
+		want       bool
+	}{
+		{"int(10) unsigned", true},
+		{"bigint(20) unsigned", true},
+		{"smallint(5) unsigned zerofill", true},
+		{"INT(10) UNSIGNED", true},
+		{"int(11)", false},
+		{"bigint(20)", false},
+		{"decimal(12,4)", false},
+		{"varchar(255)", false},
+		{"enum('unsigned','signed')", false},
+		{"", false},
+	}
+	for i, test := range tests {
+		c := &csdb.Column{ColumnType: test.columnType}
+		assert.Exactly(t, test.want, c.IsUnsigned(), "Index %d => %q", i, test.columnType)
+	}
+}
+
+func TestColumn_EnumSetValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		dataType   string
+		columnType string
+		wantEnum   []string
+		wantSet    []string
+	}{
+		{"enum", "enum('enabled','disabled')", []string{"enabled", "disabled"}, nil},
+		{"enum", "ENUM('a,b','c')", []string{"a,b", "c"}, nil},
+		{"enum", "enum('it''s','','x)y')", []string{"it's", "", "x)y"}, nil},
+		{"set", "set('red','green','blue')", nil, []string{"red", "green", "blue"}},
+		{"set", "set('a, b','c')", nil, []string{"a, b", "c"}},
+		{"varchar", "varchar(255)", nil, nil},
+		{"int", "int(10) unsigned", nil, nil},
+		{"", "", nil, nil},
+	}
+	for i, test := range tests {
+		c := &csdb.Column{DataType: test.dataType, ColumnType: test.columnType}
+		assert.Exactly(t, test.wantEnum, c.EnumValues(), "Index %d", i)
+		assert.Exactly(t, test.wantSet, c.SetValues(), "Index %d", i)
+	}
 }
 
 func TestColumn_DataTypeSimple(t *testing.T) {
@@ -438,6 +495,34 @@ func TestColumn_IsCurrentTimestamp(t *testing.T) {
 	assert.False(t, adminUserColumns.ByField("reload_acl_flag").IsCurrentTimestamp())
 }
 
+func TestColumn_DefaultValue(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		col      *csdb.Column
+		wantVal  interface{}
+		wantExpr bool
+	}{
+		{&csdb.Column{DataType: "int", Default: null.StringFrom("0")}, int64(0), false},
+		{&csdb.Column{DataType: "smallint", Default: null.StringFrom("-5")}, int64(-5), false},
+		{&csdb.Column{DataType: "decimal", Default: null.StringFrom("0.0000")}, float64(0), false},
+		{&csdb.Column{DataType: "varchar", Default: null.StringFrom("general")}, "general", false},
+		{&csdb.Column{DataType: "varchar", Default: null.StringFrom("'it''s'")}, "it's", false},
+		{&csdb.Column{DataType: "varchar", Default: null.StringFrom("")}, "", false},
+		{&csdb.Column{DataType: "timestamp", Default: null.StringFrom("CURRENT_TIMESTAMP")}, "CURRENT_TIMESTAMP", true},
+		{&csdb.Column{DataType: "timestamp", Default: null.StringFrom("current_timestamp()")}, "current_timestamp()", true},
+		{&csdb.Column{DataType: "datetime", Default: null.StringFrom("2016-11-07 13:14:15")}, time.Date(2016, 11, 7, 13, 14, 15, 0, time.UTC), false},
+		{&csdb.Column{DataType: "date", Default: null.StringFrom("2016-11-07")}, time.Date(2016, 11, 7, 0, 0, 0, 0, time.UTC), false},
+		{&csdb.Column{DataType: "timestamp", Default: null.StringFrom("0000-00-00 00:00:00")}, "0000-00-00 00:00:00", false},
+		{&csdb.Column{DataType: "int", Default: null.StringFrom("NULL")}, nil, false},
+		{&csdb.Column{DataType: "varchar", Default: null.String{}}, nil, false},
+	}
+	for i, test := range tests {
+		haveVal, haveExpr := test.col.DefaultValue()
+		assert.Exactly(t, test.wantVal, haveVal, "Index %d", i)
+		assert.Exactly(t, test.wantExpr, haveExpr, "Index %d", i)
+	}
+}
+
 var benchmarkGetColumns map[string]csdb.Columns
 var benchmarkGetColumnsHashWant = []byte{0x3b, 0x2d, 0xdd, 0xf4, 0x4e, 0x2b, 0x3a, 0xd0}
 
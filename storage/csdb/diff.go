@@ -0,0 +1,141 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csdb
+
+import "sort"
+
+// TableDiff describes the differences of one table between two schema
+// snapshots. A table gets identified by its index in Tables.
+type TableDiff struct {
+	// Index of the table in Tables.
+	Index int
+	// Name of the table in the new snapshot or in the old snapshot if the
+	// table has been removed.
+	Name string
+	// Added set to true if the table only exists in the new snapshot.
+	Added bool
+	// Removed set to true if the table only exists in the old snapshot.
+	Removed bool
+	// AddedColumns contains the columns which only exist in the new table.
+	AddedColumns Columns
+	// RemovedColumns contains the columns which only exist in the old table.
+	RemovedColumns Columns
+	// ChangedColumns contains the columns whose type, nullability or default
+	// value differ.
+	ChangedColumns []ColumnDiff
+}
+
+// ColumnDiff contains the old and the new definition of a changed column.
+type ColumnDiff struct {
+	Old *Column
+	New *Column
+}
+
+// Diff compares two schema snapshots and returns the differences sorted by
+// the table index. Tables are matched by their index and columns by their
+// Field name, so the order of the tables and columns doesn't matter. Columns
+// are compared by their ColumnType, nullability and default value. Unchanged
+// tables are not part of the returned slice. A nil Tables argument gets
+// treated as an empty snapshot.
+func Diff(old, new *Tables) []TableDiff {
+	oldTS := old.snapshot()
+	newTS := new.snapshot()
+
+	idxs := make([]int, 0, len(oldTS)+len(newTS))
+	for i := range oldTS {
+		idxs = append(idxs, i)
+	}
+	for i := range newTS {
+		if _, ok := oldTS[i]; !ok {
+			idxs = append(idxs, i)
+		}
+	}
+	sort.Ints(idxs)
+
+	var diffs []TableDiff
+	for _, i := range idxs {
+		tOld, hasOld := oldTS[i]
+		tNew, hasNew := newTS[i]
+		switch {
+		case !hasOld:
+			diffs = append(diffs, TableDiff{Index: i, Name: tNew.Name, Added: true, AddedColumns: tNew.Columns})
+		case !hasNew:
+			diffs = append(diffs, TableDiff{Index: i, Name: tOld.Name, Removed: true, RemovedColumns: tOld.Columns})
+		default:
+			if td := diffColumns(i, tOld, tNew); td.hasChanges() {
+				diffs = append(diffs, td)
+			}
+		}
+	}
+	return diffs
+}
+
+func diffColumns(idx int, tOld, tNew *Table) TableDiff {
+	td := TableDiff{
+		Index: idx,
+		Name:  tNew.Name,
+	}
+	for _, cNew := range tNew.Columns {
+		cOld := findColumn(tOld.Columns, cNew.Field)
+		switch {
+		case cOld == nil:
+			td.AddedColumns = append(td.AddedColumns, cNew)
+		case !equalColumnDefinition(cOld, cNew):
+			td.ChangedColumns = append(td.ChangedColumns, ColumnDiff{Old: cOld, New: cNew})
+		}
+	}
+	for _, cOld := range tOld.Columns {
+		if findColumn(tNew.Columns, cOld.Field) == nil {
+			td.RemovedColumns = append(td.RemovedColumns, cOld)
+		}
+	}
+	return td
+}
+
+func (td TableDiff) hasChanges() bool {
+	return td.Added || td.Removed || len(td.AddedColumns) > 0 || len(td.RemovedColumns) > 0 || len(td.ChangedColumns) > 0
+}
+
+// findColumn returns nil if the column cannot be found. ByField can't be used
+// because it never returns nil.
+func findColumn(cs Columns, field string) *Column {
+	for _, c := range cs {
+		if c.Field == field {
+			return c
+		}
+	}
+	return nil
+}
+
+func equalColumnDefinition(a, b *Column) bool {
+	return a.ColumnType == b.ColumnType &&
+		a.IsNull() == b.IsNull() &&
+		a.Default.Valid == b.Default.Valid &&
+		a.Default.String == b.Default.String
+}
+
+// snapshot returns a shallow copy of the internal table map. Thread safe.
+func (tm *Tables) snapshot() map[int]*Table {
+	if tm == nil {
+		return nil
+	}
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	ts := make(map[int]*Table, len(tm.ts))
+	for i, t := range tm.ts {
+		ts[i] = t
+	}
+	return ts
+}
@@ -0,0 +1,94 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csdb_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/util/null"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	old := csdb.MustNewTables(
+		csdb.WithTable(1, "admin_user",
+			&csdb.Column{Field: "user_id", ColumnType: "int(10) unsigned", Null: "NO", Key: "PRI"},
+			&csdb.Column{Field: "email", ColumnType: "varchar(128)", Null: "YES"},
+		),
+		csdb.WithTable(2, "admin_passwords",
+			&csdb.Column{Field: "password_id", ColumnType: "int(10) unsigned", Null: "NO", Key: "PRI"},
+		),
+		csdb.WithTable(3, "core_config_data",
+			&csdb.Column{Field: "config_id", ColumnType: "int(10) unsigned", Null: "NO", Key: "PRI"},
+		),
+	)
+	new := csdb.MustNewTables(
+		csdb.WithTable(1, "admin_user",
+			// changed column order must not be reported
+			&csdb.Column{Field: "email", ColumnType: "varchar(255)", Null: "YES"},
+			&csdb.Column{Field: "user_id", ColumnType: "int(10) unsigned", Null: "NO", Key: "PRI"},
+			&csdb.Column{Field: "is_active", ColumnType: "smallint(6)", Null: "NO", Default: null.StringFrom("1")},
+		),
+		csdb.WithTable(3, "core_config_data",
+			&csdb.Column{Field: "config_id", ColumnType: "int(10) unsigned", Null: "NO", Key: "PRI"},
+		),
+		csdb.WithTable(4, "store_website",
+			&csdb.Column{Field: "website_id", ColumnType: "smallint(5) unsigned", Null: "NO", Key: "PRI"},
+		),
+	)
+
+	diffs := csdb.Diff(old, new)
+	require.Len(t, diffs, 3)
+
+	d := diffs[0]
+	assert.Exactly(t, 1, d.Index)
+	assert.Exactly(t, "admin_user", d.Name)
+	assert.False(t, d.Added)
+	assert.False(t, d.Removed)
+	assert.Exactly(t, []string{"is_active"}, d.AddedColumns.FieldNames())
+	assert.Empty(t, d.RemovedColumns)
+	require.Len(t, d.ChangedColumns, 1)
+	assert.Exactly(t, "varchar(128)", d.ChangedColumns[0].Old.ColumnType)
+	assert.Exactly(t, "varchar(255)", d.ChangedColumns[0].New.ColumnType)
+
+	d = diffs[1]
+	assert.Exactly(t, 2, d.Index)
+	assert.True(t, d.Removed)
+	assert.Exactly(t, []string{"password_id"}, d.RemovedColumns.FieldNames())
+
+	d = diffs[2]
+	assert.Exactly(t, 4, d.Index)
+	assert.Exactly(t, "store_website", d.Name)
+	assert.True(t, d.Added)
+	assert.Exactly(t, []string{"website_id"}, d.AddedColumns.FieldNames())
+
+	t.Run("no changes", func(t *testing.T) {
+		assert.Empty(t, csdb.Diff(new, new))
+	})
+	t.Run("nil old", func(t *testing.T) {
+		assert.Len(t, csdb.Diff(nil, new), 3)
+	})
+	t.Run("null and default changes", func(t *testing.T) {
+		o := csdb.MustNewTables(csdb.WithTable(1, "a", &csdb.Column{Field: "f", ColumnType: "int(11)", Null: "NO"}))
+		n := csdb.MustNewTables(csdb.WithTable(1, "a", &csdb.Column{Field: "f", ColumnType: "int(11)", Null: "YES"}))
+		assert.Len(t, csdb.Diff(o, n), 1)
+		n = csdb.MustNewTables(csdb.WithTable(1, "a", &csdb.Column{Field: "f", ColumnType: "int(11)", Null: "NO", Default: null.StringFrom("0")}))
+		assert.Len(t, csdb.Diff(o, n), 1)
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csdb
+
+import (
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/errors"
+)
+
+// ForeignKeys contains a slice of foreign key columns.
+type ForeignKeys []*ForeignKey
+
+// ForeignKey contains one column of a foreign key constraint retrieved from
+// information_schema.KEY_COLUMN_USAGE and
+// information_schema.REFERENTIAL_CONSTRAINTS. A constraint spanning multiple
+// columns results in multiple ForeignKey entries with the same
+// ConstraintName.
+type ForeignKey struct {
+	ConstraintName   string `db:"CONSTRAINT_NAME"`        // `CONSTRAINT_NAME` varchar(64) NOT NULL DEFAULT '',
+	Column           string `db:"COLUMN_NAME"`            // `COLUMN_NAME` varchar(64) NOT NULL DEFAULT '',
+	ReferencedTable  string `db:"REFERENCED_TABLE_NAME"`  // `REFERENCED_TABLE_NAME` varchar(64) DEFAULT NULL,
+	ReferencedColumn string `db:"REFERENCED_COLUMN_NAME"` // `REFERENCED_COLUMN_NAME` varchar(64) DEFAULT NULL,
+	// OnUpdate contains the rule like CASCADE, SET NULL, RESTRICT or NO
+	// ACTION.
+	OnUpdate string `db:"UPDATE_RULE"` // `UPDATE_RULE` varchar(64) NOT NULL DEFAULT '',
+	// OnDelete contains the rule like CASCADE, SET NULL, RESTRICT or NO
+	// ACTION.
+	OnDelete string `db:"DELETE_RULE"` // `DELETE_RULE` varchar(64) NOT NULL DEFAULT '',
+}
+
+// LoadForeignKeys returns all foreign keys from a list of tables in the
+// current database. Map key contains the table name. The foreign keys of a
+// table are sorted by constraint name and column position.
+func LoadForeignKeys(db dbr.Querier, tables ...string) (map[string]ForeignKeys, error) {
+
+	sel := dbr.NewSelect("information_schema.KEY_COLUMN_USAGE", "kcu").AddColumns(
+		`kcu.TABLE_NAME,kcu.CONSTRAINT_NAME,kcu.COLUMN_NAME,kcu.REFERENCED_TABLE_NAME,
+		kcu.REFERENCED_COLUMN_NAME,rc.UPDATE_RULE,rc.DELETE_RULE`).
+		Join(dbr.JoinTable("information_schema.REFERENTIAL_CONSTRAINTS", "rc"), nil,
+			dbr.ConditionRaw(`kcu.CONSTRAINT_SCHEMA=rc.CONSTRAINT_SCHEMA AND kcu.TABLE_NAME=rc.TABLE_NAME AND kcu.CONSTRAINT_NAME=rc.CONSTRAINT_NAME`),
+		).
+		Where(
+			dbr.ConditionRaw(`kcu.TABLE_SCHEMA=DATABASE()`),
+			dbr.ConditionNotNull(`kcu.REFERENCED_TABLE_NAME`),
+		).
+		OrderBy("kcu.TABLE_NAME").OrderBy("kcu.CONSTRAINT_NAME").OrderBy("kcu.ORDINAL_POSITION")
+	sel.DB.Querier = db
+	if len(tables) > 0 {
+		sel.Where(dbr.ConditionRawExpand("kcu.TABLE_NAME IN ?", tables))
+	}
+
+	rows, err := sel.Rows()
+	if err != nil {
+		return nil, errors.Wrapf(err, "[csdb] LoadForeignKeys QueryContext for tables %v", tables)
+	}
+	defer rows.Close()
+
+	tfk := make(map[string]ForeignKeys)
+
+	var tn string
+	for rows.Next() {
+		fk := new(ForeignKey)
+		if err := rows.Scan(&tn, &fk.ConstraintName, &fk.Column, &fk.ReferencedTable, &fk.ReferencedColumn, &fk.OnUpdate, &fk.OnDelete); err != nil {
+			return nil, errors.Wrap(err, "[csdb] LoadForeignKeys Scan Query")
+		}
+		tfk[tn] = append(tfk[tn], fk)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "[csdb] LoadForeignKeys rows.Err Query")
+	}
+	return tfk, nil
+}
+
+// ReferencedTables returns the unique names of the referenced tables in the
+// order of their occurrence.
+func (fks ForeignKeys) ReferencedTables() []string {
+	var tables []string
+	seen := make(map[string]bool, len(fks))
+	for _, fk := range fks {
+		if !seen[fk.ReferencedTable] {
+			seen[fk.ReferencedTable] = true
+			tables = append(tables, fk.ReferencedTable)
+		}
+	}
+	return tables
+}
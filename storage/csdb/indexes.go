@@ -0,0 +1,83 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csdb
+
+import (
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/errors"
+)
+
+// Indexes contains a slice of table indexes.
+type Indexes []*Index
+
+// Index contains the definition of a table index retrieved from
+// information_schema.STATISTICS.
+type Index struct {
+	Name string `db:"INDEX_NAME"` // `INDEX_NAME` varchar(64) NOT NULL DEFAULT '',
+	// Columns contains the column names sorted by SEQ_IN_INDEX.
+	Columns []string
+	// Unique set to true if NON_UNIQUE is zero.
+	Unique bool
+	// Type contains the index type like BTREE, HASH or FULLTEXT.
+	Type string `db:"INDEX_TYPE"` // `INDEX_TYPE` varchar(16) NOT NULL DEFAULT '',
+}
+
+// LoadIndexes returns all indexes from a list of tables in the current
+// database. Map key contains the table name. The indexes of a table are
+// sorted by name.
+func LoadIndexes(db dbr.Querier, tables ...string) (map[string]Indexes, error) {
+
+	sel := dbr.NewSelect("information_schema.STATISTICS").AddColumns(
+		`TABLE_NAME,INDEX_NAME,COLUMN_NAME,NON_UNIQUE,INDEX_TYPE`).
+		Where(dbr.ConditionRaw(`TABLE_SCHEMA=DATABASE()`)).
+		OrderBy("TABLE_NAME").OrderBy("INDEX_NAME").OrderBy("SEQ_IN_INDEX")
+	sel.DB.Querier = db
+	if len(tables) > 0 {
+		sel.Where(dbr.ConditionRawExpand("TABLE_NAME IN ?", tables))
+	}
+
+	rows, err := sel.Rows()
+	if err != nil {
+		return nil, errors.Wrapf(err, "[csdb] LoadIndexes QueryContext for tables %v", tables)
+	}
+	defer rows.Close()
+
+	ti := make(map[string]Indexes)
+
+	var tn, indexName, column, indexType string
+	var nonUnique int64
+	for rows.Next() {
+		if err := rows.Scan(&tn, &indexName, &column, &nonUnique, &indexType); err != nil {
+			return nil, errors.Wrap(err, "[csdb] LoadIndexes Scan Query")
+		}
+		// rows are sorted, so a new index starts when the name differs from
+		// the last index of the table.
+		idxs := ti[tn]
+		if len(idxs) == 0 || idxs[len(idxs)-1].Name != indexName {
+			idxs = append(idxs, &Index{
+				Name:   indexName,
+				Unique: nonUnique == 0,
+				Type:   indexType,
+			})
+			ti[tn] = idxs
+		}
+		idx := idxs[len(idxs)-1]
+		idx.Columns = append(idx.Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "[csdb] LoadIndexes rows.Err Query")
+	}
+	return ti, nil
+}
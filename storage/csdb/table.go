@@ -16,8 +16,11 @@ package csdb
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/corestoreio/csfw/storage/dbr"
@@ -37,6 +40,12 @@ type Table struct {
 	CountPK int
 	// CountUnique number of unique keys. Auto updated.
 	CountUnique int
+	// ForeignKeys contains the foreign keys of the table. Gets only loaded
+	// with the option WithLoadForeignKeys.
+	ForeignKeys ForeignKeys
+	// Indexes contains the indexes of the table. Gets only loaded with the
+	// option WithLoadIndexes.
+	Indexes Indexes
 	// Listeners specific pre defined listeners which gets dispatches to each
 	// DML statement (SELECT, INSERT, UPDATE or DELETE).
 	Listeners dbr.ListenerBucket
@@ -65,12 +74,22 @@ func (t *Table) update() *Table {
 	if len(t.Columns) == 0 {
 		return t
 	}
-	t.fieldsPK = t.Columns.PrimaryKeys().FieldNames()
+	t.fieldsPK = t.PrimaryKeys().FieldNames()
 	t.fieldsUNI = t.Columns.UniqueKeys().FieldNames()
 	t.fields = t.Columns.ColumnsNoPK().FieldNames()
 	t.CountPK = t.Columns.PrimaryKeys().Len()
 	t.CountUnique = t.Columns.UniqueKeys().Len()
 
+	indexed := make(map[string]bool)
+	for _, idx := range t.Indexes {
+		for _, c := range idx.Columns {
+			indexed[c] = true
+		}
+	}
+	for _, c := range t.Columns {
+		c.isIndexed = indexed[c.Field]
+	}
+
 	t.selectAllCache = &dbr.Select{
 		Columns:   t.AllColumnAliasQuote(MainTable),
 		FromTable: dbr.MakeAlias(t.Name, MainTable),
@@ -79,6 +98,30 @@ func (t *Table) update() *Table {
 	return t
 }
 
+// PrimaryKeys returns all primary key columns sorted by their sequence in the
+// PRIMARY index. Tables with a composite primary key return more than one
+// column. Without loaded indexes, see WithLoadIndexes, the columns get sorted
+// by their ordinal position.
+func (t *Table) PrimaryKeys() Columns {
+	pks := t.Columns.PrimaryKeys()
+	for _, idx := range t.Indexes {
+		if idx.Name != "PRIMARY" || len(idx.Columns) != len(pks) {
+			continue
+		}
+		sorted := make(Columns, 0, len(pks))
+		for _, f := range idx.Columns {
+			if c := pks.ByField(f); c != nil {
+				sorted = append(sorted, c)
+			}
+		}
+		if len(sorted) == len(pks) {
+			return sorted
+		}
+	}
+	sort.Stable(pks)
+	return pks
+}
+
 // LoadColumns reads the column information from the DB.
 func (t *Table) LoadColumns(db dbr.Querier) error {
 	tc, err := LoadColumns(db, t.Name)
@@ -117,6 +160,12 @@ func (t *Table) AllColumnAliasQuote(alias string) []string {
 	return dbr.Quoter.TableColumnAlias(alias, sl...)
 }
 
+// ReferencedTables returns the unique names of the tables referenced by the
+// foreign keys of this table.
+func (t *Table) ReferencedTables() []string {
+	return t.ForeignKeys.ReferencedTables()
+}
+
 // IsView identifies if a table is a view
 func (t *Table) IsView() bool {
 	return t.isView
@@ -138,10 +187,11 @@ func (t *Table) In(n string) bool {
 }
 
 // Truncate truncates the tables. Removes all rows and sets the auto increment
-// to zero. Just like a CREATE TABLE statement.
+// to zero. Just like a CREATE TABLE statement. Returns a NotAllowed error if
+// the table is a view.
 func (t *Table) Truncate(execer dbr.Execer) error {
 	if t.isView {
-		return nil
+		return errors.NewNotAllowedf("[csdb] Truncate not allowed for view %q", t.Name)
 	}
 	ddl := "TRUNCATE TABLE " + dbr.Quoter.QuoteAs(t.Name)
 	_, err := execer.Exec(ddl)
@@ -152,8 +202,12 @@ func (t *Table) Truncate(execer dbr.Execer) error {
 // operation in the database. As long as two databases are on the same file
 // system, you can use RENAME TABLE to move a table from one database to
 // another. RENAME TABLE also works for views, as long as you do not try to
-// rename a view into a different database.
+// rename a view into a different database. An invalid new name returns a
+// NotValid error.
 func (t *Table) Rename(execer dbr.Execer, new string) error {
+	if err := IsValidIdentifier(strings.SplitN(new, ".", 2)...); err != nil {
+		return errors.Wrap(err, "[csdb] Rename.IsValidIdentifier")
+	}
 	ddl := "RENAME TABLE " + dbr.Quoter.QuoteAs(t.Name) + " TO " + dbr.Quoter.QuoteAs(new)
 	_, err := execer.Exec(ddl)
 	return errors.Wrapf(err, "[csdb] failed to rename table %q", ddl)
@@ -162,8 +216,12 @@ func (t *Table) Rename(execer dbr.Execer, new string) error {
 // Swap swaps the current table with the other table of the same structure.
 // Renaming is an atomic operation in the database. Note: indexes won't get
 // swapped! As long as two databases are on the same file system, you can use
-// RENAME TABLE to move a table from one database to another.
+// RENAME TABLE to move a table from one database to another. An invalid
+// other name returns a NotValid error.
 func (t *Table) Swap(execer dbr.Execer, other string) error {
+	if err := IsValidIdentifier(other); err != nil {
+		return errors.Wrap(err, "[csdb] Swap.IsValidIdentifier")
+	}
 	tmp := t.Name + "_swap_" + strconv.FormatInt(time.Now().UnixNano(), 10)
 	if len(tmp) > 64 { // https://dev.mysql.com/doc/refman/5.7/en/identifiers.html
 		tmp = tmp[:64]
@@ -175,13 +233,13 @@ func (t *Table) Swap(execer dbr.Execer, other string) error {
 	return errors.Wrapf(err, "[csdb] failed to swap table %q", ddl)
 }
 
-// Drop, if exists, drops the table or the view.
+// Drop, if exists, drops the table. Returns a NotAllowed error if the table is
+// a view.
 func (t *Table) Drop(execer dbr.Execer) error {
-	typ := "TABLE"
 	if t.isView {
-		typ = "VIEW"
+		return errors.NewNotAllowedf("[csdb] Drop not allowed for view %q", t.Name)
 	}
-	_, err := execer.Exec("DROP " + typ + " IF EXISTS " + dbr.Quoter.QuoteAs(t.Name))
+	_, err := execer.Exec("DROP TABLE IF EXISTS " + dbr.Quoter.QuoteAs(t.Name))
 	return errors.Wrapf(err, "[csdb] failed to drop table %q", t.Name)
 }
 
@@ -196,11 +254,17 @@ func (t *Table) Select() *dbr.Select {
 // into the pointer slice `dest`. Returns the number of loaded rows and nil or 0
 // and an error. The variadic third arguments can modify the SQL query.
 func (t *Table) LoadSlice(db dbr.Querier, dest interface{}, listeners ...dbr.Listen) (int, error) {
+	return t.LoadSliceContext(nil, db, dest, listeners...)
+}
+
+// LoadSliceContext same as LoadSlice but respects the context. A nil context
+// falls back to the non-context aware Query function of the database.
+func (t *Table) LoadSliceContext(ctx context.Context, db dbr.Querier, dest interface{}, listeners ...dbr.Listen) (int, error) {
 	sb := t.Select()
 	sb.DB.Querier = db
 	sb.Listeners.Merge(t.Listeners.Select)
 	sb.Listeners.Add(listeners...)
-	return sb.LoadStructs(dest)
+	return sb.LoadStructsContext(ctx, dest)
 }
 
 // InfileOptions provides options for the function LoadDataInfile. Some fields
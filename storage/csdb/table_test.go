@@ -15,6 +15,7 @@
 package csdb_test
 
 import (
+	"context"
 	"testing"
 
 	"regexp"
@@ -24,7 +25,9 @@ import (
 	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/util/cstesting"
 	"github.com/corestoreio/csfw/util/null"
+	"github.com/corestoreio/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -300,6 +303,13 @@ func TestTable_Swap(t *testing.T) {
 	assert.NoError(t, err, "%+v", err)
 }
 
+func TestTable_RenameSwap_Invalid(t *testing.T) {
+	err := tableMap.MustTable(table2).Rename(nil, "a` TO `b")
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+	err = tableMap.MustTable(table2).Swap(nil, "a`")
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
 func TestTable_Drop(t *testing.T) {
 	t.Parallel()
 
@@ -318,6 +328,67 @@ func TestTable_Drop(t *testing.T) {
 	assert.NoError(t, err, "%+v", err)
 }
 
+func TestTable_LoadSliceContext(t *testing.T) {
+	t.Parallel()
+
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	dbMock.ExpectQuery(regexp.QuoteMeta("SELECT `main_table`.`user_id`, `main_table`.`email`, `main_table`.`username` FROM `admin_user` AS `main_table`")).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "username"}).
+			AddRow(1, "gopher@example.com", "gopher").
+			AddRow(2, "ferris@example.com", "ferris"))
+
+	type adminUser struct {
+		UserID   int64  `db:"user_id"`
+		Email    string `db:"email"`
+		Username string `db:"username"`
+	}
+	var users []*adminUser
+	n, err := tableMap.MustTable(table4).LoadSliceContext(context.Background(), dbc.DB, &users)
+	require.NoError(t, err, "%+v", err)
+	assert.Exactly(t, 2, n)
+	assert.Exactly(t, "ferris", users[1].Username)
+}
+
+func TestTable_TruncateDrop_View(t *testing.T) {
+	t.Parallel()
+
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	dbMock.ExpectExec(regexp.QuoteMeta("DROP VIEW IF EXISTS `view_admin_user`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectExec(regexp.QuoteMeta("CREATE VIEW `view_admin_user` AS SELECT * FROM admin_user")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectQuery(regexp.QuoteMeta("SELECT TABLE_NAME, COLUMN_NAME, ORDINAL_POSITION, COLUMN_DEFAULT, IS_NULLABLE, DATA_TYPE, CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE, COLUMN_TYPE, COLUMN_KEY, EXTRA, COLUMN_COMMENT FROM `information_schema`.`COLUMNS` WHERE (TABLE_SCHEMA=DATABASE()) AND (TABLE_NAME IN (?))")).
+		WithArgs("view_admin_user").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME", "ORDINAL_POSITION", "COLUMN_DEFAULT", "IS_NULLABLE", "DATA_TYPE", "CHARACTER_MAXIMUM_LENGTH", "NUMERIC_PRECISION", "NUMERIC_SCALE", "COLUMN_TYPE", "COLUMN_KEY", "EXTRA", "COLUMN_COMMENT"}).
+			FromCSVString(`"view_admin_user","user_id",1,0,"NO","int",0,10,0,"int(10) unsigned","","",""`))
+
+	tm, err := csdb.NewTables(csdb.WithViewFromQuery(dbc.DB, 0, "view_admin_user", "SELECT * FROM admin_user"))
+	require.NoError(t, err, "%+v", err)
+	view := tm.MustTable(0)
+	require.True(t, view.IsView())
+
+	err = view.Truncate(dbc.DB)
+	assert.True(t, errors.IsNotAllowed(err), "%+v", err)
+	err = view.Drop(dbc.DB)
+	assert.True(t, errors.IsNotAllowed(err), "%+v", err)
+}
+
 func TestTable_LoadDataInfile(t *testing.T) {
 	t.Parallel()
 
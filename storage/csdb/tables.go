@@ -233,6 +233,57 @@ func WithLoadColumnDefinitions(db dbr.Querier) TableOption {
 	}
 }
 
+// WithLoadForeignKeys loads the foreign keys from the database for each table
+// in the internal map. Thread safe.
+func WithLoadForeignKeys(db dbr.Querier) TableOption {
+	return TableOption{
+		priority: 255, // must be one of the last elements
+		fn: func(tm *Tables) error {
+
+			tfk, err := LoadForeignKeys(db, tm.Tables()...)
+			if err != nil {
+				return errors.Wrap(err, "[csdb] table.LoadForeignKeys")
+			}
+
+			tm.mu.Lock()
+			defer tm.mu.Unlock()
+			for _, t := range tm.ts {
+				if fks, ok := tfk[t.Name]; ok {
+					t.ForeignKeys = fks
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// WithLoadIndexes loads the indexes from the database for each table in the
+// internal map. Thread safe.
+func WithLoadIndexes(db dbr.Querier) TableOption {
+	return TableOption{
+		priority: 255, // must be one of the last elements
+		fn: func(tm *Tables) error {
+
+			tis, err := LoadIndexes(db, tm.Tables()...)
+			if err != nil {
+				return errors.Wrap(err, "[csdb] table.LoadIndexes")
+			}
+
+			tm.mu.Lock()
+			defer tm.mu.Unlock()
+			for _, t := range tm.ts {
+				if is, ok := tis[t.Name]; ok {
+					t.Indexes = is
+					t.update()
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
 // WithTableDMLListeners adds event listeners to a table object. It doesn't
 // matter if the table has already been set. If the table object gets set later,
 // the events will be copied to the new object.
@@ -400,6 +451,12 @@ func (tm *Tables) Upsert(i int, tNew *Table) error {
 	if len(tNew.Columns) == 0 {
 		tNew.Columns = tOld.Columns
 	}
+	if len(tNew.ForeignKeys) == 0 {
+		tNew.ForeignKeys = tOld.ForeignKeys
+	}
+	if len(tNew.Indexes) == 0 {
+		tNew.Indexes = tOld.Indexes
+	}
 
 	tm.ts[i] = tNew.update()
 	return nil
@@ -182,7 +182,7 @@ func TestWithLoadColumnDefinitions_Integration(t *testing.T) {
 		t.Skip("Environment DB DSN not found")
 	}
 	defer dbc.Close()
-	if mv != magento.Version2 {
+	if mv&magento.Version2 == 0 {
 		t.Skip("Requirement")
 	}
 
@@ -237,6 +237,189 @@ func TestWithLoadColumnDefinitions2(t *testing.T) {
 	//t.Log(table.Columns.GoString())
 }
 
+func TestWithLoadColumnDefinitions_CompositePK(t *testing.T) {
+	t.Parallel()
+
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	// rows are intentionally not sorted by ORDINAL_POSITION
+	rows := sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME", "ORDINAL_POSITION", "COLUMN_DEFAULT", "IS_NULLABLE", "DATA_TYPE", "CHARACTER_MAXIMUM_LENGTH", "NUMERIC_PRECISION", "NUMERIC_SCALE", "COLUMN_TYPE", "COLUMN_KEY", "EXTRA", "COLUMN_COMMENT"}).
+		FromCSVString(
+			`"catalog_product_entity_int","value",4,NULL,"YES","int",0,10,0,"int(11)","","","Value"
+"catalog_product_entity_int","store_id",2,0,"NO","smallint",0,5,0,"smallint(5) unsigned","PRI","","Store ID"
+"catalog_product_entity_int","entity_id",1,0,"NO","int",0,10,0,"int(10) unsigned","PRI","","Entity ID"
+"catalog_product_entity_int","attribute_id",3,0,"NO","smallint",0,5,0,"smallint(5) unsigned","MUL","","Attribute ID"
+`)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta("SELECT TABLE_NAME, COLUMN_NAME, ORDINAL_POSITION, COLUMN_DEFAULT, IS_NULLABLE, DATA_TYPE, CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE, COLUMN_TYPE, COLUMN_KEY, EXTRA, COLUMN_COMMENT FROM `information_schema`.`COLUMNS` WHERE (TABLE_SCHEMA=DATABASE()) AND (TABLE_NAME IN (?))")).
+		WithArgs("catalog_product_entity_int").
+		WillReturnRows(rows)
+
+	i := 4711
+	tm0 := csdb.MustNewTables(
+		csdb.WithTable(i, "catalog_product_entity_int"),
+		csdb.WithLoadColumnDefinitions(dbc.DB),
+	)
+
+	table, err := tm0.Table(i)
+	require.NoError(t, err)
+	assert.Exactly(t, 2, table.CountPK)
+	assert.Exactly(t, []string{"entity_id", "store_id"}, table.PrimaryKeys().FieldNames())
+	for _, c := range table.PrimaryKeys() {
+		assert.True(t, c.IsPK(), "Column %q", c.Field)
+	}
+	assert.Exactly(t, "SELECT `main_table`.`entity_id`, `main_table`.`store_id`, `main_table`.`value`, `main_table`.`attribute_id` FROM `catalog_product_entity_int` AS `main_table`",
+		table.Select().String())
+}
+
+func TestWithLoadForeignKeys(t *testing.T) {
+	t.Parallel()
+
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	rows := sqlmock.NewRows([]string{"TABLE_NAME", "CONSTRAINT_NAME", "COLUMN_NAME", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME", "UPDATE_RULE", "DELETE_RULE"}).
+		FromCSVString(
+			`"catalog_product_website","CAT_PRD_WS_PRD_ID_CAT_PRD_ENTT_ENTT_ID","product_id","catalog_product_entity","entity_id","NO ACTION","CASCADE"
+"catalog_product_website","CAT_PRD_WS_WS_ID_STORE_WS_WS_ID","website_id","store_website","website_id","NO ACTION","CASCADE"
+`)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta("SELECT kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.COLUMN_NAME, kcu.REFERENCED_TABLE_NAME, kcu.REFERENCED_COLUMN_NAME, rc.UPDATE_RULE, rc.DELETE_RULE FROM `information_schema`.`KEY_COLUMN_USAGE` AS `kcu` INNER JOIN `information_schema`.`REFERENTIAL_CONSTRAINTS` AS `rc` ON (kcu.CONSTRAINT_SCHEMA=rc.CONSTRAINT_SCHEMA AND kcu.TABLE_NAME=rc.TABLE_NAME AND kcu.CONSTRAINT_NAME=rc.CONSTRAINT_NAME) WHERE (kcu.TABLE_SCHEMA=DATABASE()) AND (kcu.REFERENCED_TABLE_NAME IS NOT NULL) AND (kcu.TABLE_NAME IN (?)) ORDER BY kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION")).
+		WithArgs("catalog_product_website").
+		WillReturnRows(rows)
+
+	i := 4711
+	tm0 := csdb.MustNewTables(
+		csdb.WithTable(i, "catalog_product_website"),
+		csdb.WithLoadForeignKeys(dbc.DB),
+	)
+
+	table, err := tm0.Table(i)
+	require.NoError(t, err)
+	require.Len(t, table.ForeignKeys, 2)
+	assert.Exactly(t, &csdb.ForeignKey{
+		ConstraintName:   "CAT_PRD_WS_PRD_ID_CAT_PRD_ENTT_ENTT_ID",
+		Column:           "product_id",
+		ReferencedTable:  "catalog_product_entity",
+		ReferencedColumn: "entity_id",
+		OnUpdate:         "NO ACTION",
+		OnDelete:         "CASCADE",
+	}, table.ForeignKeys[0])
+	assert.Exactly(t, "website_id", table.ForeignKeys[1].Column)
+	assert.Exactly(t, []string{"catalog_product_entity", "store_website"}, table.ReferencedTables())
+}
+
+func TestWithLoadIndexes(t *testing.T) {
+	t.Parallel()
+
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	rows := sqlmock.NewRows([]string{"TABLE_NAME", "INDEX_NAME", "COLUMN_NAME", "NON_UNIQUE", "INDEX_TYPE"}).
+		FromCSVString(
+			`"catalog_category_product","CATALOG_CATEGORY_PRODUCT_PRODUCT_ID_POSITION","product_id","1","BTREE"
+"catalog_category_product","CATALOG_CATEGORY_PRODUCT_PRODUCT_ID_POSITION","position","1","BTREE"
+"catalog_category_product","PRIMARY","entity_id","0","BTREE"
+`)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta("SELECT TABLE_NAME, INDEX_NAME, COLUMN_NAME, NON_UNIQUE, INDEX_TYPE FROM `information_schema`.`STATISTICS` WHERE (TABLE_SCHEMA=DATABASE()) AND (TABLE_NAME IN (?)) ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX")).
+		WithArgs("catalog_category_product").
+		WillReturnRows(rows)
+
+	i := 4712
+	tm0 := csdb.MustNewTables(
+		csdb.WithTable(i, "catalog_category_product",
+			&csdb.Column{Field: "entity_id", Key: "PRI"},
+			&csdb.Column{Field: "product_id", Key: "MUL"},
+			&csdb.Column{Field: "position"},
+			&csdb.Column{Field: "category_id"},
+		),
+		csdb.WithLoadIndexes(dbc.DB),
+	)
+
+	table, err := tm0.Table(i)
+	require.NoError(t, err)
+	require.Len(t, table.Indexes, 2)
+	assert.Exactly(t, &csdb.Index{
+		Name:    "CATALOG_CATEGORY_PRODUCT_PRODUCT_ID_POSITION",
+		Columns: []string{"product_id", "position"},
+		Unique:  false,
+		Type:    "BTREE",
+	}, table.Indexes[0])
+	assert.Exactly(t, &csdb.Index{
+		Name:    "PRIMARY",
+		Columns: []string{"entity_id"},
+		Unique:  true,
+		Type:    "BTREE",
+	}, table.Indexes[1])
+
+	assert.True(t, table.Columns.ByField("entity_id").IsIndexed())
+	assert.True(t, table.Columns.ByField("product_id").IsIndexed())
+	assert.True(t, table.Columns.ByField("position").IsIndexed(), "second column of an index")
+	assert.False(t, table.Columns.ByField("category_id").IsIndexed())
+}
+
+func TestTable_PrimaryKeys_IndexSequence(t *testing.T) {
+	t.Parallel()
+
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	// the PRIMARY index contains the columns in a different order than the
+	// table.
+	rows := sqlmock.NewRows([]string{"TABLE_NAME", "INDEX_NAME", "COLUMN_NAME", "NON_UNIQUE", "INDEX_TYPE"}).
+		FromCSVString(
+			`"catalog_product_website","PRIMARY","website_id","0","BTREE"
+"catalog_product_website","PRIMARY","product_id","0","BTREE"
+`)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta("SELECT TABLE_NAME, INDEX_NAME, COLUMN_NAME, NON_UNIQUE, INDEX_TYPE FROM `information_schema`.`STATISTICS` WHERE (TABLE_SCHEMA=DATABASE()) AND (TABLE_NAME IN (?)) ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX")).
+		WithArgs("catalog_product_website").
+		WillReturnRows(rows)
+
+	i := 4713
+	cols := []*csdb.Column{
+		{Field: "product_id", Pos: 1, Key: "PRI"},
+		{Field: "website_id", Pos: 2, Key: "PRI"},
+	}
+	tm0 := csdb.MustNewTables(
+		csdb.WithTable(i, "catalog_product_website", cols...),
+	)
+	table, err := tm0.Table(i)
+	require.NoError(t, err)
+	assert.Exactly(t, []string{"product_id", "website_id"}, table.PrimaryKeys().FieldNames(), "sorted by position without indexes")
+
+	require.NoError(t, tm0.Options(csdb.WithLoadIndexes(dbc.DB)))
+	table, err = tm0.Table(i)
+	require.NoError(t, err)
+	assert.Exactly(t, []string{"website_id", "product_id"}, table.PrimaryKeys().FieldNames())
+}
+
 func TestMustInitTables(t *testing.T) {
 	t.Parallel()
 
@@ -26,6 +26,12 @@ func (t alias) QuoteAs() string {
 	return Quoter.QuoteAs(t.Expression, t.Alias)
 }
 
+// writeQuoteAs writes the quoted expression and alias to w and returns a
+// NotValid error for an invalid identifier.
+func (t alias) writeQuoteAs(w QueryWriter) error {
+	return Quoter.writeQuoteAs(w, t.Expression, t.Alias)
+}
+
 // DefaultScopeNames specifies the name of the scopes used in all EAV* function
 // to generate scope based hierarchical fall backs.
 var DefaultScopeNames = [...]string{"Store", "Group", "Website", "Default"}
@@ -0,0 +1,117 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr
+
+import (
+	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/errors"
+)
+
+// ColumnExpression gets implemented by types which generate a column
+// expression with place holders, for example Case. The returned arguments
+// replace the place holders in the order of their occurrence.
+type ColumnExpression interface {
+	ToSQL() (string, []interface{}, error)
+}
+
+// Case builds a searched CASE expression:
+//
+//	CASE WHEN cond1 THEN result1 [WHEN cond2 THEN result2 ...] [ELSE result] END
+//
+// Case implements interface ColumnExpression.
+type Case struct {
+	whens      []caseWhen
+	elseResult string
+	elseArgs   []interface{}
+	hasElse    bool
+	alias      string
+}
+
+type caseWhen struct {
+	condition string
+	result    string
+	args      []interface{}
+}
+
+// NewCase creates a new empty CASE expression.
+func NewCase() *Case {
+	return &Case{}
+}
+
+// When adds a WHEN condition THEN result branch. Both condition and result
+// are raw SQL and can contain place holders. The args replace the place
+// holders first in the condition and then in the result.
+//
+//	When("price > ?", "?", 100, "expensive")
+func (c *Case) When(condition, result string, args ...interface{}) *Case {
+	c.whens = append(c.whens, caseWhen{
+		condition: condition,
+		result:    result,
+		args:      args,
+	})
+	return c
+}
+
+// Else sets the raw ELSE result. The args replace the place holders of the
+// result.
+func (c *Case) Else(result string, args ...interface{}) *Case {
+	c.elseResult = result
+	c.elseArgs = args
+	c.hasElse = true
+	return c
+}
+
+// As sets the alias of the expression when used as a column.
+func (c *Case) As(alias string) *Case {
+	c.alias = alias
+	return c
+}
+
+// ToSQL generates the CASE expression and returns the arguments in the order
+// of the WHEN branches followed by the ELSE arguments. Returns an Empty error
+// if no WHEN branch has been added.
+func (c *Case) ToSQL() (string, []interface{}, error) {
+	if len(c.whens) == 0 {
+		return "", nil, errors.NewEmptyf("[dbr] Case.ToSQL: At least one WHEN branch is required")
+	}
+
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+
+	var args []interface{}
+	buf.WriteString("CASE")
+	for _, w := range c.whens {
+		buf.WriteString(" WHEN ")
+		buf.WriteString(w.condition)
+		buf.WriteString(" THEN ")
+		buf.WriteString(w.result)
+		args = append(args, w.args...)
+	}
+	if c.hasElse {
+		buf.WriteString(" ELSE ")
+		buf.WriteString(c.elseResult)
+		args = append(args, c.elseArgs...)
+	}
+	buf.WriteString(" END")
+
+	if err := argsValuer(&args); err != nil {
+		return "", nil, errors.Wrap(err, "[dbr] Case.ToSQL.argsValuer")
+	}
+
+	if c.alias != "" {
+		return Quoter.Alias(buf.String(), c.alias), args, nil
+	}
+	return buf.String(), args, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr
+
+import (
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ ColumnExpression = (*Case)(nil)
+
+func TestCase_ToSQL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no when", func(t *testing.T) {
+		sql, args, err := NewCase().Else("1").ToSQL()
+		assert.Empty(t, sql)
+		assert.Nil(t, args)
+		assert.True(t, errors.IsEmpty(err), "%+v", err)
+	})
+
+	t.Run("without else", func(t *testing.T) {
+		sql, args, err := NewCase().When("a IS NULL", "0").ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "CASE WHEN a IS NULL THEN 0 END", sql)
+		assert.Nil(t, args)
+	})
+
+	t.Run("driver.Valuer", func(t *testing.T) {
+		sql, args, err := NewCase().When("a = ?", "?", myString{Present: true, Val: "x"}, 2).As("b").ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "CASE WHEN a = ? THEN ? END AS `b`", sql)
+		assert.Exactly(t, []interface{}{"x", 2}, args)
+	})
+}
+
+func TestSelect_ColumnExpr(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tax tiers", func(t *testing.T) {
+		taxTier := NewCase().
+			When("price < ?", "?", 100, "low").
+			When("price BETWEEN ? AND ?", "CONCAT(?, tax_class)", 100, 1000, "mid_").
+			Else("?", "high").
+			As("tax_tier")
+
+		sql, args, err := NewSelect("catalog_product_entity", "cpe").
+			Column("IF(qty > ?, 1, 0)", "in_stock", 0).
+			AddColumns("sku").
+			ColumnExpr(taxTier).
+			Where(ConditionRaw("store_id = ?", 3)).
+			ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t,
+			"SELECT IF(qty > ?, 1, 0) AS `in_stock`, sku, CASE WHEN price < ? THEN ? WHEN price BETWEEN ? AND ? THEN CONCAT(?, tax_class) ELSE ? END AS `tax_tier` FROM `catalog_product_entity` AS `cpe` WHERE (store_id = ?)",
+			sql)
+		assert.Exactly(t, []interface{}{0, 100, "low", 100, 1000, "mid_", "high", 3}, args)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		sql, args, err := NewSelect("tableA").ColumnExpr(NewCase()).AddColumns("b").ToSQL()
+		assert.Empty(t, sql)
+		assert.Nil(t, args)
+		assert.True(t, errors.IsEmpty(err), "%+v", err)
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr_test
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connMaxLifetime reads the unexported lifetime of the connection pool
+// because sql.DB provides no getter.
+func connMaxLifetime(t *testing.T, db *sql.DB) time.Duration {
+	f := reflect.ValueOf(db).Elem().FieldByName("maxLifetime")
+	if !f.IsValid() {
+		t.Skip("sql.DB has no field maxLifetime")
+	}
+	return time.Duration(f.Int())
+}
+
+func TestConnection_PoolOptions(t *testing.T) {
+
+	t.Run("negative values", func(t *testing.T) {
+		for i, opt := range []dbr.ConnectionOption{
+			dbr.WithMaxOpenConns(-1),
+			dbr.WithMaxIdleConns(-1),
+			dbr.WithConnMaxLifetime(-time.Second),
+		} {
+			dbc, err := dbr.NewConnection(opt)
+			assert.Nil(t, dbc, "Index %d", i)
+			assert.True(t, errors.IsNotValid(err), "Index %d => %+v", i, err)
+		}
+	})
+
+	t.Run("applied to DB set later", func(t *testing.T) {
+		db, sqlMock, err := sqlmock.New()
+		require.NoError(t, err)
+		dbc, err := dbr.NewConnection(
+			dbr.WithMaxOpenConns(5),
+			dbr.WithConnMaxLifetime(time.Minute),
+			dbr.WithDB(db),
+		)
+		require.NoError(t, err, "%+v", err)
+		defer func() {
+			sqlMock.ExpectClose()
+			assert.NoError(t, dbc.Close())
+		}()
+		assert.Exactly(t, 5, dbc.DB.Stats().MaxOpenConnections)
+		assert.Exactly(t, time.Minute, connMaxLifetime(t, dbc.DB))
+	})
+
+	t.Run("applied via Options", func(t *testing.T) {
+		db, sqlMock, err := sqlmock.New()
+		require.NoError(t, err)
+		dbc, err := dbr.NewConnection(dbr.WithDB(db))
+		require.NoError(t, err, "%+v", err)
+		defer func() {
+			sqlMock.ExpectClose()
+			assert.NoError(t, dbc.Close())
+		}()
+
+		// the connection created by sqlmock.New is idle
+		assert.Exactly(t, 1, dbc.DB.Stats().Idle)
+
+		require.NoError(t, dbc.Options(dbr.WithMaxOpenConns(7), dbr.WithMaxIdleConns(0)))
+		assert.Exactly(t, 7, dbc.DB.Stats().MaxOpenConnections)
+		assert.Exactly(t, 0, dbc.DB.Stats().Idle)
+	})
+}
@@ -1,7 +1,9 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/log"
@@ -23,6 +25,25 @@ type Connection struct {
 	// DatabaseName contains the database name to which this connection has been
 	// bound to. It will only be set when a DSN has been parsed.
 	DatabaseName string
+	// Dialect gets passed to all builders created by a Session. Defaults to
+	// DialectMySQL.
+	Dialect Dialect
+	// stmtCache caches the statements prepared by PrepareCached. Nil if
+	// disabled.
+	stmtCache *stmtCache
+	// retryMaxAttempts and retryBackoff configure the retry of Exec in case of
+	// a deadlock. Zero attempts disable the retry.
+	retryMaxAttempts int
+	retryBackoff     time.Duration
+	// dbSettings contains the connection pool settings which get applied to
+	// DB once it has been set.
+	dbSettings []func(*sql.DB)
+	// Listeners get merged into the listeners of all builders created by a
+	// Session or a transaction. They get dispatched before the listeners
+	// added to a builder.
+	Listeners ListenerBucket
+	// QueryLogger gets set by the option WithQueryLogger. Nil if not set.
+	QueryLogger *QueryLogger
 }
 
 // Session represents a business unit of execution for some connection
@@ -56,13 +77,98 @@ func WithDSN(dsn string) ConnectionOption {
 	}
 }
 
+// WithStmtCache enables caching of the statements prepared by
+// Connection.PrepareCached, keyed by their SQL string. The cache holds at most
+// size statements and evicts the least recently used statement once the limit
+// has been exceeded. An evicted statement gets closed once all its users have
+// closed their CachedStmt. The Prepare functions of the builders are not
+// affected. Applying the option again replaces and closes the previous cache.
+func WithStmtCache(size int) ConnectionOption {
+	return func(c *Connection) error {
+		if size < 1 {
+			return errors.NewNotValidf("[dbr] WithStmtCache: size %d must be greater than zero", size)
+		}
+		if c.stmtCache != nil {
+			if err := c.stmtCache.Close(); err != nil {
+				return errors.Wrap(err, "[dbr] WithStmtCache")
+			}
+		}
+		c.stmtCache = newStmtCache(size)
+		return nil
+	}
+}
+
+// WithDialect sets the SQL dialect for the placeholders and quoting of the
+// generated SQL. The MySQL dialect is the default.
+func WithDialect(d Dialect) ConnectionOption {
+	return func(c *Connection) error {
+		c.Dialect = d
+		return nil
+	}
+}
+
+// WithMaxOpenConns sets the maximum number of open connections to the
+// database, see sql.DB.SetMaxOpenConns. Zero means unlimited.
+func WithMaxOpenConns(n int) ConnectionOption {
+	return func(c *Connection) error {
+		if n < 0 {
+			return errors.NewNotValidf("[dbr] WithMaxOpenConns: %d cannot be negative", n)
+		}
+		c.dbSettings = append(c.dbSettings, func(db *sql.DB) { db.SetMaxOpenConns(n) })
+		return nil
+	}
+}
+
+// WithMaxIdleConns sets the maximum number of connections in the idle
+// connection pool, see sql.DB.SetMaxIdleConns. Zero means no idle
+// connections are retained.
+func WithMaxIdleConns(n int) ConnectionOption {
+	return func(c *Connection) error {
+		if n < 0 {
+			return errors.NewNotValidf("[dbr] WithMaxIdleConns: %d cannot be negative", n)
+		}
+		c.dbSettings = append(c.dbSettings, func(db *sql.DB) { db.SetMaxIdleConns(n) })
+		return nil
+	}
+}
+
+// WithConnMaxLifetime sets the maximum amount of time a connection may be
+// reused, see sql.DB.SetConnMaxLifetime. Zero means connections are reused
+// forever.
+func WithConnMaxLifetime(d time.Duration) ConnectionOption {
+	return func(c *Connection) error {
+		if d < 0 {
+			return errors.NewNotValidf("[dbr] WithConnMaxLifetime: %s cannot be negative", d)
+		}
+		c.dbSettings = append(c.dbSettings, func(db *sql.DB) { db.SetConnMaxLifetime(d) })
+		return nil
+	}
+}
+
+// WithQueryLogger logs all statements executed by the builders of a Session or
+// a transaction to l. It adds OnAfterQuery and OnAfterExec listeners to the
+// Listeners of the connection. The field QueryLogger allows to enable or
+// disable the logging at runtime. Arguments of the statements do not get
+// logged by default.
+func WithQueryLogger(l log.Logger) ConnectionOption {
+	return func(c *Connection) error {
+		if l == nil {
+			return errors.NewEmptyf("[dbr] WithQueryLogger: Logger cannot be nil")
+		}
+		c.QueryLogger = NewQueryLogger(l)
+		c.QueryLogger.addTo(&c.Listeners)
+		return nil
+	}
+}
+
 // NewConnection instantiates a Connection for a given database/sql connection
 // and event receiver. An invalid drivername causes a NotImplemented error to be
 // returned. You can either apply a DSN or a pre configured *sql.DB type.
 func NewConnection(opts ...ConnectionOption) (*Connection, error) {
 	c := &Connection{
-		dn:     DriverNameMySQL,
-		Logger: log.BlackHole{},
+		dn:      DriverNameMySQL,
+		Logger:  log.BlackHole{},
+		Dialect: DialectMySQL,
 	}
 	if err := c.Options(opts...); err != nil {
 		return nil, errors.Wrap(err, "[dbr] NewConnection.ApplyOpts")
@@ -78,14 +184,14 @@ func NewConnection(opts ...ConnectionOption) (*Connection, error) {
 		c.DatabaseName = c.dsn.DBName
 	}
 
-	if c.DB != nil || c.dsn == nil {
-		return c, nil
+	if c.DB == nil && c.dsn != nil {
+		var err error
+		if c.DB, err = sql.Open(c.dn, c.dsn.FormatDSN()); err != nil {
+			return nil, errors.Wrap(err, "[dbr] sql.Open")
+		}
 	}
 
-	var err error
-	if c.DB, err = sql.Open(c.dn, c.dsn.FormatDSN()); err != nil {
-		return nil, errors.Wrap(err, "[dbr] sql.Open")
-	}
+	c.applyDBSettings()
 
 	return c, nil
 }
@@ -110,9 +216,22 @@ func (c *Connection) Options(opts ...ConnectionOption) error {
 			return errors.Wrap(err, "[dbr] Connection ApplyOpts")
 		}
 	}
+	c.applyDBSettings()
 	return nil
 }
 
+// applyDBSettings applies the pending connection pool settings once DB has
+// been set.
+func (c *Connection) applyDBSettings() {
+	if c.DB == nil {
+		return
+	}
+	for _, fn := range c.dbSettings {
+		fn(c.DB)
+	}
+	c.dbSettings = nil
+}
+
 // NewSession instantiates a Session for the Connection
 func (c *Connection) NewSession(opts ...SessionOption) *Session {
 	s := &Session{
@@ -123,8 +242,52 @@ func (c *Connection) NewSession(opts ...SessionOption) *Session {
 	return s
 }
 
-// Close closes the database, releasing any open resources.
+// PrepareCached returns the cached statement for query or prepares a new one
+// and adds it to the cache. Without the option WithStmtCache each call
+// prepares a new statement. The returned statement must be closed, which
+// hands it back to the cache.
+func (c *Connection) PrepareCached(query string) (*CachedStmt, error) {
+	return c.PrepareCachedContext(nil, query)
+}
+
+// PrepareCachedContext same as PrepareCached but uses the context for
+// preparing a new statement. A nil context falls back to the non-context
+// aware Prepare function of the database.
+func (c *Connection) PrepareCachedContext(ctx context.Context, query string) (*CachedStmt, error) {
+	if c.stmtCache == nil {
+		stmt, err := prepareContext(ctx, c.DB, query)
+		if err != nil {
+			return nil, errors.Wrap(err, "[dbr] Connection.PrepareCached")
+		}
+		return &CachedStmt{Stmt: stmt}, nil
+	}
+	stmt, err := c.stmtCache.prepareContext(ctx, c.DB, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "[dbr] Connection.PrepareCached")
+	}
+	return &CachedStmt{Stmt: stmt, cache: c.stmtCache}, nil
+}
+
+// execer returns the database wrapped into a deadlock retry if enabled.
+func (c *Connection) execer() Execer {
+	if c.retryMaxAttempts > 0 {
+		return retryExecer{
+			db:          c.DB,
+			maxAttempts: c.retryMaxAttempts,
+			backoff:     c.retryBackoff,
+		}
+	}
+	return c.DB
+}
+
+// Close closes all cached prepared statements and the database, releasing any
+// open resources.
 func (c *Connection) Close() error {
+	if c.stmtCache != nil {
+		if err := c.stmtCache.Close(); err != nil {
+			return errors.Wrap(err, "[dbr] connection.close")
+		}
+	}
 	return errors.Wrap(c.DB.Close(), "[dbr] connection.close")
 }
 
@@ -1,8 +1,9 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
-	"strconv"
+	"time"
 
 	"github.com/corestoreio/csfw/util/bufferpool"
 	"github.com/corestoreio/errors"
@@ -16,8 +17,14 @@ type Delete struct {
 		Preparer
 		Execer
 	}
+	// Dialect writes the placeholders, quoted identifiers and LIMIT of the
+	// generated SQL. Nil defaults to MySQL.
+	Dialect Dialect
 
 	From alias
+	// JoinFragments turns the statement into a multi-table DELETE which
+	// removes only the rows of the From table.
+	JoinFragments
 	WhereFragments
 	OrderBys    []string
 	LimitCount  uint64
@@ -28,6 +35,13 @@ type Delete struct {
 	// Listeners allows to dispatch certain functions in different
 	// situations.
 	Listeners DeleteListeners
+	// Stats contains the statistics of the last execution. They get set
+	// before the OnAfterExec listeners get dispatched.
+	Stats QueryStats
+	// lastSQL and lastArgs contain the statement and its arguments of the
+	// last call to ToSQL. They get copied into Stats after the execution.
+	lastSQL  string
+	lastArgs []interface{}
 	// PropagationStopped set to true if you would like to interrupt the
 	// listener chain. Once set to true all sub sequent calls of the next
 	// listeners will be suppressed.
@@ -50,11 +64,13 @@ func NewDelete(from ...string) *Delete {
 func (sess *Session) DeleteFrom(from ...string) *Delete {
 	d := &Delete{
 		Log:            sess.Logger,
+		Dialect:        sess.cxn.Dialect,
 		From:           MakeAlias(from...),
 		WhereFragments: make(WhereFragments, 0, 2),
 	}
-	d.DB.Execer = sess.cxn.DB
+	d.DB.Execer = sess.cxn.execer()
 	d.DB.Preparer = sess.cxn.DB
+	d.Listeners.Merge(sess.cxn.Listeners.Delete)
 	return d
 }
 
@@ -63,11 +79,13 @@ func (sess *Session) DeleteFrom(from ...string) *Delete {
 func (tx *Tx) DeleteFrom(from ...string) *Delete {
 	d := &Delete{
 		Log:            tx.Logger,
+		Dialect:        tx.Dialect,
 		From:           MakeAlias(from...),
 		WhereFragments: make(WhereFragments, 0, 2),
 	}
 	d.DB.Execer = tx.Tx
 	d.DB.Preparer = tx.Tx
+	d.Listeners.Merge(tx.Listeners.Delete)
 	return d
 }
 
@@ -78,6 +96,40 @@ func (b *Delete) Where(args ...ConditionArg) *Delete {
 	return b
 }
 
+// WhereOr groups the conditions with OR and appends the group as one WHERE
+// clause, joined with AND to the other clauses. See ConditionOr.
+func (b *Delete) WhereOr(args ...ConditionArg) *Delete {
+	return b.Where(ConditionOr(args...))
+}
+
+func (b *Delete) join(j string, t []string, on ...ConditionArg) *Delete {
+	b.JoinFragments = append(b.JoinFragments, &joinFragment{
+		JoinType:     j,
+		Table:        MakeAlias(t...),
+		OnConditions: newWhereFragments(on...),
+	})
+	return b
+}
+
+// Join creates a join construct with the onConditions glued together with
+// AND. Only the rows of the From table get deleted. MySQL does not support
+// ORDER BY and LIMIT in a multi-table DELETE.
+func (b *Delete) Join(table []string, onConditions ...ConditionArg) *Delete {
+	return b.join("INNER", table, onConditions...)
+}
+
+// LeftJoin creates a join construct with the onConditions glued together
+// with AND. Only the rows of the From table get deleted.
+func (b *Delete) LeftJoin(table []string, onConditions ...ConditionArg) *Delete {
+	return b.join("LEFT", table, onConditions...)
+}
+
+// RightJoin creates a join construct with the onConditions glued together
+// with AND. Only the rows of the From table get deleted.
+func (b *Delete) RightJoin(table []string, onConditions ...ConditionArg) *Delete {
+	return b.join("RIGHT", table, onConditions...)
+}
+
 // OrderBy appends an ORDER BY clause to the statement
 func (b *Delete) OrderBy(ord string) *Delete {
 	b.OrderBys = append(b.OrderBys, ord)
@@ -101,7 +153,9 @@ func (b *Delete) Limit(limit uint64) *Delete {
 	return b
 }
 
-// Offset sets an OFFSET clause for the statement; overrides any existing OFFSET
+// Offset sets an OFFSET clause for the statement; overrides any existing
+// OFFSET. MySQL does not support an OFFSET in a DELETE statement, hence ToSQL
+// returns a NotValid error.
 func (b *Delete) Offset(offset uint64) *Delete {
 	b.OffsetCount = offset
 	b.OffsetValid = true
@@ -111,6 +165,16 @@ func (b *Delete) Offset(offset uint64) *Delete {
 // ToSQL serialized the Delete to a SQL string
 // It returns the string with placeholders and a slice of query arguments
 func (b *Delete) ToSQL() (string, []interface{}, error) {
+	sql, args, err := b.toSQL()
+	if err != nil {
+		b.lastSQL, b.lastArgs = "", nil
+		return "", nil, err
+	}
+	b.lastSQL, b.lastArgs = sql, args
+	return sql, args, nil
+}
+
+func (b *Delete) toSQL() (string, []interface{}, error) {
 
 	if err := b.Listeners.dispatch(OnBeforeToSQL, b); err != nil {
 		return "", nil, errors.Wrap(err, "[dbr] Delete.Listeners.dispatch")
@@ -120,51 +184,79 @@ func (b *Delete) ToSQL() (string, []interface{}, error) {
 		return "", nil, errors.NewEmptyf(errTableMissing)
 	}
 
-	var buf = bufferpool.Get()
-	defer bufferpool.Put(buf)
+	var bb = bufferpool.Get()
+	defer bufferpool.Put(bb)
+	buf := newDialectWriter(bb, b.Dialect)
 	var args []interface{}
 
-	buf.WriteString("DELETE FROM ")
-	buf.WriteString(b.From.QuoteAs())
-
-	// Write WHERE clause if we have any fragments
-	if len(b.WhereFragments) > 0 {
-		buf.WriteString(" WHERE ")
-		writeWhereFragmentsToSQL(b.WhereFragments, buf, &args)
+	if b.OffsetValid {
+		return "", nil, errors.NewNotValidf("[dbr] Delete.ToSQL: OFFSET is not supported")
+	}
+	if len(b.JoinFragments) > 0 && (len(b.OrderBys) > 0 || b.LimitValid) {
+		return "", nil, errors.NewNotValidf("[dbr] Delete.ToSQL: ORDER BY and LIMIT are not supported with JOIN")
 	}
 
-	// Ordering and limiting
-	if len(b.OrderBys) > 0 {
-		buf.WriteString(" ORDER BY ")
-		for i, s := range b.OrderBys {
-			if i > 0 {
-				buf.WriteString(", ")
-			}
-			buf.WriteString(s)
+	buf.WriteString("DELETE ")
+	if len(b.JoinFragments) > 0 {
+		// the table with the alias is the target of the multi-table delete
+		target := b.From.Alias
+		if target == "" {
+			target = b.From.Expression
 		}
+		if err := Quoter.writeQuoteAs(buf, target); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] Delete.ToSQL")
+		}
+		buf.WriteRune(' ')
+	}
+	buf.WriteString("FROM ")
+	if err := b.From.writeQuoteAs(buf); err != nil {
+		return "", nil, errors.Wrap(err, "[dbr] Delete.ToSQL")
 	}
 
-	if b.LimitValid {
-		buf.WriteString(" LIMIT ")
-		buf.WriteString(strconv.FormatUint(b.LimitCount, 10))
+	if err := writeJoinFragmentsToSQL(b.JoinFragments, buf, &args); err != nil {
+		return "", nil, errors.Wrap(err, "[dbr] Delete.ToSQL.Join")
 	}
 
-	if b.OffsetValid {
-		buf.WriteString(" OFFSET ")
-		buf.WriteString(strconv.FormatUint(b.OffsetCount, 10))
+	// Write WHERE clause if we have any fragments
+	if len(b.WhereFragments) > 0 {
+		buf.WriteString(" WHERE ")
+		if err := writeWhereFragmentsToSQL(b.WhereFragments, buf, &args); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] Delete.ToSQL.Where")
+		}
 	}
+
+	// Ordering and limiting
+	writeOrderLimitToSQL(buf, b.OrderBys, b.LimitValid, b.LimitCount, false, 0)
 	return buf.String(), args, nil
 }
 
 // Exec executes the statement represented by the Delete
 // It returns the raw database/sql Result and an error if there was one
 func (b *Delete) Exec() (sql.Result, error) {
+	return b.ExecContext(nil)
+}
+
+// ExecContext same as Exec but respects the context. All listeners gets called
+// before the context aware function of the database gets invoked. A nil
+// context falls back to the non-context aware Exec function of the database.
+// The OnAfterExec listeners get called after the execution.
+func (b *Delete) ExecContext(ctx context.Context) (sql.Result, error) {
+	start := time.Now()
+	res, err := b.exec(ctx)
+	b.Stats = makeExecStats(start, b.lastSQL, b.lastArgs, res, err)
+	if lErr := b.Listeners.dispatch(OnAfterExec, b); lErr != nil && err == nil {
+		err = errors.Wrap(lErr, "[dbr] Delete.Exec.Listeners.dispatch")
+	}
+	return res, err
+}
+
+func (b *Delete) exec(ctx context.Context) (sql.Result, error) {
 	sqlStr, args, err := b.ToSQL()
 	if err != nil {
 		return nil, errors.Wrap(err, "[dbr] Delete.Exec.ToSQL")
 	}
 
-	fullSQL, err := Preprocess(sqlStr, args)
+	fullSQL, args, err := preprocessDialect(b.Dialect, sqlStr, args)
 	if err != nil {
 		return nil, errors.Wrapf(err, "[dbr] Delete.Exec.Preprocess: %q", fullSQL)
 	}
@@ -173,7 +265,7 @@ func (b *Delete) Exec() (sql.Result, error) {
 		defer log.WhenDone(b.Log).Info("dbr.Delete.Exec.Timing", log.String("sql", fullSQL))
 	}
 
-	result, err := b.DB.Exec(fullSQL)
+	result, err := execContext(ctx, b.DB.Execer, fullSQL, args...)
 	if err != nil {
 		return result, errors.Wrap(err, "[dbr] delete.exec.Exec")
 	}
@@ -185,6 +277,12 @@ func (b *Delete) Exec() (sql.Result, error) {
 // database/sql Statement and an error if there was one. Provided arguments in
 // the Delete are getting ignored. It panics when field Preparer is nil.
 func (b *Delete) Prepare() (*sql.Stmt, error) {
+	return b.PrepareContext(nil)
+}
+
+// PrepareContext same as Prepare but bound to the context. A nil context falls
+// back to the non-context aware Prepare function of the database.
+func (b *Delete) PrepareContext(ctx context.Context) (*sql.Stmt, error) {
 	sqlStr, _, err := b.ToSQL() // TODO create a ToSQL version without any arguments
 	if err != nil {
 		return nil, errors.Wrap(err, "[dbr] Delete.Prepare.ToSQL")
@@ -194,6 +292,6 @@ func (b *Delete) Prepare() (*sql.Stmt, error) {
 		defer log.WhenDone(b.Log).Info("dbr.Delete.Prepare.Timing", log.String("sql", sqlStr))
 	}
 
-	stmt, err := b.DB.Prepare(sqlStr)
+	stmt, err := prepareContext(ctx, b.DB.Preparer, sqlStr)
 	return stmt, errors.Wrap(err, "[dbr] Delete.Prepare.Prepare")
 }
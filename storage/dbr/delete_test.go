@@ -55,8 +55,20 @@ func TestDeleteTenStaringFromTwentyToSQL(t *testing.T) {
 	s := createFakeSession()
 
 	sql, _, err := s.DeleteFrom("a").Limit(10).Offset(20).OrderBy("id").ToSQL()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+	assert.Empty(t, sql)
+
+	sql, _, err = s.DeleteFrom("a").Limit(10).OrderBy("id").ToSQL()
 	assert.NoError(t, err)
-	assert.Equal(t, sql, "DELETE FROM `a` ORDER BY id LIMIT 10 OFFSET 20")
+	assert.Equal(t, sql, "DELETE FROM `a` ORDER BY id LIMIT 10")
+}
+
+func TestDelete_OffsetWithoutLimit(t *testing.T) {
+	s := createFakeSession()
+
+	sql, _, err := s.DeleteFrom("a").Offset(20).OrderBy("id").ToSQL()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+	assert.Empty(t, sql)
 }
 
 func TestDeleteReal(t *testing.T) {
@@ -216,3 +228,71 @@ func TestDelete_Events(t *testing.T) {
 	})
 
 }
+
+func TestDelete_ConditionNamed(t *testing.T) {
+	s := createFakeSession()
+
+	sql, args, err := s.DeleteFrom("tableA").Where(ConditionNamed("a = :id OR b = :id", map[string]interface{}{"id": 5})).ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "DELETE FROM `tableA` WHERE (a = ? OR b = ?)", sql)
+	assert.Exactly(t, []interface{}{5, 5}, args)
+
+	sql, args, err = s.DeleteFrom("tableA").Where(ConditionNamed("a = :idx", nil)).ToSQL()
+	assert.Empty(t, sql)
+	assert.Nil(t, args)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+func TestDelete_WhereOr(t *testing.T) {
+	s := createFakeSession()
+
+	sql, args, err := s.DeleteFrom("tableA").Where(Eq{"a": 1}).WhereOr(ConditionRaw("b = ?", 2), ConditionIsNull("c")).ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "DELETE FROM `tableA` WHERE (`a` = ?) AND ((b = ?) OR (c IS NULL))", sql)
+	assert.Exactly(t, []interface{}{1, 2}, args)
+
+	sql, _, err = s.DeleteFrom("tableA").WhereOr().ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "DELETE FROM `tableA`", sql)
+}
+
+func TestDelete_Join(t *testing.T) {
+	s := createFakeSession()
+
+	t.Run("orphaned child rows", func(t *testing.T) {
+		sql, args, err := s.DeleteFrom("catalog_product_option", "cpo").
+			LeftJoin(
+				JoinTable("catalog_product_entity", "cpe"),
+				ConditionRaw("cpe.entity_id = cpo.product_id AND cpe.type_id = ?", "simple"),
+			).
+			Where(ConditionIsNull("cpe.entity_id"), ConditionRaw("cpo.is_require = ?", 1)).
+			ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t,
+			"DELETE `cpo` FROM `catalog_product_option` AS `cpo` LEFT JOIN `catalog_product_entity` AS `cpe` ON (cpe.entity_id = cpo.product_id AND cpe.type_id = ?) WHERE (cpe.entity_id IS NULL) AND (cpo.is_require = ?)",
+			sql)
+		assert.Exactly(t, []interface{}{"simple", 1}, args)
+	})
+
+	t.Run("without alias", func(t *testing.T) {
+		sql, args, err := s.DeleteFrom("child").
+			Join(JoinTable("parent"), ConditionRaw("parent.id = child.parent_id")).
+			Where(ConditionRaw("parent.status = ?", 0)).
+			ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t,
+			"DELETE `child` FROM `child` INNER JOIN `parent` ON (parent.id = child.parent_id) WHERE (parent.status = ?)",
+			sql)
+		assert.Exactly(t, []interface{}{0}, args)
+	})
+
+	t.Run("limit not supported", func(t *testing.T) {
+		sql, args, err := s.DeleteFrom("child").
+			Join(JoinTable("parent"), ConditionRaw("parent.id = child.parent_id")).
+			Limit(10).
+			ToSQL()
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		assert.Empty(t, sql)
+		assert.Nil(t, args)
+	})
+}
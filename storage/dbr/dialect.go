@@ -1,6 +1,11 @@
 package dbr
 
-import "time"
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
 
 var dialect dialecter = mysqlDialect{}
 
@@ -13,3 +18,158 @@ type dialecter interface {
 	EscapeTime(w QueryWriter, t time.Time)
 	ApplyLimitAndOffset(w QueryWriter, limit, offset uint64)
 }
+
+// Dialect describes the placeholders, the quoting of identifiers and the
+// LIMIT/OFFSET clause of a SQL database. The builders ask the Dialect while
+// writing the SQL.
+type Dialect interface {
+	// Placeholder returns the placeholder for the n-th argument. n starts at
+	// one.
+	Placeholder(n int) string
+	// QuoteIdentifier quotes a table or column name.
+	QuoteIdentifier(ident string) string
+	// WriteLimitOffset writes the LIMIT and OFFSET clauses including the
+	// leading white space. A flag reports if a value has been set.
+	WriteLimitOffset(w QueryWriter, limitValid bool, limit uint64, offsetValid bool, offset uint64)
+}
+
+// DialectMySQL uses question marks as placeholders and back ticks for quoting
+// identifiers. It is the default dialect.
+var DialectMySQL Dialect = mysqlDialect{}
+
+// DialectPostgreSQL uses numbered placeholders like $1, $2 and double quotes
+// for quoting identifiers.
+var DialectPostgreSQL Dialect = postgresDialect{}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+func (postgresDialect) QuoteIdentifier(ident string) string {
+	return `"` + strings.Replace(ident, `"`, `""`, -1) + `"`
+}
+
+func (postgresDialect) WriteLimitOffset(w QueryWriter, limitValid bool, limit uint64, offsetValid bool, offset uint64) {
+	if limitValid {
+		_, _ = w.WriteString(" LIMIT ")
+		_, _ = w.WriteString(strconv.FormatUint(limit, 10))
+	}
+	if offsetValid {
+		_, _ = w.WriteString(" OFFSET ")
+		_, _ = w.WriteString(strconv.FormatUint(offset, 10))
+	}
+}
+
+// isDialectMySQL reports true if the dialect d writes MySQL, which is the
+// case for a nil dialect.
+func isDialectMySQL(d Dialect) bool {
+	switch d.(type) {
+	case nil, mysqlDialect:
+		return true
+	}
+	return false
+}
+
+// dialectWriter writes the SQL of a builder in the syntax of a Dialect. The
+// placeholders get numbered in the order they get written, starting at one.
+type dialectWriter struct {
+	*bytes.Buffer
+	d Dialect
+	n int // number of written placeholders
+}
+
+func newDialectWriter(buf *bytes.Buffer, d Dialect) *dialectWriter {
+	if d == nil {
+		d = DialectMySQL
+	}
+	return &dialectWriter{Buffer: buf, d: d}
+}
+
+// dialectOf returns the dialect of w. All other writers than the dialectWriter
+// write MySQL.
+func dialectOf(w QueryWriter) Dialect {
+	if dw, ok := w.(*dialectWriter); ok {
+		return dw.d
+	}
+	return DialectMySQL
+}
+
+// writePlaceholder writes the placeholder of the next argument.
+func writePlaceholder(w QueryWriter) {
+	dw, ok := w.(*dialectWriter)
+	if !ok {
+		_, _ = w.WriteRune('?')
+		return
+	}
+	dw.n++
+	_, _ = dw.WriteString(dw.d.Placeholder(dw.n))
+}
+
+// writeRawSQL writes a SQL fragment like a condition or a column expression.
+// For MySQL the fragment gets written unchanged. For all other dialects each
+// question mark gets written as the placeholder of the next argument and each
+// back tick quoted identifier gets quoted by the dialect. String literals in
+// single or double quotes remain untouched.
+func writeRawSQL(w QueryWriter, sql string) {
+	if isDialectMySQL(dialectOf(w)) {
+		_, _ = w.WriteString(sql)
+		return
+	}
+
+	start := 0 // start of the not yet written part of sql
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; c {
+		case '?':
+			_, _ = w.WriteString(sql[start:i])
+			writePlaceholder(w)
+			start = i + 1
+		case quoteByte:
+			// doubled back ticks are an escaped back tick within the identifier
+			_, _ = w.WriteString(sql[start:i])
+			var ident []byte
+			j := i + 1
+			for ; j < len(sql); j++ {
+				if sql[j] == quoteByte {
+					if j+1 < len(sql) && sql[j+1] == quoteByte {
+						ident = append(ident, quoteByte)
+						j++
+						continue
+					}
+					break
+				}
+				ident = append(ident, sql[j])
+			}
+			_, _ = w.WriteString(dialectOf(w).QuoteIdentifier(string(ident)))
+			i = j
+			start = j + 1
+		case '\'', '"':
+			j := i + 1
+			for ; j < len(sql); j++ {
+				if sql[j] == '\\' {
+					j++
+					continue
+				}
+				if sql[j] == c {
+					break
+				}
+			}
+			i = j
+		}
+	}
+	if start < len(sql) {
+		_, _ = w.WriteString(sql[start:])
+	}
+}
+
+// preprocessDialect interpolates for MySQL the arguments into the SQL string
+// and returns nil arguments. All other dialects return the unchanged SQL and
+// arguments to be bound by the database driver.
+func preprocessDialect(d Dialect, sql string, args []interface{}) (string, []interface{}, error) {
+	if !isDialectMySQL(d) {
+		return sql, args, nil
+	}
+	fullSQL, err := Preprocess(sql, args)
+	return fullSQL, nil, err
+}
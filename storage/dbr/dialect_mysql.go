@@ -12,6 +12,31 @@ const DriverNameMySQL = "mysql"
 
 type mysqlDialect struct{}
 
+func (mysqlDialect) Placeholder(int) string {
+	return "?"
+}
+
+func (mysqlDialect) QuoteIdentifier(ident string) string {
+	return quote + strings.Replace(ident, quote, quote+quote, -1) + quote
+}
+
+// WriteLimitOffset writes an OFFSET without a LIMIT as LIMIT
+// 18446744073709551615 OFFSET n because MySQL does not support an OFFSET
+// without a LIMIT.
+func (mysqlDialect) WriteLimitOffset(w QueryWriter, limitValid bool, limit uint64, offsetValid bool, offset uint64) {
+	switch {
+	case limitValid:
+		_, _ = w.WriteString(" LIMIT ")
+		_, _ = w.WriteString(strconv.FormatUint(limit, 10))
+	case offsetValid:
+		_, _ = w.WriteString(" LIMIT " + limitMax)
+	}
+	if offsetValid {
+		_, _ = w.WriteString(" OFFSET ")
+		_, _ = w.WriteString(strconv.FormatUint(offset, 10))
+	}
+}
+
 func (mysqlDialect) EscapeIdent(w QueryWriter, ident string) {
 	w.WriteRune('`')
 	r := strings.NewReplacer("`", "``", ".", "`.`")
@@ -62,7 +87,7 @@ func (mysqlDialect) ApplyLimitAndOffset(w QueryWriter, limit, offset uint64) {
 	w.WriteString(" LIMIT ")
 	if limit == 0 {
 		// In MYSQL, OFFSET cannot be used alone. Set the limit to the max possible value.
-		w.WriteString("18446744073709551615")
+		w.WriteString(limitMax)
 	} else {
 		w.WriteString(strconv.FormatUint(limit, 10))
 	}
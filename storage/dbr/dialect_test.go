@@ -0,0 +1,171 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialect_Placeholder(t *testing.T) {
+	assert.Exactly(t, "?", dbr.DialectMySQL.Placeholder(3))
+	assert.Exactly(t, "$3", dbr.DialectPostgreSQL.Placeholder(3))
+	assert.Exactly(t, "`a``b`", dbr.DialectMySQL.QuoteIdentifier("a`b"))
+	assert.Exactly(t, `"a""b"`, dbr.DialectPostgreSQL.QuoteIdentifier(`a"b`))
+}
+
+func TestDialect_ToSQL(t *testing.T) {
+
+	t.Run("Select", func(t *testing.T) {
+		sel := dbr.NewSelect("tableA", "tA").AddColumns("a", "b").
+			Where(dbr.ConditionRaw("c = ? AND d = 'is it ?'", 1), dbr.Eq{"e": 2}).
+			Having(dbr.ConditionRaw("f > ?", 3))
+		sel.Dialect = dbr.DialectPostgreSQL
+		sql, args, err := sel.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, `SELECT a, b FROM "tableA" AS "tA" WHERE (c = $1 AND d = 'is it ?') AND ("e" = $2) HAVING (f > $3)`, sql)
+		assert.Exactly(t, []interface{}{1, 2, 3}, args)
+	})
+
+	t.Run("Select IN slice", func(t *testing.T) {
+		sel := dbr.NewSelect("tableA").AddColumns("a").Where(dbr.Eq{"a": []int{1, 2}}, dbr.Eq{"b": 3})
+		sel.Dialect = dbr.DialectPostgreSQL
+		sql, args, err := sel.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, `SELECT a FROM "tableA" WHERE ("a" IN ($1,$2)) AND ("b" = $3)`, sql)
+		assert.Exactly(t, []interface{}{1, 2, 3}, args)
+	})
+
+	t.Run("Select offset only", func(t *testing.T) {
+		sel := dbr.NewSelect("tableA").AddColumns("a").Offset(20)
+		sel.Dialect = dbr.DialectPostgreSQL
+		sql, _, err := sel.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, `SELECT a FROM "tableA" OFFSET 20`, sql)
+	})
+
+	t.Run("Select limit offset", func(t *testing.T) {
+		sel := dbr.NewSelect("tableA").AddColumns("a").Limit(10).Offset(20)
+		sel.Dialect = dbr.DialectPostgreSQL
+		sql, _, err := sel.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, `SELECT a FROM "tableA" LIMIT 10 OFFSET 20`, sql)
+	})
+
+	t.Run("Select MySQL default", func(t *testing.T) {
+		sql, _, err := dbr.NewSelect("tableA").AddColumns("a").Where(dbr.Eq{"e": 2}).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT a FROM `tableA` WHERE (`e` = ?)", sql)
+	})
+
+	t.Run("Select raw SQL", func(t *testing.T) {
+		sel := &dbr.Select{RawFullSQL: "SELECT a FROM b WHERE c = ?", RawArguments: []interface{}{1}}
+		sel.Dialect = dbr.DialectPostgreSQL
+		sql, args, err := sel.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT a FROM b WHERE c = ?", sql)
+		assert.Exactly(t, []interface{}{1}, args)
+	})
+
+	t.Run("Insert", func(t *testing.T) {
+		ins := dbr.NewInsert("tableA").Columns("a", "b").Values(1, 2).Values(3, 4)
+		ins.Dialect = dbr.DialectPostgreSQL
+		sql, args, err := ins.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, `INSERT INTO "tableA" ("a","b") VALUES ($1,$2),($3,$4)`, sql)
+		assert.Exactly(t, []interface{}{1, 2, 3, 4}, args)
+	})
+
+	t.Run("Insert qualified table", func(t *testing.T) {
+		ins := dbr.NewInsert("schemaA.tableA").Columns("a").Values(1)
+		ins.Dialect = dbr.DialectPostgreSQL
+		sql, _, err := ins.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, `INSERT INTO "schemaA"."tableA" ("a") VALUES ($1)`, sql)
+	})
+
+	t.Run("Insert Map", func(t *testing.T) {
+		ins := dbr.NewInsert("tableA").Map(map[string]interface{}{"a": 1})
+		ins.Dialect = dbr.DialectPostgreSQL
+		sql, args, err := ins.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, `INSERT INTO "tableA" ("a") VALUES ($1)`, sql)
+		assert.Exactly(t, []interface{}{1}, args)
+	})
+
+	t.Run("Insert FromSelect", func(t *testing.T) {
+		ins := dbr.NewInsert("tableA").Columns("a").FromSelect(
+			dbr.NewSelect("tableB").AddColumns("b").Where(dbr.Eq{"c": 1}))
+		ins.Dialect = dbr.DialectPostgreSQL
+		sql, args, err := ins.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, `INSERT INTO "tableA" ("a") SELECT b FROM "tableB" WHERE ("c" = $1)`, sql)
+		assert.Exactly(t, []interface{}{1}, args)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		up := dbr.NewUpdate("tableA").Set("a", 1).SetExpr("b", "b + ?", 2).Where(dbr.ConditionRaw("c = ?", 3))
+		up.Dialect = dbr.DialectPostgreSQL
+		sql, args, err := up.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, `UPDATE "tableA" SET "a" = $1, "b" = b + $2 WHERE (c = $3)`, sql)
+		assert.Exactly(t, []interface{}{1, 2, 3}, args)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		del := dbr.NewDelete("tableA").Where(dbr.ConditionRaw("c = ? OR d = \"?\"", 3))
+		del.Dialect = dbr.DialectPostgreSQL
+		sql, args, err := del.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, `DELETE FROM "tableA" WHERE (c = $1 OR d = "?")`, sql)
+		assert.Exactly(t, []interface{}{3}, args)
+	})
+}
+
+func TestDialect_Exec(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+	require.NoError(t, dbc.Options(dbr.WithDialect(dbr.DialectPostgreSQL)))
+	sess := dbc.NewSession()
+
+	dbMock.ExpectExec(`UPDATE "tableA" SET "a" = \$1 WHERE \("b" = \$2\)`).
+		WithArgs(1, "x").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := sess.Update("tableA").Set("a", 1).Where(dbr.Eq{"b": "x"}).Exec()
+	require.NoError(t, err, "%+v", err)
+	ra, err := res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(1), ra)
+
+	dbMock.ExpectQuery(`SELECT a FROM "tableA" WHERE \(b = \$1\)`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(3))
+
+	var a int64
+	require.NoError(t, sess.Select("a").From("tableA").Where(dbr.ConditionRaw("b = ?", 2)).LoadValue(&a))
+	assert.Exactly(t, int64(3), a)
+}
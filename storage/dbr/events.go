@@ -16,7 +16,9 @@ package dbr
 
 import (
 	"bytes"
+	"database/sql"
 	"sync"
+	"time"
 
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/log"
@@ -31,9 +33,70 @@ func (et EventType) String() string {
 
 // List of possible dispatched events.
 const (
+	// OnBeforeToSQL gets dispatched before the SQL string of a builder gets
+	// rendered. The listeners may still modify the builder, for example the
+	// table name in the fields Select.FromTable, Insert.Into, Update.Table
+	// and Delete.From. This is the hook for sharding or read/write splitting,
+	// see RewriteTable.
 	OnBeforeToSQL EventType = iota + 65
+	// OnAfterQuery gets dispatched after a Select has been executed and its
+	// rows have been loaded. The field Select.Stats contains the statistics.
+	OnAfterQuery
+	// OnAfterExec gets dispatched after an Insert, Update or Delete has been
+	// executed. The field Stats of the builder contains the statistics.
+	OnAfterExec
 )
 
+// QueryStats contains the statistics of an executed statement. The stats get
+// set before the listeners of the events OnAfterQuery and OnAfterExec get
+// dispatched.
+type QueryStats struct {
+	// Duration the time it took to execute the statement including the
+	// loading of the rows.
+	Duration time.Duration
+	// Rows the number of loaded rows of a Select. -1 if the rows get handed
+	// over to the caller, for example in Select.Rows.
+	Rows int
+	// RowsAffected the number of affected rows of an Insert, Update or
+	// Delete. -1 if not supported by the driver.
+	RowsAffected int64
+	// LastInsertID the ID of the last inserted row of an Insert. -1 if not
+	// supported by the driver.
+	LastInsertID int64
+	// Err contains the error of the execution, if any.
+	Err error
+	// SQL contains the executed statement with its place holders. Empty if
+	// the statement could not be built.
+	SQL string
+	// Args contains the arguments of the executed statement. Be careful when
+	// logging them because they might contain sensitive data.
+	Args []interface{}
+}
+
+// makeExecStats creates the statistics for an executed Insert, Update or
+// Delete statement.
+func makeExecStats(start time.Time, sqlStr string, args []interface{}, res sql.Result, err error) QueryStats {
+	qs := QueryStats{
+		Duration:     time.Since(start),
+		Rows:         -1,
+		RowsAffected: -1,
+		LastInsertID: -1,
+		Err:          err,
+		SQL:          sqlStr,
+		Args:         args,
+	}
+	if res == nil {
+		return qs
+	}
+	if ra, raErr := res.RowsAffected(); raErr == nil {
+		qs.RowsAffected = ra
+	}
+	if id, idErr := res.LastInsertId(); idErr == nil {
+		qs.LastInsertID = id
+	}
+	return qs
+}
+
 // ListenerBucket a type for embedding into other structs to define events for
 // manipulating the SQL. Not an interface because interfaces are named with
 // verbs ;-) Not yet thread safe.
@@ -113,6 +176,24 @@ type Listen struct {
 	DeleteFunc
 }
 
+// RewriteTable creates an OnBeforeToSQL listener for all four builders which
+// replaces the main table name with the result of fn. It acts as the hook for
+// sharded tables, for example to rewrite catalog_product to
+// catalog_product_shard_3. The alias of the table stays untouched. Joined
+// tables and sub selects must be rewritten by their own listeners. fn gets
+// called each time the SQL gets rendered and must return already rewritten
+// or unknown table names unchanged.
+func RewriteTable(fn func(table string) string) Listen {
+	return Listen{
+		Name:       "dbr.RewriteTable",
+		EventType:  OnBeforeToSQL,
+		SelectFunc: func(b *Select) { b.FromTable.Expression = fn(b.FromTable.Expression) },
+		InsertFunc: func(b *Insert) { b.Into = fn(b.Into) },
+		UpdateFunc: func(b *Update) { b.Table.Expression = fn(b.Table.Expression) },
+		DeleteFunc: func(b *Delete) { b.From.Expression = fn(b.From.Expression) },
+	}
+}
+
 // <-------------------------COPY------------------------->
 
 // SelectFunc receives the Select object pointer for modification.
@@ -0,0 +1,166 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr_test
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListeners_OnAfterQuery(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	var after, once, before int
+	var stats []dbr.QueryStats
+	sel := dbc.NewSession().Select("a").From("tableA")
+	sel.Listeners.Add(
+		dbr.Listen{
+			EventType: dbr.OnAfterQuery,
+			SelectFunc: func(b *dbr.Select) {
+				after++
+				stats = append(stats, b.Stats)
+			},
+		},
+		dbr.Listen{
+			Once:       true,
+			EventType:  dbr.OnAfterQuery,
+			SelectFunc: func(b *dbr.Select) { once++ },
+		},
+		dbr.Listen{
+			EventType:  dbr.OnBeforeToSQL,
+			SelectFunc: func(b *dbr.Select) { before++ },
+		},
+	)
+
+	dbMock.ExpectQuery("SELECT a FROM `tableA`").
+		WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1).AddRow(2))
+	dbMock.ExpectQuery("SELECT a FROM `tableA`").
+		WillReturnError(errors.New("Ups"))
+
+	var vals []int64
+	n, err := sel.LoadValues(&vals)
+	require.NoError(t, err, "%+v", err)
+	assert.Exactly(t, 2, n)
+
+	n, err = sel.LoadInt64s(&vals)
+	assert.EqualError(t, errors.Cause(err), "Ups")
+	assert.Exactly(t, 0, n)
+
+	assert.Exactly(t, 2, after, "OnAfterQuery invocations")
+	assert.Exactly(t, 1, once, "OnAfterQuery Once invocations")
+	assert.Exactly(t, 2, before, "OnBeforeToSQL invocations")
+
+	require.Len(t, stats, 2)
+	assert.Exactly(t, 2, stats[0].Rows)
+	assert.Exactly(t, int64(-1), stats[0].RowsAffected)
+	assert.NoError(t, stats[0].Err)
+	assert.True(t, stats[0].Duration > 0, "Duration should be greater than zero")
+	assert.Exactly(t, 0, stats[1].Rows)
+	assert.EqualError(t, errors.Cause(stats[1].Err), "Ups")
+}
+
+func TestListeners_OnAfterExec(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+	sess := dbc.NewSession()
+
+	t.Run("Insert", func(t *testing.T) {
+		var calls int
+		ins := sess.InsertInto("tableA").Columns("a").Values(1)
+		ins.Listeners.Add(dbr.Listen{
+			EventType: dbr.OnAfterExec,
+			InsertFunc: func(b *dbr.Insert) {
+				calls++
+				assert.Exactly(t, int64(5), b.Stats.LastInsertID)
+				assert.Exactly(t, int64(1), b.Stats.RowsAffected)
+			},
+		})
+		dbMock.ExpectExec("INSERT INTO tableA").WillReturnResult(sqlmock.NewResult(5, 1))
+		_, err := ins.Exec()
+		require.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 1, calls)
+	})
+
+	t.Run("Update with Once", func(t *testing.T) {
+		var calls, once int
+		up := sess.Update("tableA").Set("a", 1)
+		up.Listeners.Add(
+			dbr.Listen{
+				EventType: dbr.OnAfterExec,
+				UpdateFunc: func(b *dbr.Update) {
+					calls++
+					assert.Exactly(t, int64(3), b.Stats.RowsAffected)
+				},
+			},
+			dbr.Listen{
+				Once:       true,
+				EventType:  dbr.OnAfterExec,
+				UpdateFunc: func(b *dbr.Update) { once++ },
+			},
+		)
+		dbMock.ExpectExec("UPDATE `tableA` SET `a` = 1").WillReturnResult(sqlmock.NewResult(0, 3))
+		dbMock.ExpectExec("UPDATE `tableA` SET `a` = 1").WillReturnResult(sqlmock.NewResult(0, 3))
+		for i := 0; i < 2; i++ {
+			_, err := up.Exec()
+			require.NoError(t, err, "%+v", err)
+		}
+		assert.Exactly(t, 2, calls)
+		assert.Exactly(t, 1, once)
+	})
+
+	t.Run("Delete PropagationStopped", func(t *testing.T) {
+		var first, second int
+		del := sess.DeleteFrom("tableA").Where(dbr.Eq{"a": 1})
+		del.Listeners.Add(
+			dbr.Listen{
+				EventType: dbr.OnAfterExec,
+				DeleteFunc: func(b *dbr.Delete) {
+					first++
+					b.PropagationStopped = true
+				},
+			},
+			dbr.Listen{
+				EventType:  dbr.OnAfterExec,
+				DeleteFunc: func(b *dbr.Delete) { second++ },
+			},
+		)
+		dbMock.ExpectExec("DELETE FROM `tableA` WHERE \\(`a` = 1\\)").WillReturnError(errors.New("Ups"))
+		_, err := del.Exec()
+		assert.EqualError(t, errors.Cause(err), "Ups")
+		assert.EqualError(t, errors.Cause(del.Stats.Err), "Ups")
+		assert.Exactly(t, int64(-1), del.Stats.RowsAffected)
+		assert.Exactly(t, 1, first)
+		assert.Exactly(t, 0, second)
+	})
+}
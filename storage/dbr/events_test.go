@@ -268,3 +268,54 @@ func TestNewListenerBucket(t *testing.T) {
 	})
 
 }
+
+func TestRewriteTable(t *testing.T) {
+	shard := RewriteTable(func(table string) string {
+		if table == "catalog_product" {
+			return "catalog_product_shard_3"
+		}
+		return table
+	})
+
+	t.Run("Select", func(t *testing.T) {
+		sel := NewSelect("catalog_product", "e").AddColumns("e.sku").Where(ConditionRaw("e.entity_id = ?", 1))
+		sel.Listeners.Add(shard)
+		sqlStr, args, err := sel.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT e.sku FROM `catalog_product_shard_3` AS `e` WHERE (e.entity_id = ?)", sqlStr)
+		assert.Exactly(t, []interface{}{1}, args)
+
+		// rendering a second time must not rewrite the already rewritten name
+		sqlStr, _, err = sel.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT e.sku FROM `catalog_product_shard_3` AS `e` WHERE (e.entity_id = ?)", sqlStr)
+	})
+	t.Run("Insert", func(t *testing.T) {
+		ins := NewInsert("catalog_product").Columns("sku").Values("SKU-1")
+		ins.Listeners.Add(shard)
+		sqlStr, _, err := ins.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "INSERT INTO catalog_product_shard_3 (`sku`) VALUES (?)", sqlStr)
+	})
+	t.Run("Update", func(t *testing.T) {
+		up := NewUpdate("catalog_product").Set("sku", "SKU-1")
+		up.Listeners.Add(shard)
+		sqlStr, _, err := up.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "UPDATE `catalog_product_shard_3` SET `sku` = ?", sqlStr)
+	})
+	t.Run("Delete", func(t *testing.T) {
+		del := NewDelete("catalog_product").Where(ConditionRaw("entity_id = ?", 2))
+		del.Listeners.Add(shard)
+		sqlStr, _, err := del.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "DELETE FROM `catalog_product_shard_3` WHERE (entity_id = ?)", sqlStr)
+	})
+	t.Run("other tables untouched", func(t *testing.T) {
+		sel := NewSelect("catalog_category").AddColumns("a")
+		sel.Listeners.Add(shard)
+		sqlStr, _, err := sel.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT a FROM `catalog_category`", sqlStr)
+	})
+}
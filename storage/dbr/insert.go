@@ -1,9 +1,11 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"reflect"
+	"time"
 
 	"github.com/corestoreio/csfw/util/bufferpool"
 	"github.com/corestoreio/errors"
@@ -17,16 +19,36 @@ type Insert struct {
 		Preparer
 		Execer
 	}
+	// Dialect writes the placeholders and quoted identifiers of the
+	// generated SQL. Nil defaults to MySQL.
+	Dialect Dialect
 
 	Into string
 	Cols []string
 	Vals [][]interface{}
 	Recs []interface{}
 	Maps map[string]interface{}
+	// Select used to create an "INSERT INTO `table` SELECT ..." statement. If
+	// set, the fields Vals, Recs and Maps gets ignored.
+	Select *Select
+	// IsReplace writes a MySQL REPLACE INTO statement instead of INSERT INTO.
+	// An existing row with the same primary or unique key gets deleted before
+	// the new row gets inserted.
+	IsReplace bool
+	// OnDuplicateKeys contains the columns which get updated with their new
+	// value in the ON DUPLICATE KEY UPDATE clause. MySQL only.
+	OnDuplicateKeys []string
 
 	// Listeners allows to dispatch certain functions in different
 	// situations.
 	Listeners InsertListeners
+	// Stats contains the statistics of the last execution. They get set
+	// before the OnAfterExec listeners get dispatched.
+	Stats QueryStats
+	// lastSQL and lastArgs contain the statement and its arguments of the
+	// last call to ToSQL. They get copied into Stats after the execution.
+	lastSQL  string
+	lastArgs []interface{}
 	// PropagationStopped set to true if you would like to interrupt the
 	// listener chain. Once set to true all sub sequent calls of the next
 	// listeners will be suppressed.
@@ -50,25 +72,64 @@ func NewInsert(into string) *Insert {
 // InsertInto instantiates a Insert for the given table
 func (sess *Session) InsertInto(into string) *Insert {
 	i := &Insert{
-		Log:  sess.Logger,
-		Into: into,
+		Log:     sess.Logger,
+		Dialect: sess.cxn.Dialect,
+		Into:    into,
 	}
-	i.DB.Execer = sess.cxn.DB
+	i.DB.Execer = sess.cxn.execer()
 	i.DB.Preparer = sess.cxn.DB
+	i.Listeners.Merge(sess.cxn.Listeners.Insert)
 	return i
 }
 
 // InsertInto instantiates a Insert for the given table bound to a transaction
 func (tx *Tx) InsertInto(into string) *Insert {
 	i := &Insert{
-		Log:  tx.Logger,
-		Into: into,
+		Log:     tx.Logger,
+		Dialect: tx.Dialect,
+		Into:    into,
 	}
 	i.DB.Execer = tx.Tx
 	i.DB.Preparer = tx.Tx
+	i.Listeners.Merge(tx.Listeners.Insert)
 	return i
 }
 
+// Upsert creates an INSERT statement for the given table which updates the
+// updateCols of an existing row in case of a duplicate key. The values contain
+// one or more rows, each with one value per column, hence the length of values
+// must be a multiple of the length of cols. An empty updateCols updates all
+// columns. A length mismatch returns a NotValid error when calling ToSQL or
+// Exec.
+//
+//	sess.Upsert("core_config_data", []string{"path", "value"},
+//		[]interface{}{"a/b/c", "1"}, []string{"value"}).Exec()
+func (sess *Session) Upsert(table string, cols []string, values []interface{}, updateCols []string) *Insert {
+	return sess.InsertInto(table).upsert(cols, values, updateCols)
+}
+
+// Upsert creates an INSERT statement bound to a transaction which updates the
+// updateCols of an existing row in case of a duplicate key. See
+// Session.Upsert.
+func (tx *Tx) Upsert(table string, cols []string, values []interface{}, updateCols []string) *Insert {
+	return tx.InsertInto(table).upsert(cols, values, updateCols)
+}
+
+func (b *Insert) upsert(cols []string, values []interface{}, updateCols []string) *Insert {
+	if len(cols) == 0 || len(values) == 0 || len(values)%len(cols) != 0 {
+		b.previousError = errors.NewNotValidf("[dbr] Insert.Upsert: %d values are not a multiple of %d columns", len(values), len(cols))
+		return b
+	}
+	if len(updateCols) == 0 {
+		updateCols = cols
+	}
+	b.Columns(cols...)
+	for i := 0; i < len(values); i += len(cols) {
+		b = b.Values(values[i : i+len(cols)]...)
+	}
+	return b.OnDuplicateKeyUpdate(updateCols...)
+}
+
 // Columns appends columns to insert in the statement.
 func (b *Insert) Columns(columns ...string) *Insert {
 	b.Cols = append(b.Cols, columns...)
@@ -133,66 +194,128 @@ func (b *Insert) Pair(column string, value interface{}) *Insert {
 	return b
 }
 
+// FromSelect creates an "INSERT INTO `table` SELECT ..." statement from a
+// previously created SELECT statement. The arguments of the SELECT statement
+// gets appended to the arguments of the INSERT statement. If Columns have been
+// set, their amount must match the amount of the SELECT columns, if the latter
+// can be determined.
+func (b *Insert) FromSelect(s *Select) *Insert {
+	b.Select = s
+	return b
+}
+
+// Replace writes a REPLACE INTO statement instead of INSERT INTO. All other
+// parts of the statement stay the same. MySQL only.
+func (b *Insert) Replace() *Insert {
+	b.IsReplace = true
+	return b
+}
+
+// OnDuplicateKeyUpdate appends columns to the ON DUPLICATE KEY UPDATE clause.
+// In case of a duplicate primary or unique key value each column gets updated
+// with the value it would have been inserted with:
+//
+//	INSERT INTO `a` (`b`,`c`) VALUES (?,?) ON DUPLICATE KEY UPDATE `c`=VALUES(`c`)
+//
+// Cannot be combined with Replace. MySQL only.
+func (b *Insert) OnDuplicateKeyUpdate(columns ...string) *Insert {
+	b.OnDuplicateKeys = append(b.OnDuplicateKeys, columns...)
+	return b
+}
+
 // ToSQL serialized the Insert to a SQL string
 // It returns the string with placeholders and a slice of query arguments
 func (b *Insert) ToSQL() (string, []interface{}, error) {
+	sql, args, err := b.toSQL()
+	if err != nil {
+		b.lastSQL, b.lastArgs = "", nil
+		return "", nil, err
+	}
+	b.lastSQL, b.lastArgs = sql, args
+	return sql, args, nil
+}
+
+func (b *Insert) toSQL() (string, []interface{}, error) {
+	var bb = bufferpool.Get()
+	defer bufferpool.Put(bb)
+	buf := newDialectWriter(bb, b.Dialect)
+
+	args, err := b.insertToSQL(buf)
+	if err != nil || len(b.OnDuplicateKeys) == 0 {
+		return buf.String(), args, err
+	}
+	if b.IsReplace {
+		return "", nil, errors.NewNotValidf("[dbr] Insert.ToSQL: REPLACE INTO does not support ON DUPLICATE KEY UPDATE")
+	}
+
+	buf.WriteString(" ON DUPLICATE KEY UPDATE ")
+	for i, c := range b.OnDuplicateKeys {
+		if i > 0 {
+			buf.WriteRune(',')
+		}
+		if err := Quoter.writeQuotedColumn(c, buf); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] Insert.ToSQL.OnDuplicateKeyUpdate")
+		}
+		buf.WriteString("=VALUES(")
+		_ = Quoter.writeQuotedColumn(c, buf)
+		buf.WriteRune(')')
+	}
+	return buf.String(), args, nil
+}
+
+func (b *Insert) insertToSQL(buf *dialectWriter) ([]interface{}, error) {
 	if b.previousError != nil {
-		return "", nil, errors.Wrap(b.previousError, "[dbr] Insert.ToSQL")
+		return nil, errors.Wrap(b.previousError, "[dbr] Insert.ToSQL")
 	}
 
 	if err := b.Listeners.dispatch(OnBeforeToSQL, b); err != nil {
-		return "", nil, errors.Wrap(err, "[dbr] Insert.Listeners.dispatch")
+		return nil, errors.Wrap(err, "[dbr] Insert.Listeners.dispatch")
 	}
 
 	if len(b.Into) == 0 {
-		return "", nil, errors.NewEmptyf(errTableMissing)
+		return nil, errors.NewEmptyf(errTableMissing)
+	}
+	if b.Select != nil {
+		return b.fromSelectToSQL(buf)
 	}
 	if len(b.Cols) == 0 && len(b.Maps) == 0 {
-		return "", nil, errors.NewEmptyf(errColumnsMissing)
+		return nil, errors.NewEmptyf(errColumnsMissing)
 	} else if len(b.Maps) == 0 {
 		if len(b.Vals) == 0 && len(b.Recs) == 0 {
-			return "", nil, errors.NewEmptyf(errRecordsMissing)
+			return nil, errors.NewEmptyf(errRecordsMissing)
 		}
 		if len(b.Cols) == 0 && (len(b.Vals) > 0 || len(b.Recs) > 0) {
-			return "", nil, errors.NewEmptyf(errColumnsMissing)
+			return nil, errors.NewEmptyf(errColumnsMissing)
 		}
 	}
 
-	var buf = bufferpool.Get()
-	defer bufferpool.Put(buf)
-
-	buf.WriteString("INSERT INTO ")
-	buf.WriteString(b.Into)
+	if err := b.writeStatement(buf); err != nil {
+		return nil, errors.Wrap(err, "[dbr] Insert.ToSQL")
+	}
 	buf.WriteString(" (")
 
 	if len(b.Maps) != 0 {
-		return b.MapToSQL(buf)
+		_, args, err := b.MapToSQL(buf)
+		return args, err
 	}
 
 	var args []interface{}
-	var placeholder = bufferpool.Get() // Build the placeholder like "(?,?,?)"
-	defer bufferpool.Put(placeholder)
-
-	// Simultaneously write the cols to the sql buffer, and build a placeholder
-	placeholder.WriteRune('(')
 	for i, c := range b.Cols {
 		if i > 0 {
 			buf.WriteRune(',')
-			placeholder.WriteRune(',')
 		}
-		Quoter.writeQuotedColumn(c, buf)
-		placeholder.WriteRune('?')
+		if err := Quoter.writeQuotedColumn(c, buf); err != nil {
+			return nil, errors.Wrap(err, "[dbr] Insert.ToSQL")
+		}
 	}
 	buf.WriteString(") VALUES ")
-	placeholder.WriteRune(')')
-	placeholderStr := placeholder.String()
 
 	// Go thru each value we want to insert. Write the placeholders, and collect args
 	for i, row := range b.Vals {
 		if i > 0 {
 			buf.WriteRune(',')
 		}
-		buf.WriteString(placeholderStr)
+		b.writePlaceholders(buf)
 		args = append(args, row...)
 	}
 	anyVals := len(b.Vals) > 0
@@ -202,17 +325,73 @@ func (b *Insert) ToSQL() (string, []interface{}, error) {
 		if i > 0 || anyVals {
 			buf.WriteRune(',')
 		}
-		buf.WriteString(placeholderStr)
+		b.writePlaceholders(buf)
 
 		ind := reflect.Indirect(reflect.ValueOf(rec))
 		vals, err := valuesFor(ind.Type(), ind, b.Cols)
 		if err != nil {
-			return "", nil, errors.Wrap(err, "[dbr] valuesFor")
+			return nil, errors.Wrap(err, "[dbr] valuesFor")
 		}
 		args = append(args, vals...)
 	}
 
-	return buf.String(), args, nil
+	return args, nil
+}
+
+// writePlaceholders writes the placeholders of one row like (?,?,?).
+func (b *Insert) writePlaceholders(w QueryWriter) {
+	w.WriteRune('(')
+	for i := range b.Cols {
+		if i > 0 {
+			w.WriteRune(',')
+		}
+		writePlaceholder(w)
+	}
+	w.WriteRune(')')
+}
+
+// writeStatement writes INSERT INTO or REPLACE INTO and the table name. For
+// MySQL the table name gets written as is, all other dialects quote it.
+func (b *Insert) writeStatement(w QueryWriter) error {
+	if b.IsReplace {
+		_, _ = w.WriteString("REPLACE INTO ")
+	} else {
+		_, _ = w.WriteString("INSERT INTO ")
+	}
+	if isDialectMySQL(dialectOf(w)) {
+		_, _ = w.WriteString(b.Into)
+		return nil
+	}
+	return Quoter.writeQuoted(b.Into, true, w)
+}
+
+// fromSelectToSQL writes the "INSERT INTO `table` (`cols`) SELECT ..."
+// statement. The Select gets only validated when it does not contain a raw SQL
+// string and its columns do not contain a wildcard. The Select gets written in
+// the dialect of the Insert.
+func (b *Insert) fromSelectToSQL(buf *dialectWriter) ([]interface{}, error) {
+	if selCols, ok := b.Select.countColumns(); ok && len(b.Cols) > 0 && selCols != len(b.Cols) {
+		return nil, errors.NewNotValidf("[dbr] Insert.FromSelect: Column count mismatch. Insert has %d columns but Select %d", len(b.Cols), selCols)
+	}
+
+	if err := b.writeStatement(buf); err != nil {
+		return nil, errors.Wrap(err, "[dbr] Insert.FromSelect.ToSQL")
+	}
+	buf.WriteRune(' ')
+	if len(b.Cols) > 0 {
+		buf.WriteRune('(')
+		for i, c := range b.Cols {
+			if i > 0 {
+				buf.WriteRune(',')
+			}
+			if err := Quoter.writeQuotedColumn(c, buf); err != nil {
+				return nil, errors.Wrap(err, "[dbr] Insert.FromSelect.ToSQL")
+			}
+		}
+		buf.WriteString(") ")
+	}
+	sArgs, err := b.Select.writeSQL(buf)
+	return sArgs, errors.Wrap(err, "[dbr] Insert.FromSelect.ToSQL")
 }
 
 // MapToSQL serialized the Insert to a SQL string
@@ -240,21 +419,23 @@ func (b *Insert) MapToSQL(w QueryWriter) (string, []interface{}, error) {
 		i++
 	}
 	var args []interface{}
-	var placeholder = bufferpool.Get() // Build the placeholder like "(?,?,?)"
-	defer bufferpool.Put(placeholder)
 
-	placeholder.WriteRune('(')
 	for i, c := range keys {
 		if i > 0 {
 			w.WriteRune(',')
-			placeholder.WriteRune(',')
 		}
-		Quoter.writeQuotedColumn(c, w)
-		placeholder.WriteRune('?')
+		if err := Quoter.writeQuotedColumn(c, w); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] Insert.MapToSQL")
+		}
+	}
+	w.WriteString(") VALUES (")
+	for i := range keys {
+		if i > 0 {
+			w.WriteRune(',')
+		}
+		writePlaceholder(w)
 	}
-	w.WriteString(") VALUES ")
-	placeholder.WriteRune(')')
-	w.WriteString(placeholder.String())
+	w.WriteRune(')')
 
 	args = append(args, vals...)
 
@@ -268,12 +449,30 @@ func (b *Insert) MapToSQL(w QueryWriter) (string, []interface{}, error) {
 // the first inserted row only. The reason for this is to make it possible to
 // reproduce easily the same INSERT statement against some other server.
 func (b *Insert) Exec() (sql.Result, error) {
+	return b.ExecContext(nil)
+}
+
+// ExecContext same as Exec but respects the context. All listeners gets called
+// before the context aware function of the database gets invoked. A nil
+// context falls back to the non-context aware Exec function of the database.
+// The OnAfterExec listeners get called after the execution.
+func (b *Insert) ExecContext(ctx context.Context) (sql.Result, error) {
+	start := time.Now()
+	res, err := b.exec(ctx)
+	b.Stats = makeExecStats(start, b.lastSQL, b.lastArgs, res, err)
+	if lErr := b.Listeners.dispatch(OnAfterExec, b); lErr != nil && err == nil {
+		err = errors.Wrap(lErr, "[dbr] Insert.Exec.Listeners.dispatch")
+	}
+	return res, err
+}
+
+func (b *Insert) exec(ctx context.Context) (sql.Result, error) {
 	sql, args, err := b.ToSQL()
 	if err != nil {
 		return nil, errors.Wrap(err, "[dbr] Insert.Exec.ToSQL")
 	}
 
-	fullSQL, err := Preprocess(sql, args)
+	fullSQL, args, err := preprocessDialect(b.Dialect, sql, args)
 	if err != nil {
 		return nil, errors.Wrap(err, "[dbr] Insert.Exec.Preprocess")
 	}
@@ -282,7 +481,7 @@ func (b *Insert) Exec() (sql.Result, error) {
 		defer log.WhenDone(b.Log).Info("dbr.Insert.Exec.Timing", log.String("sql", fullSQL))
 	}
 
-	result, err := b.DB.Exec(fullSQL)
+	result, err := execContext(ctx, b.DB.Execer, fullSQL, args...)
 	if err != nil {
 		return result, errors.Wrap(err, "[dbr] Insert.Exec.Exec")
 	}
@@ -292,6 +491,12 @@ func (b *Insert) Exec() (sql.Result, error) {
 
 // Prepare creates a prepared statement
 func (b *Insert) Prepare() (*sql.Stmt, error) {
+	return b.PrepareContext(nil)
+}
+
+// PrepareContext creates a prepared statement bound to the context. A nil
+// context falls back to the non-context aware Prepare function of the database.
+func (b *Insert) PrepareContext(ctx context.Context) (*sql.Stmt, error) {
 	rawSQL, _, err := b.ToSQL() // TODO create a ToSQL version without any arguments
 	if err != nil {
 		return nil, errors.Wrap(err, "[dbr] Insert.Exec.ToSQL")
@@ -301,6 +506,6 @@ func (b *Insert) Prepare() (*sql.Stmt, error) {
 		defer log.WhenDone(b.Log).Info("dbr.Insert.Prepare.Timing", log.String("sql", rawSQL))
 	}
 
-	stmt, err := b.DB.Prepare(rawSQL)
+	stmt, err := prepareContext(ctx, b.DB.Preparer, rawSQL)
 	return stmt, errors.Wrap(err, "[dbr] Insert.Prepare.Prepare")
 }
@@ -56,6 +56,108 @@ func TestInsertMultipleToSQL(t *testing.T) {
 	assert.Equal(t, args, []interface{}{1, 2, 3, 4})
 }
 
+func TestInsertReplaceToSQL(t *testing.T) {
+	s := createFakeSession()
+
+	t.Run("Single Row", func(t *testing.T) {
+		sql, args, err := s.InsertInto("a").Replace().Columns("b", "c").Values(1, 2).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "REPLACE INTO a (`b`,`c`) VALUES (?,?)", sql)
+		assert.Exactly(t, []interface{}{1, 2}, args)
+	})
+
+	t.Run("Multiple Rows", func(t *testing.T) {
+		sql, args, err := s.InsertInto("a").Replace().Columns("b", "c").Values(1, 2).Values(3, 4).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "REPLACE INTO a (`b`,`c`) VALUES (?,?),(?,?)", sql)
+		assert.Exactly(t, []interface{}{1, 2, 3, 4}, args)
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		sql, args, err := s.InsertInto("a").Replace().Map(map[string]interface{}{"b": 1}).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "REPLACE INTO a (`b`) VALUES (?)", sql)
+		assert.Exactly(t, []interface{}{1}, args)
+	})
+
+	t.Run("FromSelect", func(t *testing.T) {
+		sel := NewSelect("tableB").AddColumns("c1")
+		sql, _, err := s.InsertInto("a").Replace().Columns("b").FromSelect(sel).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "REPLACE INTO a (`b`) SELECT c1 FROM `tableB`", sql)
+	})
+
+	t.Run("Listeners dispatched", func(t *testing.T) {
+		ins := NewInsert("a").Replace().Columns("b").Values(1)
+		ins.Listeners.Add(Listen{
+			EventType: OnBeforeToSQL,
+			InsertFunc: func(i *Insert) {
+				i.Pair("c", 2)
+			},
+		})
+		sql, args, err := ins.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "REPLACE INTO a (`b`,`c`) VALUES (?,?)", sql)
+		assert.Exactly(t, []interface{}{1, 2}, args)
+	})
+}
+
+func TestInsert_OnDuplicateKeyUpdate(t *testing.T) {
+	s := createFakeSession()
+
+	t.Run("Multiple Rows", func(t *testing.T) {
+		sql, args, err := s.InsertInto("a").Columns("b", "c", "d").Values(1, 2, 3).Values(4, 5, 6).
+			OnDuplicateKeyUpdate("c", "d").ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "INSERT INTO a (`b`,`c`,`d`) VALUES (?,?,?),(?,?,?) ON DUPLICATE KEY UPDATE `c`=VALUES(`c`),`d`=VALUES(`d`)", sql)
+		assert.Exactly(t, []interface{}{1, 2, 3, 4, 5, 6}, args)
+	})
+
+	t.Run("FromSelect", func(t *testing.T) {
+		sel := NewSelect("tableB").AddColumns("c1")
+		sql, _, err := s.InsertInto("a").Columns("b").FromSelect(sel).OnDuplicateKeyUpdate("b").ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "INSERT INTO a (`b`) SELECT c1 FROM `tableB` ON DUPLICATE KEY UPDATE `b`=VALUES(`b`)", sql)
+	})
+
+	t.Run("Replace not supported", func(t *testing.T) {
+		sql, args, err := s.InsertInto("a").Replace().Columns("b").Values(1).OnDuplicateKeyUpdate("b").ToSQL()
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		assert.Empty(t, sql)
+		assert.Nil(t, args)
+	})
+}
+
+func TestSession_Upsert(t *testing.T) {
+	s := createFakeSession()
+
+	t.Run("Single Row", func(t *testing.T) {
+		sql, args, err := s.Upsert("core_config_data", []string{"path", "value"}, []interface{}{"a/b/c", "1"}, []string{"value"}).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "INSERT INTO core_config_data (`path`,`value`) VALUES (?,?) ON DUPLICATE KEY UPDATE `value`=VALUES(`value`)", sql)
+		assert.Exactly(t, []interface{}{"a/b/c", "1"}, args)
+	})
+
+	t.Run("Multiple Rows all columns", func(t *testing.T) {
+		sql, args, err := s.Upsert("a", []string{"b", "c"}, []interface{}{1, 2, 3, 4}, nil).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "INSERT INTO a (`b`,`c`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE `b`=VALUES(`b`),`c`=VALUES(`c`)", sql)
+		assert.Exactly(t, []interface{}{1, 2, 3, 4}, args)
+	})
+
+	t.Run("Columns Values mismatch", func(t *testing.T) {
+		sql, args, err := s.Upsert("a", []string{"b", "c"}, []interface{}{1, 2, 3}, []string{"c"}).ToSQL()
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		assert.Empty(t, sql)
+		assert.Nil(t, args)
+	})
+
+	t.Run("No Columns", func(t *testing.T) {
+		_, _, err := s.Upsert("a", nil, []interface{}{1}, nil).ToSQL()
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+}
+
 func TestInsertRecordsToSQL(t *testing.T) {
 	s := createFakeSession()
 
@@ -262,3 +364,60 @@ func TestInsert_Events(t *testing.T) {
 	})
 
 }
+
+func TestInsert_FromSelect(t *testing.T) {
+	t.Run("With Columns", func(t *testing.T) {
+		sel := NewSelect("tableB", "b").AddColumns("b.c1", "b.c2").Where(ConditionRaw("b.c3 = ?", 5))
+		sql, args, err := NewInsert("tableA").Columns("a", "b").FromSelect(sel).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "INSERT INTO tableA (`a`,`b`) SELECT b.c1, b.c2 FROM `tableB` AS `b` WHERE (b.c3 = ?)", sql)
+		assert.Exactly(t, []interface{}{5}, args)
+	})
+
+	t.Run("Without Columns", func(t *testing.T) {
+		sel := NewSelect("tableB").AddColumns("*").Where(Eq{"c3": 5})
+		sql, args, err := NewInsert("tableA").FromSelect(sel).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "INSERT INTO tableA SELECT * FROM `tableB` WHERE (`c3` = ?)", sql)
+		assert.Exactly(t, []interface{}{5}, args)
+	})
+
+	t.Run("Column count mismatch", func(t *testing.T) {
+		sel := NewSelect("tableB").AddColumns("c1", "c2", "c3")
+		sql, args, err := NewInsert("tableA").Columns("a", "b").FromSelect(sel).ToSQL()
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		assert.Nil(t, args)
+		assert.Empty(t, sql)
+	})
+
+	t.Run("Column count with joins", func(t *testing.T) {
+		sel := NewSelect("tableB", "b").AddColumns("b.c1").
+			Join(JoinTable("tableC", "c"), JoinColumns("c.c2"), ConditionRaw("b.id = c.id"))
+		sql, _, err := NewInsert("tableA").Columns("a", "b").FromSelect(sel).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "INSERT INTO tableA (`a`,`b`) SELECT b.c1, c.c2 FROM `tableB` AS `b` INNER JOIN `tableC` AS `c` ON (b.id = c.id)", sql)
+	})
+
+	t.Run("Listeners dispatched", func(t *testing.T) {
+		sel := NewSelect("tableB").AddColumns("c1")
+		sel.Listeners.Add(Listen{
+			EventType: OnBeforeToSQL,
+			SelectFunc: func(s *Select) {
+				s.Where(ConditionRaw("c2 = ?", 7))
+			},
+		})
+		ins := NewInsert("tableA").Columns("a").FromSelect(sel)
+		ins.Listeners.Add(Listen{
+			EventType: OnBeforeToSQL,
+			Once:      true,
+			InsertFunc: func(i *Insert) {
+				i.Columns("b")
+				i.Select.AddColumns("c3")
+			},
+		})
+		sql, args, err := ins.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "INSERT INTO tableA (`a`,`b`) SELECT c1, c3 FROM `tableB` WHERE (c2 = ?)", sql)
+		assert.Exactly(t, []interface{}{7}, args)
+	})
+}
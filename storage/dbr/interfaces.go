@@ -17,6 +17,8 @@ package dbr
 import (
 	"context"
 	"database/sql"
+
+	"github.com/corestoreio/errors"
 )
 
 // DBer is a composition of multiple interfaces to describe the common needed
@@ -54,6 +56,78 @@ type QueryRower interface {
 	QueryRow(query string, args ...interface{}) *sql.Row
 }
 
+// PreparerContext creates a new prepared statement bound to a context.
+type PreparerContext interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// QuerierContext can execute a SELECT query which can return many rows and
+// respects the context.
+type QuerierContext interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// ExecerContext can execute all other queries except SELECT and respects the
+// context.
+type ExecerContext interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// QueryRowerContext executes a SELECT query which returns one row and respects
+// the context.
+type QueryRowerContext interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// execContext calls ExecContext of db if ctx is not nil. A nil context falls
+// back to the non-context aware Exec function.
+func execContext(ctx context.Context, db Execer, query string, args ...interface{}) (sql.Result, error) {
+	if ctx == nil {
+		return db.Exec(query, args...)
+	}
+	dbc, ok := db.(ExecerContext)
+	if !ok {
+		return nil, errors.NewNotSupportedf("[dbr] %T does not implement interface ExecerContext", db)
+	}
+	return dbc.ExecContext(ctx, query, args...)
+}
+
+// queryContext calls QueryContext of db if ctx is not nil. A nil context falls
+// back to the non-context aware Query function.
+func queryContext(ctx context.Context, db Querier, query string, args ...interface{}) (*sql.Rows, error) {
+	if ctx == nil {
+		return db.Query(query, args...)
+	}
+	dbc, ok := db.(QuerierContext)
+	if !ok {
+		return nil, errors.NewNotSupportedf("[dbr] %T does not implement interface QuerierContext", db)
+	}
+	return dbc.QueryContext(ctx, query, args...)
+}
+
+// queryRowContext calls QueryRowContext of db if ctx is not nil. A nil context
+// or a db not implementing QueryRowerContext fall back to the non-context aware
+// QueryRow function because *sql.Row cannot transport an error.
+func queryRowContext(ctx context.Context, db QueryRower, query string, args ...interface{}) *sql.Row {
+	if dbc, ok := db.(QueryRowerContext); ok && ctx != nil {
+		return dbc.QueryRowContext(ctx, query, args...)
+	}
+	return db.QueryRow(query, args...)
+}
+
+// prepareContext calls PrepareContext of db if ctx is not nil. A nil context
+// falls back to the non-context aware Prepare function.
+func prepareContext(ctx context.Context, db Preparer, query string) (*sql.Stmt, error) {
+	if ctx == nil {
+		return db.Prepare(query)
+	}
+	dbc, ok := db.(PreparerContext)
+	if !ok {
+		return nil, errors.NewNotSupportedf("[dbr] %T does not implement interface PreparerContext", db)
+	}
+	return dbc.PrepareContext(ctx, query)
+}
+
 type wrapDBContext struct {
 	context.Context
 	db interface {
@@ -19,6 +19,7 @@ import (
 	"database/sql"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/util/cstesting"
 	"github.com/corestoreio/errors"
@@ -139,3 +140,94 @@ func TestWrapStmtContext(t *testing.T) {
 	err = row.Scan()
 	assert.EqualError(t, err, "Upssss QueryRow", "%+v", err)
 }
+
+func TestContextAwareBuilders(t *testing.T) {
+
+	dbc, sqlMock := cstesting.MockDB(t)
+	defer func() {
+		sqlMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := sqlMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	sess := dbc.NewSession()
+
+	t.Run("Insert ExecContext", func(t *testing.T) {
+		sqlMock.ExpectExec("INSERT INTO tableA \\(`a`\\) VALUES \\(1\\)").WillReturnResult(sqlmock.NewResult(1, 1))
+		res, err := sess.InsertInto("tableA").Columns("a").Values(1).ExecContext(context.Background())
+		require.NoError(t, err, "%+v", err)
+		lid, err := res.LastInsertId()
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(1), lid)
+	})
+
+	t.Run("Update ExecContext canceled", func(t *testing.T) {
+		var listenerCalled bool
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		up := sess.Update("tableA").Set("a", 2)
+		up.Listeners.Add(dbr.Listen{
+			EventType: dbr.OnBeforeToSQL,
+			UpdateFunc: func(*dbr.Update) {
+				listenerCalled = true
+			},
+		})
+		res, err := up.ExecContext(ctx)
+		assert.Nil(t, res)
+		assert.Exactly(t, context.Canceled, errors.Cause(err), "%+v", err)
+		assert.True(t, listenerCalled, "Listener should be called before the context")
+	})
+
+	t.Run("Delete ExecContext nil falls back", func(t *testing.T) {
+		sqlMock.ExpectExec("DELETE FROM `tableA` WHERE \\(`a` = 3\\)").WillReturnResult(sqlmock.NewResult(0, 1))
+		res, err := sess.DeleteFrom("tableA").Where(dbr.Eq{"a": 3}).ExecContext(nil)
+		require.NoError(t, err, "%+v", err)
+		ra, err := res.RowsAffected()
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(1), ra)
+	})
+
+	t.Run("Select LoadValuesContext", func(t *testing.T) {
+		sqlMock.ExpectQuery("SELECT a FROM `tableA`").WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(4).AddRow(5))
+		var vals []int64
+		n, err := sess.Select("a").From("tableA").LoadValuesContext(context.Background(), &vals)
+		require.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 2, n)
+		assert.Exactly(t, []int64{4, 5}, vals)
+	})
+
+	t.Run("Select LoadStructsContext canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var dest []*struct{ A int64 }
+		n, err := sess.Select("a").From("tableA").LoadStructsContext(ctx, &dest)
+		assert.Exactly(t, 0, n)
+		assert.Exactly(t, context.Canceled, errors.Cause(err), "%+v", err)
+	})
+
+	t.Run("Select PrepareContext", func(t *testing.T) {
+		sqlMock.ExpectPrepare("SELECT a FROM `tableA`").WillReturnError(errors.NewAlreadyClosedf("Who closed myself?"))
+		stmt, err := sess.Select("a").From("tableA").PrepareContext(context.Background())
+		assert.Nil(t, stmt)
+		assert.True(t, errors.IsAlreadyClosed(err), "%+v", err)
+	})
+
+	t.Run("Select RowContext falls back without QueryRowerContext", func(t *testing.T) {
+		sqlMock.ExpectQuery("SELECT a FROM `tableA`").WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(6))
+		sel := dbr.NewSelect("tableA").AddColumns("a")
+		sel.DB.QueryRower = struct{ dbr.QueryRower }{dbc.DB}
+		var a int64
+		require.NoError(t, sel.RowContext(context.Background()).Scan(&a))
+		assert.Exactly(t, int64(6), a)
+	})
+
+	t.Run("Context not supported", func(t *testing.T) {
+		sel := dbr.NewSelect("tableA").AddColumns("a")
+		sel.DB.Querier = dbMock{}
+		rows, err := sel.RowsContext(context.Background())
+		assert.Nil(t, rows)
+		assert.True(t, errors.IsNotSupported(err), "%+v", err)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	// "fmt"
 
 	"database/sql/driver"
+	"encoding/hex"
 	"reflect"
 	"strconv"
 	"strings"
@@ -49,6 +50,12 @@ var typeOfTime = reflect.TypeOf(time.Time{})
 // replace them with. It returns a blank string and error if the number of placeholders
 // does not match the number of arguments.
 func Preprocess(sql string, vals []interface{}) (string, error) {
+	return preprocess(sql, vals, false)
+}
+
+// preprocess implements Preprocess. If debug is true, pointers get
+// dereferenced and byte slices written as binary literals, see Interpolate.
+func preprocess(sql string, vals []interface{}, debug bool) (string, error) {
 	// Get the number of arguments to add to this query
 	if sql == "" {
 		if len(vals) != 0 {
@@ -71,7 +78,7 @@ func Preprocess(sql string, vals []interface{}) (string, error) {
 			if curVal >= len(vals) {
 				return "", errors.NewNotValidf(errArgMismatch)
 			}
-			if err := interpolate(buf, vals[curVal]); err != nil {
+			if err := interpolate(buf, vals[curVal], debug); err != nil {
 				return "", err
 			}
 			curVal++
@@ -81,14 +88,20 @@ func Preprocess(sql string, vals []interface{}) (string, error) {
 				return "", errors.NewNotValidf("[dbr] Preprocess: Invalid syntax")
 			}
 			if r == '"' {
-				r = '\''
+				// A double quoted literal gets written in single quotes,
+				// so the unescaped single quotes need an escape.
+				writeSingleQuoted(buf, sql[pos:pos+p])
+			} else {
+				buf.WriteRune(r)
+				buf.WriteString(sql[pos : pos+p])
+				buf.WriteRune(r)
 			}
-			buf.WriteRune(r)
-			buf.WriteString(sql[pos : pos+p])
-			buf.WriteRune(r)
 			pos += p + 1
 		case r == '[':
 			w := strings.IndexRune(sql[pos:], ']')
+			if w == -1 {
+				return "", errors.NewNotValidf("[dbr] Preprocess: Missing closing bracket")
+			}
 			col := sql[pos : pos+w]
 			dialect.EscapeIdent(buf, col)
 			pos += w + 1 // size of ']'
@@ -103,7 +116,20 @@ func Preprocess(sql string, vals []interface{}) (string, error) {
 	return buf.String(), nil
 }
 
-func interpolate(w QueryWriter, v interface{}) error {
+// writeSingleQuoted writes s enclosed in single quotes and escapes all single
+// quotes which are not already escaped.
+func writeSingleQuoted(w QueryWriter, s string) {
+	w.WriteRune('\'')
+	for i, r := range s {
+		if r == '\'' && (i == 0 || s[i-1] != '\\') {
+			w.WriteRune('\\')
+		}
+		w.WriteRune(r)
+	}
+	w.WriteRune('\'')
+}
+
+func interpolate(w QueryWriter, v interface{}, debug bool) error {
 	valuer, ok := v.(driver.Valuer)
 	if ok {
 		val, err := valuer.Value()
@@ -147,6 +173,17 @@ func interpolate(w QueryWriter, v interface{}) error {
 		} else {
 			return errors.NewNotValidf("[dbr] Interpolate: Invalid value for time")
 		}
+	case debug && kindOfV == reflect.Ptr:
+		if valueOfV.IsNil() {
+			w.WriteString("NULL")
+			return nil
+		}
+		return interpolate(w, valueOfV.Elem().Interface(), debug)
+	case debug && kindOfV == reflect.Slice && valueOfV.Type().Elem().Kind() == reflect.Uint8:
+		// []byte gets written as a binary literal and not as a list of numbers
+		w.WriteString("X'")
+		w.WriteString(hex.EncodeToString(valueOfV.Bytes()))
+		w.WriteRune('\'')
 	case kindOfV == reflect.Slice:
 		typeOfV := reflect.TypeOf(v)
 		subtype := typeOfV.Elem()
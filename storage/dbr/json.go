@@ -0,0 +1,102 @@
+package dbr
+
+import (
+	"strings"
+
+	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/errors"
+)
+
+// JSON builds the MySQL function JSON_EXTRACT(`column`, ?) to extract a value
+// from a JSON column. The path gets passed as an argument and never becomes
+// part of the SQL string. JSON implements interface ColumnExpression and can
+// be used in Select.ColumnExpr or via Compare as a ConditionArg.
+type JSON struct {
+	column string
+	path   string
+	alias  string
+}
+
+// JSONExtract creates a new JSON_EXTRACT expression. The column gets quoted,
+// a dot separates the table name or alias. The path must start with $, e.g.
+// $.key or $[0].key.
+//
+//	JSONExtract("e.data", "$.color") // JSON_EXTRACT(`e`.`data`, ?)
+func JSONExtract(column, path string) *JSON {
+	return &JSON{
+		column: column,
+		path:   path,
+	}
+}
+
+// As sets the alias of the expression when used as a column.
+func (j *JSON) As(alias string) *JSON {
+	j.alias = alias
+	return j
+}
+
+// ToSQL generates the JSON_EXTRACT expression and returns the path as the only
+// argument. Returns an Empty error if the column is missing and a NotValid
+// error if the path does not start with $.
+func (j *JSON) ToSQL() (string, []interface{}, error) {
+	if j.column == "" {
+		return "", nil, errors.NewEmptyf("[dbr] JSON.ToSQL: Column is missing")
+	}
+	if !strings.HasPrefix(j.path, "$") {
+		return "", nil, errors.NewNotValidf("[dbr] JSON.ToSQL: Path %q must start with $", j.path)
+	}
+
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+
+	buf.WriteString("JSON_EXTRACT(")
+	if err := Quoter.writeQuotedColumn(j.column, buf); err != nil {
+		return "", nil, errors.Wrap(err, "[dbr] JSON.ToSQL")
+	}
+	buf.WriteString(", ?)")
+
+	if j.alias != "" {
+		expr := buf.String()
+		buf.Reset()
+		if err := Quoter.writeAlias(buf, expr, j.alias); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] JSON.ToSQL")
+		}
+	}
+	return buf.String(), []interface{}{j.path}, nil
+}
+
+// jsonCompareOperators contains the operators allowed in JSON.Compare.
+var jsonCompareOperators = map[string]bool{
+	"=": true, "!=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true,
+	"LIKE": true, "NOT LIKE": true,
+}
+
+// Compare creates a condition which compares the extracted value with the
+// operator to the value, e.g. JSON_EXTRACT(`data`, ?) = ?. The path argument
+// precedes the value argument. An alias gets ignored. Allowed operators are
+// =, !=, <>, <, <=, >, >=, LIKE and NOT LIKE, all others return a NotValid
+// error.
+//
+//	Where(JSONExtract("data", "$.size").Compare(">", 3))
+func (j *JSON) Compare(operator string, value interface{}) ConditionArg {
+	return conditionArgFunc(func() (*whereFragment, error) {
+		operator := strings.ToUpper(strings.TrimSpace(operator))
+		if !jsonCompareOperators[operator] {
+			return nil, errors.NewNotValidf("[dbr] JSON.Compare: Operator %q not supported", operator)
+		}
+		jc := *j
+		jc.alias = ""
+		sql, args, err := jc.ToSQL()
+		if err != nil {
+			return nil, errors.Wrap(err, "[dbr] JSON.Compare")
+		}
+		args = append(args, value)
+		if err := argsValuer(&args); err != nil {
+			return nil, errors.Wrapf(err, "[dbr] JSON.Compare: %q; Value %v", sql, value)
+		}
+		return &whereFragment{
+			Condition: sql + " " + operator + " ?",
+			Values:    args,
+		}, nil
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ ColumnExpression = (*JSON)(nil)
+
+func TestJSONExtract_ToSQL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("column", func(t *testing.T) {
+		sql, args, err := JSONExtract("e.data", "$.color").As("color").ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "JSON_EXTRACT(`e`.`data`, ?) AS `color`", sql)
+		assert.Exactly(t, []interface{}{"$.color"}, args)
+	})
+	t.Run("empty column", func(t *testing.T) {
+		sql, args, err := JSONExtract("", "$.color").ToSQL()
+		assert.Empty(t, sql)
+		assert.Nil(t, args)
+		assert.True(t, errors.IsEmpty(err), "%+v", err)
+	})
+	t.Run("invalid path", func(t *testing.T) {
+		sql, args, err := JSONExtract("data", "color").ToSQL()
+		assert.Empty(t, sql)
+		assert.Nil(t, args)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+}
+
+func TestSelect_JSONExtract(t *testing.T) {
+	t.Parallel()
+
+	t.Run("select column", func(t *testing.T) {
+		sql, args, err := NewSelect("catalog_product_entity", "e").
+			AddColumns("e.sku").
+			ColumnExpr(JSONExtract("e.data", "$.color").As("color")).
+			Where(Eq{"e.entity_id": 4}).
+			ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT e.sku, JSON_EXTRACT(`e`.`data`, ?) AS `color` FROM `catalog_product_entity` AS `e` WHERE (`e`.`entity_id` = ?)", sql)
+		assert.Exactly(t, []interface{}{"$.color", 4}, args)
+	})
+
+	t.Run("where predicate", func(t *testing.T) {
+		sql, args, err := NewSelect("catalog_product_entity", "e").
+			AddColumns("e.sku").
+			Where(
+				ConditionRaw("e.type_id = ?", "simple"),
+				JSONExtract("e.data", "$.size").Compare(">", 3),
+				Eq{"e.attribute_set_id": 4},
+			).
+			ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT e.sku FROM `catalog_product_entity` AS `e` WHERE (e.type_id = ?) AND (JSON_EXTRACT(`e`.`data`, ?) > ?) AND (`e`.`attribute_set_id` = ?)", sql)
+		assert.Exactly(t, []interface{}{"simple", "$.size", 3, 4}, args)
+	})
+
+	t.Run("column and where", func(t *testing.T) {
+		sql, args, err := NewSelect("catalog_product_entity", "e").
+			ColumnExpr(JSONExtract("e.data", "$.color")).
+			Where(JSONExtract("e.data", "$.color").As("ignored").Compare("=", "red")).
+			ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT JSON_EXTRACT(`e`.`data`, ?) FROM `catalog_product_entity` AS `e` WHERE (JSON_EXTRACT(`e`.`data`, ?) = ?)", sql)
+		assert.Exactly(t, []interface{}{"$.color", "$.color", "red"}, args)
+	})
+
+	t.Run("operators", func(t *testing.T) {
+		for _, op := range []string{"=", "!=", "<>", "<", "<=", ">", ">=", "LIKE", "not like"} {
+			wf, err := JSONExtract("data", "$.size").Compare(op, 3).newWhereFragment()
+			assert.NoError(t, err, "%q: %+v", op, err)
+			assert.Exactly(t, "JSON_EXTRACT(`data`, ?) "+strings.ToUpper(op)+" ?", wf.Condition)
+		}
+		for _, op := range []string{"=1 OR 1=1 OR 1", "", "IS", "= ? OR"} {
+			_, err := JSONExtract("data", "$.size").Compare(op, 3).newWhereFragment()
+			assert.True(t, errors.IsNotValid(err), "%q: %+v", op, err)
+		}
+	})
+}
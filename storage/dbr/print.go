@@ -1,6 +1,10 @@
 package dbr
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/corestoreio/errors"
+)
 
 // QueryBuilder assembles a query and returns the raw SQL without parameter
 // substitution and the arguments.
@@ -8,14 +12,32 @@ type QueryBuilder interface {
 	ToSQL() (string, []interface{}, error)
 }
 
+// Interpolate replaces the question mark place holders in sql with the quoted
+// and escaped arguments for debugging and logging purposes. Strings get escaped
+// and quoted, byte slices written as hex binary literals, numbers formatted,
+// time.Time written in the MySQL date time format, pointers dereferenced and
+// nil values as NULL. It returns a NotValid error
+// if the number of place holders does not match the number of arguments or if
+// an argument cannot be represented.
+//
+// Interpolate is meant for debugging only. Do not use the returned SQL to
+// execute queries; use the Exec and Load functions of the builders instead.
+func Interpolate(sql string, args []interface{}) (string, error) {
+	s, err := preprocess(sql, args, true)
+	if err != nil {
+		return "", errors.Wrapf(err, "[dbr] Interpolate: %q", sql)
+	}
+	return s, nil
+}
+
 func makeSQL(b QueryBuilder) string {
 	sRaw, vals, err := b.ToSQL()
 	if err != nil {
 		return fmt.Sprintf("[dbr] ToSQL Error: %+v", err)
 	}
-	sql, err := Preprocess(sRaw, vals)
+	sql, err := Interpolate(sRaw, vals)
 	if err != nil {
-		return fmt.Sprintf("[dbr] Preprocess Error: %+v", err)
+		return fmt.Sprintf("[dbr] Interpolate Error: %+v", err)
 	}
 	return sql
 }
@@ -33,7 +55,8 @@ func (b *Insert) String() string {
 }
 
 // String returns a string representing a preprocessed, interpolated, query.
-// On error, the error gets printed. Fulfills interface fmt.Stringer.
+// On error, the error gets printed. Fulfills interface fmt.Stringer. Use it
+// only for debugging, see Interpolate.
 func (b *Select) String() string {
 	return makeSQL(b)
 }
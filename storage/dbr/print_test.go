@@ -16,8 +16,12 @@ package dbr_test
 
 import (
 	"fmt"
+	"testing"
+	"time"
 
 	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
 )
 
 // check if the types implement the interfaces
@@ -31,3 +35,71 @@ var _ dbr.QueryBuilder = (*dbr.Select)(nil)
 var _ dbr.QueryBuilder = (*dbr.Delete)(nil)
 var _ dbr.QueryBuilder = (*dbr.Update)(nil)
 var _ dbr.QueryBuilder = (*dbr.Insert)(nil)
+
+func TestInterpolate(t *testing.T) {
+	t.Parallel()
+
+	tm := time.Date(2016, 8, 31, 23, 59, 1, 0, time.UTC)
+	str := "c"
+	var nilInt *int64
+
+	tests := []struct {
+		sql     string
+		args    []interface{}
+		wantSQL string
+	}{
+		{"SELECT * FROM x WHERE a = ? AND b = ?", []interface{}{int64(-1), uint8(2)}, "SELECT * FROM x WHERE a = -1 AND b = 2"},
+		{"SELECT * FROM x WHERE a = ? OR a = ?", []interface{}{1.5, true}, "SELECT * FROM x WHERE a = 1.5 OR a = 1"},
+		{"SELECT * FROM x WHERE a = ?", []interface{}{"It's \\ \"q\""}, "SELECT * FROM x WHERE a = 'It\\'s \\\\ \\\"q\\\"'"},
+		{"SELECT * FROM x WHERE a = ?", []interface{}{[]byte("b'")}, "SELECT * FROM x WHERE a = X'6227'"},
+		{"SELECT * FROM x WHERE a = ?", []interface{}{[]byte{0x34, 0xFF, 0xFE}}, "SELECT * FROM x WHERE a = X'34fffe'"},
+		{"SELECT * FROM x WHERE a = ? AND b = ?", []interface{}{&str, nilInt}, "SELECT * FROM x WHERE a = 'c' AND b = NULL"},
+		{"SELECT * FROM x WHERE a = ? AND b IN ?", []interface{}{tm, []int{1, 2}}, "SELECT * FROM x WHERE a = '2016-08-31 23:59:01' AND b IN (1,2)"},
+		{"SELECT * FROM x WHERE a = '?' AND b = ?", []interface{}{nil}, "SELECT * FROM x WHERE a = '?' AND b = NULL"},
+		{"SELECT \"it's\", \"it\\'s\", \"a\"", nil, "SELECT 'it\\'s', 'it\\'s', 'a'"},
+		{"SELECT [a] FROM x", nil, "SELECT `a` FROM x"},
+	}
+	for i, test := range tests {
+		sql, err := dbr.Interpolate(test.sql, test.args)
+		assert.NoError(t, err, "Index %d => %+v", i, err)
+		assert.Exactly(t, test.wantSQL, sql, "Index %d", i)
+	}
+
+	t.Run("argument mismatch", func(t *testing.T) {
+		sql, err := dbr.Interpolate("SELECT * FROM x WHERE a = ? AND b = ?", []interface{}{1})
+		assert.Empty(t, sql)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+		sql, err = dbr.Interpolate("SELECT * FROM x WHERE a = ?", []interface{}{1, 2})
+		assert.Empty(t, sql)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+
+	t.Run("missing closing bracket", func(t *testing.T) {
+		sql, err := dbr.Interpolate("SELECT a[b", nil)
+		assert.Empty(t, sql)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+
+	t.Run("invalid UTF-8", func(t *testing.T) {
+		sql, err := dbr.Interpolate("SELECT * FROM x WHERE a = ?", []interface{}{string([]byte{0x34, 0xFF, 0xFE})})
+		assert.Empty(t, sql)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+
+	t.Run("Preprocess unchanged", func(t *testing.T) {
+		sql, err := dbr.Preprocess("SELECT * FROM x WHERE a IN ?", []interface{}{[]byte("xy")})
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT * FROM x WHERE a IN (120,121)", sql)
+
+		str := "c"
+		sql, err = dbr.Preprocess("SELECT * FROM x WHERE a = ?", []interface{}{&str})
+		assert.Empty(t, sql)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+}
+
+func TestSelect_String(t *testing.T) {
+	sel := dbr.NewSelect("tableA").AddColumns("a").Where(dbr.Eq{"b": "x'y"}, dbr.ConditionRaw("c > ?", 3))
+	assert.Exactly(t, "SELECT a FROM `tableA` WHERE (`b` = 'x\\'y') AND (c > 3)", sel.String())
+}
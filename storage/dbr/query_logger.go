@@ -0,0 +1,113 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr
+
+import (
+	"sync/atomic"
+
+	"github.com/corestoreio/log"
+)
+
+// QueryLogger logs the executed statements of the builders. The SQL string,
+// the number of arguments and the duration of the execution get logged with
+// level info. The logging can be switched on and off at runtime, the
+// functions are thread safe. Arguments get only logged after calling
+// LogArgs(true) because they might contain sensitive data.
+type QueryLogger struct {
+	log      log.Logger
+	disabled int32
+	logArgs  int32
+}
+
+// NewQueryLogger creates a new enabled QueryLogger which writes to l.
+func NewQueryLogger(l log.Logger) *QueryLogger {
+	return &QueryLogger{log: l}
+}
+
+// Enable switches the logging on or off.
+func (ql *QueryLogger) Enable(on bool) {
+	atomic.StoreInt32(&ql.disabled, boolToInt32(!on))
+}
+
+// IsEnabled returns true if the statements get logged.
+func (ql *QueryLogger) IsEnabled() bool {
+	return atomic.LoadInt32(&ql.disabled) == 0
+}
+
+// LogArgs switches the logging of the arguments of a statement on or off.
+func (ql *QueryLogger) LogArgs(on bool) {
+	atomic.StoreInt32(&ql.logArgs, boolToInt32(on))
+}
+
+// Listeners returns the listeners for all four builders. The Select listener
+// gets called with OnAfterQuery and the others with OnAfterExec.
+func (ql *QueryLogger) Listeners() []Listen {
+	return []Listen{
+		{
+			Name:       "dbr.QueryLogger.Select",
+			EventType:  OnAfterQuery,
+			SelectFunc: func(b *Select) { ql.write("dbr.QueryLogger.Select", b.Stats) },
+		},
+		{
+			Name:       "dbr.QueryLogger.Insert",
+			EventType:  OnAfterExec,
+			InsertFunc: func(b *Insert) { ql.write("dbr.QueryLogger.Insert", b.Stats) },
+		},
+		{
+			Name:       "dbr.QueryLogger.Update",
+			EventType:  OnAfterExec,
+			UpdateFunc: func(b *Update) { ql.write("dbr.QueryLogger.Update", b.Stats) },
+		},
+		{
+			Name:       "dbr.QueryLogger.Delete",
+			EventType:  OnAfterExec,
+			DeleteFunc: func(b *Delete) { ql.write("dbr.QueryLogger.Delete", b.Stats) },
+		},
+	}
+}
+
+func (ql *QueryLogger) addTo(lb *ListenerBucket) {
+	ls := ql.Listeners()
+	lb.Select.Add(ls...)
+	lb.Insert.Add(ls...)
+	lb.Update.Add(ls...)
+	lb.Delete.Add(ls...)
+}
+
+func (ql *QueryLogger) write(msg string, qs QueryStats) {
+	if !ql.IsEnabled() || !ql.log.IsInfo() {
+		return
+	}
+	fields := make([]log.Field, 0, 5)
+	fields = append(fields,
+		log.String("sql", qs.SQL),
+		log.Int("args", len(qs.Args)),
+		log.Duration("duration", qs.Duration),
+	)
+	if atomic.LoadInt32(&ql.logArgs) == 1 {
+		fields = append(fields, log.Object("arguments", qs.Args))
+	}
+	if qs.Err != nil {
+		fields = append(fields, log.Err(qs.Err))
+	}
+	ql.log.Info(msg, fields...)
+}
+
+func boolToInt32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log/logw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithQueryLogger(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, dbc.Options(dbr.WithQueryLogger(logw.NewLog(logw.WithWriter(buf), logw.WithLevel(logw.LevelInfo)))))
+	require.NotNil(t, dbc.QueryLogger)
+	assert.True(t, dbc.QueryLogger.IsEnabled())
+
+	sess := dbc.NewSession()
+
+	t.Run("Select without arguments", func(t *testing.T) {
+		defer buf.Reset()
+		dbMock.ExpectQuery("SELECT a FROM `tableA` WHERE \\(b = 'secret'\\)").
+			WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1))
+
+		var vals []int64
+		_, err := sess.Select("a").From("tableA").Where(dbr.ConditionRaw("b = ?", "secret")).LoadInt64s(&vals)
+		require.NoError(t, err, "%+v", err)
+
+		have := buf.String()
+		assert.Contains(t, have, `dbr.QueryLogger.Select`)
+		assert.Contains(t, have, "sql: \"SELECT a FROM `tableA` WHERE (b = ?)\"")
+		assert.Contains(t, have, `args: 1`)
+		assert.Contains(t, have, `duration: `)
+		assert.False(t, strings.Contains(have, `secret`), "Arguments must not be logged: %s", have)
+	})
+
+	t.Run("Update with arguments", func(t *testing.T) {
+		defer buf.Reset()
+		defer dbc.QueryLogger.LogArgs(false)
+		dbc.QueryLogger.LogArgs(true)
+
+		dbMock.ExpectExec("UPDATE `tableA` SET `b` = 'secret'").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := sess.Update("tableA").Set("b", "secret").Exec()
+		require.NoError(t, err, "%+v", err)
+
+		have := buf.String()
+		assert.Contains(t, have, `dbr.QueryLogger.Update`)
+		assert.Contains(t, have, "sql: \"UPDATE `tableA` SET `b` = ?\"")
+		assert.Contains(t, have, `args: 1`)
+		assert.Contains(t, have, `arguments: `)
+		assert.Contains(t, have, `secret`)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		defer buf.Reset()
+		dbc.QueryLogger.Enable(false)
+		defer dbc.QueryLogger.Enable(true)
+
+		dbMock.ExpectExec("DELETE FROM `tableA`").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		_, err := sess.DeleteFrom("tableA").Exec()
+		require.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "", buf.String())
+	})
+}
+
+func TestWithQueryLogger_Nil(t *testing.T) {
+	dbc, err := dbr.NewConnection(dbr.WithQueryLogger(nil))
+	assert.Nil(t, dbc)
+	assert.True(t, errors.IsEmpty(err), "%+v", err)
+}
@@ -1,93 +1,175 @@
 package dbr
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/errors"
+)
 
 const quote string = "`"
 const quoteRune rune = '`'
 const quoteByte byte = '`'
 
 // Quoter is the quoter to use for quoting text; use Mysql quoting by default.
-var Quoter = MysqlQuoter{
-	replacer: strings.NewReplacer(quote, ""),
+var Quoter = MysqlQuoter{}
+
+// MysqlQuoter implements Mysql-specific quoting. All functions write the
+// identifiers enclosed in back ticks and escape back ticks within an
+// identifier by doubling them. An identifier which is already correctly
+// quoted, e.g. `a` or `a`.`b`, gets written unchanged. Identifiers containing
+// a control character are not valid.
+type MysqlQuoter struct{}
+
+// writeQuotedColumn writes the column enclosed in back ticks. A dot
+// separates the table name or alias from the column name, e.g. a.b gets
+// written as `a`.`b`. Back ticks within the identifier get escaped by
+// doubling them. Control characters return a NotValid error.
+func (q MysqlQuoter) writeQuotedColumn(column string, sql QueryWriter) error {
+	return q.writeQuoted(column, true, sql)
 }
 
-// MysqlQuoter implements Mysql-specific quoting
-type MysqlQuoter struct {
-	replacer *strings.Replacer
+// writeQuoted validates the identifier and writes it quoted to sql. If
+// splitDot is true, the first dot separates the qualifier from the name.
+func (q MysqlQuoter) writeQuoted(ident string, splitDot bool, sql QueryWriter) error {
+	for i := 0; i < len(ident); i++ {
+		if c := ident[i]; c < ' ' || c == 0x7f {
+			return errors.NewNotValidf("[dbr] Identifier %q contains the control character %q", ident, c)
+		}
+	}
+	if isQuotedIdentifier(ident, splitDot) {
+		writeRawSQL(sql, ident)
+		return nil
+	}
+	if dot := strings.IndexByte(ident, '.'); splitDot && dot > 0 {
+		q.writeQuotedIdentifier(ident[:dot], sql)
+		_, _ = sql.WriteRune('.')
+		ident = ident[dot+1:]
+	}
+	q.writeQuotedIdentifier(ident, sql)
+	return nil
 }
 
-func (q MysqlQuoter) writeQuotedColumn(column string, sql QueryWriter) {
+// writeQuotedIdentifier writes the identifier quoted by the dialect of sql.
+func (q MysqlQuoter) writeQuotedIdentifier(ident string, sql QueryWriter) {
+	if d := dialectOf(sql); !isDialectMySQL(d) {
+		_, _ = sql.WriteString(d.QuoteIdentifier(ident))
+		return
+	}
 	_, _ = sql.WriteRune(quoteRune)
-	_, _ = sql.WriteString(column)
+	_, _ = sql.WriteString(strings.Replace(ident, quote, quote+quote, -1))
 	_, _ = sql.WriteRune(quoteRune)
 }
 
-func (q MysqlQuoter) unQuote(s string) string {
-	if strings.IndexByte(s, quoteByte) == -1 {
-		return s
+// isQuotedIdentifier reports true if s is enclosed in back ticks and all back
+// ticks within are doubled. If qualified is true, s can consist of two quoted
+// identifiers separated by a dot, e.g. `a`.`b`.
+func isQuotedIdentifier(s string, qualified bool) bool {
+	parts := 0
+	i := 0
+	for i < len(s) {
+		if s[i] != quoteByte {
+			return false
+		}
+		i++
+		closed := false
+		for i < len(s) {
+			if s[i] == quoteByte {
+				if i+1 < len(s) && s[i+1] == quoteByte {
+					i += 2
+					continue
+				}
+				closed = true
+				i++
+				break
+			}
+			i++
+		}
+		if !closed {
+			return false
+		}
+		parts++
+		if i == len(s) {
+			break
+		}
+		if !qualified || parts > 1 || s[i] != '.' {
+			return false
+		}
+		i++
+		if i == len(s) {
+			return false
+		}
+	}
+	return parts > 0
+}
+
+// mustQuote returns the result of fn or panics with its error.
+func mustQuote(fn func(QueryWriter) error) string {
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+	if err := fn(buf); err != nil {
+		panic(err)
 	}
-	return q.replacer.Replace(s)
+	return buf.String()
 }
 
 // QuoteAs quotes a with back ticks. First argument table or column name and
 // second argument can be an alias. Both parts will get quoted. If providing
-// only one part, then the AS parts get skipped.
+// only one part, then the AS parts get skipped. Panics with a NotValid error
+// if an identifier contains a control character.
 func (q MysqlQuoter) QuoteAs(exprAlias ...string) string {
-	return q.quoteAs(exprAlias...)
+	return mustQuote(func(w QueryWriter) error {
+		return q.writeQuoteAs(w, exprAlias...)
+	})
 }
 
-// Alias appends the the aliasName to the expression, e.g.: (e.price*x.tax) as `final_price`
+// Alias appends the the aliasName to the expression, e.g.: (e.price*x.tax) as
+// `final_price`. Panics with a NotValid error if the aliasName contains a
+// control character.
 func (q MysqlQuoter) Alias(expression, aliasName string) string {
-	return expression + " AS " + quote + q.unQuote(aliasName) + quote
+	return mustQuote(func(w QueryWriter) error {
+		return q.writeAlias(w, expression, aliasName)
+	})
 }
 
-// Quote returns a string like: `database`.`table` or `table` if prefix is empty
-func (q MysqlQuoter) Quote(prefix, name string) string {
-	// way faster than fmt or buffer ...
-	if prefix == "" {
-		return quote + q.unQuote(name) + quote
-	}
-	return quote + q.unQuote(prefix) + quote + "." + quote + q.unQuote(name) + quote
+func (q MysqlQuoter) writeAlias(w QueryWriter, expression, aliasName string) error {
+	_, _ = w.WriteString(expression)
+	_, _ = w.WriteString(" AS ")
+	return errors.Wrap(q.writeQuoted(aliasName, false, w), "[dbr] Quoter.Alias")
 }
 
-func (q MysqlQuoter) quoteAs(parts ...string) string {
+// Quote returns a string like: `database`.`table` or `table` if prefix is
+// empty. Panics with a NotValid error if an identifier contains a control
+// character.
+func (q MysqlQuoter) Quote(prefix, name string) string {
+	return mustQuote(func(w QueryWriter) error {
+		if prefix != "" {
+			if err := q.writeQuoted(prefix, false, w); err != nil {
+				return errors.Wrap(err, "[dbr] Quoter.Quote")
+			}
+			_, _ = w.WriteRune('.')
+		}
+		return errors.Wrap(q.writeQuoted(name, false, w), "[dbr] Quoter.Quote")
+	})
+}
 
-	lp := len(parts)
-	if lp == 2 && parts[1] == "" {
-		lp = 1
-		parts = parts[:1]
+// writeQuoteAs writes the first part as a table or column name, which can
+// contain a dot, and the remaining parts joined with an underscore as the
+// alias.
+func (q MysqlQuoter) writeQuoteAs(w QueryWriter, parts ...string) error {
+	if err := q.writeQuoted(parts[0], true, w); err != nil {
+		return errors.Wrap(err, "[dbr] Quoter.QuoteAs")
 	}
-
-	hasQuote0 := strings.ContainsRune(parts[0], quoteRune)
-	hasDot0 := strings.ContainsRune(parts[0], '.')
-
 	switch {
-	case lp == 1 && hasQuote0:
-		return parts[0] // already quoted
-	case lp > 1 && parts[1] == "" && !hasQuote0 && !hasDot0:
-		return quote + q.unQuote(parts[0]) + quote // must be quoted
-	case lp == 1 && !hasQuote0 && hasDot0:
-		return q.splitDotAndQuote(parts[0])
-	}
-
-	n := q.splitDotAndQuote(parts[0])
-
-	switch lp {
-	case 1:
-		return n
-	case 2:
-		return n + " AS " + quote + q.unQuote(parts[1]) + quote
+	case len(parts) == 1, len(parts) == 2 && parts[1] == "":
+		return nil
+	case len(parts) == 2:
+		_, _ = w.WriteString(" AS ")
+		return errors.Wrap(q.writeQuoted(parts[1], false, w), "[dbr] Quoter.QuoteAs")
 	default:
-		return n + " AS " + quote + q.unQuote(strings.Join(parts[1:], "_")) + quote
-	}
-}
-
-func (q MysqlQuoter) splitDotAndQuote(part string) string {
-	dotIndex := strings.Index(part, ".")
-	if dotIndex > 0 { // dot at a beginning of a string is illegal
-		return quote + q.unQuote(part[:dotIndex]) + quote + "." + quote + q.unQuote(part[dotIndex+1:]) + quote
+		_, _ = w.WriteString(" AS ")
+		return errors.Wrap(q.writeQuoted(strings.Join(parts[1:], "_"), false, w), "[dbr] Quoter.QuoteAs")
 	}
-	return quote + q.unQuote(part) + quote
 }
 
 // ColumnAlias is a helper func which transforms variadic arguments into a slice with a special
@@ -100,7 +182,7 @@ func (q MysqlQuoter) ColumnAlias(columns ...string) []string {
 	cols := make([]string, l/2)
 	j := 0
 	for i := 0; i < l; i = i + 2 {
-		cols[j] = q.quoteAs(columns[i], columns[i+1])
+		cols[j] = q.QuoteAs(columns[i], columns[i+1])
 		j++
 	}
 	return cols
@@ -108,15 +190,20 @@ func (q MysqlQuoter) ColumnAlias(columns ...string) []string {
 
 // TableColumnAlias prefixes all columns with a table name/alias and puts quotes around them.
 // If a column name has already been prefixed by a name or an alias it will be ignored.
+// Panics with a NotValid error if an identifier contains a control character.
 func (q MysqlQuoter) TableColumnAlias(t string, cols ...string) []string {
 	for i, c := range cols {
 		switch {
-		case strings.ContainsRune(c, quoteRune):
-			cols[i] = c
-		case strings.ContainsRune(c, '.'):
+		case isQuotedIdentifier(c, true), strings.ContainsRune(c, '.'):
 			cols[i] = q.QuoteAs(c)
 		default:
-			cols[i] = q.QuoteAs(t + "." + c)
+			cols[i] = mustQuote(func(w QueryWriter) error {
+				if err := q.writeQuoted(t, false, w); err != nil {
+					return errors.Wrap(err, "[dbr] Quoter.TableColumnAlias")
+				}
+				_, _ = w.WriteRune('.')
+				return errors.Wrap(q.writeQuoted(c, false, w), "[dbr] Quoter.TableColumnAlias")
+			})
 		}
 	}
 	return cols
@@ -3,6 +3,8 @@ package dbr
 import (
 	"testing"
 
+	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,11 +20,94 @@ func TestQuoteAs(t *testing.T) {
 		{[]string{"d.e"}, "`d`.`e`"},
 		{[]string{"`d`.`e`"}, "`d`.`e`"},
 		{[]string{"f", "g", "h"}, "`f` AS `g_h`"},
-		{[]string{"f", "g", "h`h"}, "`f` AS `g_hh`"},
+		{[]string{"f", "g", "h`h"}, "`f` AS `g_h``h`"},
+		{[]string{"a`b"}, "`a``b`"},
+		{[]string{"a`b", "c`d"}, "`a``b` AS `c``d`"},
+		{[]string{"t.a`b"}, "`t`.`a``b`"},
+		{[]string{"`a`b`"}, "```a``b```"},
+		{[]string{"`a` OR 1=1 OR `b`"}, "```a`` OR 1=1 OR ``b```"},
 	}
 	for i, test := range tests {
 		assert.Exactly(t, test.want, Quoter.QuoteAs(test.have...), "Index %d", i)
 	}
+	assert.Panics(t, func() { Quoter.QuoteAs("a\nb") })
+}
+
+func TestWriteQuotedColumn(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    string
+		wantErr bool
+	}{
+		{"a", "`a`", false},
+		{"t.entity_id", "`t`.`entity_id`", false},
+		{"a`b", "`a``b`", false},
+		{"a` = 1 OR 1=1 -- ", "`a`` = 1 OR 1=1 -- `", false},
+		{"t.a`; DROP TABLE b; --", "`t`.`a``; DROP TABLE b; --`", false},
+		{"a\x00b", "", true},
+		{"a\nb", "", true},
+		{"a\x7f", "", true},
+	}
+	for i, test := range tests {
+		buf := bufferpool.Get()
+		err := Quoter.writeQuotedColumn(test.have, buf)
+		if test.wantErr {
+			assert.True(t, errors.IsNotValid(err), "Index %d => %+v", i, err)
+		} else {
+			assert.NoError(t, err, "Index %d", i)
+			assert.Exactly(t, test.want, buf.String(), "Index %d", i)
+		}
+		bufferpool.Put(buf)
+	}
+}
+
+func TestWriteQuotedColumn_Builders(t *testing.T) {
+	s := createFakeSession()
+
+	sql, args, err := s.Select("a").From("b").Where(Eq{"b.c": 1}).ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE (`b`.`c` = ?)", sql)
+	assert.Exactly(t, []interface{}{1}, args)
+
+	sql, _, err = s.Select("a").From("b").Where(Eq{"c` = 1 OR `d": 1}).ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE (`c`` = 1 OR ``d` = ?)", sql)
+
+	_, _, err = s.Select("a").From("b").Where(Eq{"c\n": 1}).ToSQL()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	_, _, err = s.Update("a").Set("b\x00", 1).ToSQL()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	_, _, err = s.InsertInto("a").Columns("b\r").Values(1).ToSQL()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	sql, _, err = s.InsertInto("a").Columns("b`c").Values(1).ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "INSERT INTO a (`b``c`) VALUES (?)", sql)
+
+	sql, _, err = s.Select("a").From("b` WHERE 1=1 OR `c", "d`e").ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "SELECT a FROM `b`` WHERE 1=1 OR ``c` AS `d``e`", sql)
+
+	_, _, err = s.Select("a").From("b\n").ToSQL()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	sql, _, err = s.Update("a`b").Set("c", 1).ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "UPDATE `a``b` SET `c` = ?", sql)
+
+	sql, _, err = s.DeleteFrom("a`b").ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "DELETE FROM `a``b`", sql)
+
+	sql, _, err = s.Select("a").From("b").
+		PaginateKeyset(5, []string{"c` > 0 OR `d"}, map[string]interface{}{"c` > 0 OR `d": 1}).ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE ((`c`` > 0 OR ``d` > ?)) ORDER BY `c`` > 0 OR ``d` ASC LIMIT 5", sql)
+
+	_, _, err = s.Select("a").From("b").PaginateKeyset(5, []string{"c\x00"}, nil).ToSQL()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
 }
 
 // BenchmarkQuoteAs-4	 3000000	       417 ns/op	      48 B/op	       2 allocs/op
@@ -57,7 +142,7 @@ func BenchmarkQuoteQuote(b *testing.B) {
 	b.ResetTimer()
 	b.Run("Worse Case", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			if have := Quoter.Quote("database`Name", "table`Name"); have != want {
+			if have := Quoter.Quote("`databaseName`", "`tableName`"); have != want {
 				b.Fatalf("Have %s\nWant %s\n", have, want)
 			}
 		}
@@ -74,5 +159,22 @@ func BenchmarkQuoteQuote(b *testing.B) {
 func TestMysqlQuoter_Quote(t *testing.T) {
 	assert.Exactly(t, "`tableName`", Quoter.Quote("", "tableName"))
 	assert.Exactly(t, "`databaseName`.`tableName`", Quoter.Quote("databaseName", "tableName"))
-	assert.Exactly(t, "`databaseName`.`tableName`", Quoter.Quote("database`Name", "table`Name"))
+	assert.Exactly(t, "`databaseName`.`tableName`", Quoter.Quote("`databaseName`", "`tableName`"))
+	assert.Exactly(t, "`database``Name`.`table``Name`", Quoter.Quote("database`Name", "table`Name"))
+	assert.Exactly(t, "`a``b`", Quoter.Quote("", "a`b"))
+	assert.Exactly(t, "`a.b`", Quoter.Quote("", "a.b"))
+	assert.Panics(t, func() { Quoter.Quote("", "a\x00b") })
+}
+
+func TestMysqlQuoter_TableColumnAlias(t *testing.T) {
+	assert.Exactly(t,
+		[]string{"`t`.`a``b`", "`t`.`a`` OR 1=1 OR ``c`", "`x`.`a``b`", "`t`.`c`"},
+		Quoter.TableColumnAlias("t", "a`b", "a` OR 1=1 OR `c", "x.a`b", "`t`.`c`"),
+	)
+	assert.Exactly(t, []string{"`t``x`.`a`"}, Quoter.TableColumnAlias("t`x", "a"))
+}
+
+func TestMysqlQuoter_Alias(t *testing.T) {
+	assert.Exactly(t, "(a+b) AS `c``d`", Quoter.Alias("(a+b)", "c`d"))
+	assert.Exactly(t, "(a+b) AS `cd`", Quoter.Alias("(a+b)", "`cd`"))
 }
@@ -0,0 +1,108 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL error numbers which indicate that a statement can be retried.
+const (
+	mysqlErrLockWaitTimeout uint16 = 1205
+	mysqlErrLockDeadlock    uint16 = 1213
+)
+
+// WithDeadlockRetry enables a bounded retry of Exec and ExecContext when MySQL
+// reports a deadlock (error 1213) or a lock wait timeout (error 1205). Each
+// statement gets executed at most maxAttempts times. Before the n-th retry the
+// execution sleeps n times backoff. The retry applies only to a single Exec of
+// the builders created by a Session and never to statements executed within
+// an open transaction because MySQL rolls back the whole transaction in case
+// of a deadlock.
+func WithDeadlockRetry(maxAttempts int, backoff time.Duration) ConnectionOption {
+	return func(c *Connection) error {
+		if maxAttempts < 1 {
+			return errors.NewNotValidf("[dbr] WithDeadlockRetry: maxAttempts %d must be greater than zero", maxAttempts)
+		}
+		c.retryMaxAttempts = maxAttempts
+		c.retryBackoff = backoff
+		return nil
+	}
+}
+
+// isRetryableMySQLError reports true if err is caused by a deadlock or a lock
+// wait timeout.
+func isRetryableMySQLError(err error) bool {
+	me, ok := errors.Cause(err).(*mysql.MySQLError)
+	return ok && (me.Number == mysqlErrLockDeadlock || me.Number == mysqlErrLockWaitTimeout)
+}
+
+// retryExecer retries the execution of a statement in case of a deadlock or
+// lock wait timeout.
+type retryExecer struct {
+	db interface {
+		Execer
+		ExecerContext
+	}
+	maxAttempts int
+	backoff     time.Duration
+}
+
+func (re retryExecer) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return re.ExecContext(nil, query, args...)
+}
+
+// ExecContext executes the query and retries it. A nil context falls back to
+// the non-context aware Exec function of the database.
+func (re retryExecer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var err error
+	for attempt := 1; attempt <= re.maxAttempts; attempt++ {
+		var res sql.Result
+		res, err = execContext(ctx, re.db, query, args...)
+		if err == nil || !isRetryableMySQLError(err) {
+			return res, err
+		}
+		if attempt == re.maxAttempts {
+			break
+		}
+		if err := re.wait(ctx, attempt); err != nil {
+			return nil, errors.Wrap(err, "[dbr] retryExecer.ExecContext")
+		}
+	}
+	return nil, errors.Wrapf(err, "[dbr] retryExecer.ExecContext: Giving up after %d attempts", re.maxAttempts)
+}
+
+// wait sleeps attempt times the backoff duration or until the context has
+// been canceled.
+func (re retryExecer) wait(ctx context.Context, attempt int) error {
+	d := time.Duration(attempt) * re.backoff
+	if ctx == nil {
+		time.Sleep(d)
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+// execRetryMock returns the errors in the order of the slice. Once the slice
+// has been consumed, nil gets returned.
+type execRetryMock struct {
+	errs  []error
+	calls int
+}
+
+func (em *execRetryMock) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return em.ExecContext(context.Background(), query, args...)
+}
+
+func (em *execRetryMock) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	em.calls++
+	if em.calls <= len(em.errs) {
+		return nil, em.errs[em.calls-1]
+	}
+	return nil, nil
+}
+
+func TestWithDeadlockRetry(t *testing.T) {
+	t.Parallel()
+
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+	lockWait := &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}
+
+	t.Run("invalid attempts", func(t *testing.T) {
+		c, err := NewConnection(WithDeadlockRetry(0, time.Millisecond))
+		assert.Nil(t, c)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+
+	t.Run("session builders", func(t *testing.T) {
+		c, err := NewConnection(WithDeadlockRetry(3, time.Millisecond))
+		assert.NoError(t, err, "%+v", err)
+		sess := c.NewSession()
+		assert.IsType(t, retryExecer{}, sess.Update("a").DB.Execer)
+		assert.IsType(t, retryExecer{}, sess.InsertInto("a").DB.Execer)
+		assert.IsType(t, retryExecer{}, sess.DeleteFrom("a").DB.Execer)
+	})
+
+	t.Run("succeeds after retry", func(t *testing.T) {
+		em := &execRetryMock{errs: []error{deadlock, errors.Wrap(lockWait, "wrapped")}}
+		re := retryExecer{db: em, maxAttempts: 3, backoff: time.Microsecond}
+		_, err := re.Exec("UPDATE a SET b = 1")
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 3, em.calls)
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		em := &execRetryMock{errs: []error{deadlock, deadlock, deadlock, deadlock}}
+		u := NewUpdate("a").Set("b", 1)
+		u.DB.Execer = retryExecer{db: em, maxAttempts: 3, backoff: time.Microsecond}
+		res, err := u.ExecContext(context.Background())
+		assert.Nil(t, res)
+		assert.Exactly(t, deadlock, errors.Cause(err), "%+v", err)
+		assert.Exactly(t, 3, em.calls)
+	})
+
+	t.Run("no retry of other errors", func(t *testing.T) {
+		dup := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+		em := &execRetryMock{errs: []error{dup}}
+		re := retryExecer{db: em, maxAttempts: 3, backoff: time.Microsecond}
+		_, err := re.Exec("INSERT INTO a (b) VALUES (1)")
+		assert.Exactly(t, dup, errors.Cause(err), "%+v", err)
+		assert.Exactly(t, 1, em.calls)
+	})
+
+	t.Run("canceled context while waiting", func(t *testing.T) {
+		em := &execRetryMock{errs: []error{deadlock, deadlock}}
+		re := retryExecer{db: em, maxAttempts: 3, backoff: time.Hour}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		_, err := re.ExecContext(ctx, "DELETE FROM a")
+		assert.Exactly(t, context.DeadlineExceeded, errors.Cause(err), "%+v", err)
+		assert.Exactly(t, 1, em.calls)
+	})
+}
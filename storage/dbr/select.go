@@ -1,7 +1,6 @@
 package dbr
 
 import (
-	"strconv"
 	"strings"
 
 	"github.com/corestoreio/csfw/util/bufferpool"
@@ -9,6 +8,21 @@ import (
 	"github.com/corestoreio/log"
 )
 
+// LockMode defines the row locking clause of a SELECT statement.
+type LockMode uint8
+
+// List of supported row locking modes.
+const (
+	// LockNone writes no locking clause.
+	LockNone LockMode = iota
+	// LockForUpdate writes FOR UPDATE and sets exclusive locks on the read
+	// rows.
+	LockForUpdate
+	// LockInShareMode writes LOCK IN SHARE MODE and sets shared locks on the
+	// read rows.
+	LockInShareMode
+)
+
 // Select contains the clauses for a SELECT statement
 type Select struct {
 	Log log.Logger // Log optional logger
@@ -18,12 +32,21 @@ type Select struct {
 		QueryRower
 		Preparer
 	}
+	// Dialect writes the placeholders, quoted identifiers and LIMIT of the
+	// generated SQL. Nil defaults to MySQL.
+	Dialect Dialect
 
 	RawFullSQL   string
 	RawArguments []interface{}
 
 	IsDistinct bool
-	Columns    []string
+	// DistinctOnColumns contains the columns set via DistinctOn. MySQL does
+	// not support DISTINCT ON, hence only DISTINCT gets written.
+	DistinctOnColumns []string
+	Columns           []string
+	// ColumnArgs contains the arguments of the expressions added via Column.
+	// They get prepended to the arguments of the other clauses.
+	ColumnArgs []interface{}
 	FromTable  alias
 	WhereFragments
 	JoinFragments
@@ -34,10 +57,19 @@ type Select struct {
 	LimitValid      bool
 	OffsetCount     uint64
 	OffsetValid     bool
+	// LockMode defines the row locking clause at the end of the statement.
+	LockMode LockMode
 
 	// Listeners allows to dispatch certain functions in different
 	// situations.
 	Listeners SelectListeners
+	// Stats contains the statistics of the last execution. They get set
+	// before the OnAfterQuery listeners get dispatched.
+	Stats QueryStats
+	// lastSQL and lastArgs contain the statement and its arguments of the
+	// last call to ToSQL. They get copied into Stats after the execution.
+	lastSQL  string
+	lastArgs []interface{}
 	// PropagationStopped set to true if you would like to interrupt the
 	// listener chain. Once set to true all sub sequent calls of the next
 	// listeners will be suppressed.
@@ -46,6 +78,8 @@ type Select struct {
 	// has been requested. for every new iteration the propagation must stop at
 	// this position.
 	propagationStoppedAt int
+	// previousError any error occurred during construction the SQL statement
+	previousError error
 }
 
 // NewSelect creates a new object with a black hole logger.
@@ -60,11 +94,13 @@ func NewSelect(from ...string) *Select {
 func (sess *Session) Select(cols ...string) *Select {
 	s := &Select{
 		Log:     sess.Logger,
+		Dialect: sess.cxn.Dialect,
 		Columns: cols,
 	}
 	s.DB.Querier = sess.cxn.DB
 	s.DB.QueryRower = sess.cxn.DB
 	s.DB.Preparer = sess.cxn.DB
+	s.Listeners.Merge(sess.cxn.Listeners.Select)
 	return s
 }
 
@@ -72,12 +108,14 @@ func (sess *Session) Select(cols ...string) *Select {
 func (sess *Session) SelectBySQL(sql string, args ...interface{}) *Select {
 	s := &Select{
 		Log:          sess.Logger,
+		Dialect:      sess.cxn.Dialect,
 		RawFullSQL:   sql,
 		RawArguments: args,
 	}
 	s.DB.Querier = sess.cxn.DB
 	s.DB.QueryRower = sess.cxn.DB
 	s.DB.Preparer = sess.cxn.DB
+	s.Listeners.Merge(sess.cxn.Listeners.Select)
 	return s
 }
 
@@ -85,11 +123,13 @@ func (sess *Session) SelectBySQL(sql string, args ...interface{}) *Select {
 func (tx *Tx) Select(cols ...string) *Select {
 	s := &Select{
 		Log:     tx.Logger,
+		Dialect: tx.Dialect,
 		Columns: cols,
 	}
 	s.DB.Querier = tx.Tx
 	s.DB.QueryRower = tx.Tx
 	s.DB.Preparer = tx.Tx
+	s.Listeners.Merge(tx.Listeners.Select)
 	return s
 }
 
@@ -97,12 +137,14 @@ func (tx *Tx) Select(cols ...string) *Select {
 func (tx *Tx) SelectBySQL(sql string, args ...interface{}) *Select {
 	s := &Select{
 		Log:          tx.Logger,
+		Dialect:      tx.Dialect,
 		RawFullSQL:   sql,
 		RawArguments: args,
 	}
 	s.DB.Querier = tx.Tx
 	s.DB.QueryRower = tx.Tx
 	s.DB.Preparer = tx.Tx
+	s.Listeners.Merge(tx.Listeners.Select)
 	return s
 }
 
@@ -112,6 +154,16 @@ func (b *Select) Distinct() *Select {
 	return b
 }
 
+// DistinctOn marks the statement as a DISTINCT SELECT and records the columns
+// for a dialect specific DISTINCT ON rendering. MySQL writes a DISTINCT
+// applied to all selected columns. If no columns have been selected, the
+// DistinctOn columns become the selected columns.
+func (b *Select) DistinctOn(columns ...string) *Select {
+	b.IsDistinct = true
+	b.DistinctOnColumns = append(b.DistinctOnColumns, columns...)
+	return b
+}
+
 // From sets the table to SELECT FROM. If second argument will be provided this
 // is then considered as the alias. SELECT ... FROM table AS alias.
 func (b *Select) From(from ...string) *Select {
@@ -142,6 +194,44 @@ func (b *Select) AddColumnsAliases(colsAlias ...string) *Select {
 	return b
 }
 
+// Column appends a raw expression to the Columns slice. The expression gets
+// written as is, without quoting, e.g.: COUNT(*) or IF(a > ?, 1, 0). A non
+// empty alias gets quoted and appended with AS. The optional arguments replace
+// the place holders within the expression and are getting added in the order
+// of the columns.
+func (b *Select) Column(expression, alias string, args ...interface{}) *Select {
+	if b.previousError != nil {
+		return b
+	}
+	if err := argsValuer(&args); err != nil {
+		b.previousError = errors.Wrapf(err, "[dbr] Select.Column %q Args: %v", expression, args)
+		return b
+	}
+	if alias != "" {
+		expression = Quoter.Alias(expression, alias)
+	}
+	b.Columns = append(b.Columns, expression)
+	b.ColumnArgs = append(b.ColumnArgs, args...)
+	return b
+}
+
+// ColumnExpr appends the generated SQL of each expression to the Columns
+// slice and its arguments to the ColumnArgs, e.g. a Case expression.
+func (b *Select) ColumnExpr(exprs ...ColumnExpression) *Select {
+	for _, e := range exprs {
+		if b.previousError != nil {
+			return b
+		}
+		sql, args, err := e.ToSQL()
+		if err != nil {
+			b.previousError = errors.Wrap(err, "[dbr] Select.ColumnExpr")
+			return b
+		}
+		b.Column(sql, "", args...)
+	}
+	return b
+}
+
 // Where appends a WHERE clause to the statement for the given string and args
 // or map of column/value pairs
 func (b *Select) Where(args ...ConditionArg) *Select {
@@ -149,6 +239,12 @@ func (b *Select) Where(args ...ConditionArg) *Select {
 	return b
 }
 
+// WhereOr groups the conditions with OR and appends the group as one WHERE
+// clause, joined with AND to the other clauses. See ConditionOr.
+func (b *Select) WhereOr(args ...ConditionArg) *Select {
+	return b.Where(ConditionOr(args...))
+}
+
 // GroupBy appends a column to group the statement
 func (b *Select) GroupBy(group string) *Select {
 	b.GroupBys = append(b.GroupBys, group)
@@ -184,13 +280,25 @@ func (b *Select) Limit(limit uint64) *Select {
 	return b
 }
 
-// Offset sets an offset for the statement; overrides any existing OFFSET
+// Offset sets an offset for the statement; overrides any existing OFFSET. As
+// MySQL does not support an OFFSET without a LIMIT, a missing LIMIT gets
+// written as LIMIT 18446744073709551615. An offset of zero without a LIMIT
+// gets omitted.
 func (b *Select) Offset(offset uint64) *Select {
 	b.OffsetCount = offset
 	b.OffsetValid = true
 	return b
 }
 
+// Lock sets the row locking clause which gets written at the very end of the
+// statement. The locks are held until the transaction commits or rolls back.
+// Outside of a transaction, with auto commit enabled, the locks get released
+// immediately after the statement, hence it's a no-op.
+func (b *Select) Lock(mode LockMode) *Select {
+	b.LockMode = mode
+	return b
+}
+
 // Paginate sets LIMIT/OFFSET for the statement based on the given page/perPage
 // Assumes page/perPage are valid. Page and perPage must be >= 1
 func (b *Select) Paginate(page, perPage uint64) *Select {
@@ -199,31 +307,228 @@ func (b *Select) Paginate(page, perPage uint64) *Select {
 	return b
 }
 
+// PaginateKeyset sets a keyset (seek) pagination for the statement. The
+// keyColumns define the ascending ORDER BY and must form a unique ordering of
+// the rows, for example (created_at, entity_id), otherwise rows get skipped or
+// repeated between pages. A nil lastSeen returns the first page. For the next
+// page pass the values of the key columns of the last row of the previous
+// page. It writes for the columns a and b:
+//
+//	WHERE ((`a` > ?) OR (`a` = ? AND `b` > ?)) ORDER BY `a` ASC, `b` ASC LIMIT pageSize
+//
+// A key column missing in a non nil lastSeen returns a NotValid error in
+// ToSQL.
+func (b *Select) PaginateKeyset(pageSize uint64, keyColumns []string, lastSeen map[string]interface{}) *Select {
+	if b.previousError != nil {
+		return b
+	}
+	if len(keyColumns) == 0 {
+		b.previousError = errors.NewNotValidf("[dbr] Select.PaginateKeyset: Key columns cannot be empty")
+		return b
+	}
+
+	if lastSeen != nil {
+		var cond = bufferpool.Get()
+		defer bufferpool.Put(cond)
+		args := make([]interface{}, 0, len(keyColumns)*(len(keyColumns)+1)/2)
+		for i, kc := range keyColumns {
+			if _, ok := lastSeen[kc]; !ok {
+				b.previousError = errors.NewNotValidf("[dbr] Select.PaginateKeyset: Key column %q not found in lastSeen %v", kc, lastSeen)
+				return b
+			}
+			if i > 0 {
+				cond.WriteString(" OR ")
+			}
+			cond.WriteRune('(')
+			for _, prev := range keyColumns[:i] {
+				if err := Quoter.writeQuotedColumn(prev, cond); err != nil {
+					b.previousError = errors.Wrap(err, "[dbr] Select.PaginateKeyset")
+					return b
+				}
+				cond.WriteString(" = ? AND ")
+				args = append(args, lastSeen[prev])
+			}
+			if err := Quoter.writeQuotedColumn(kc, cond); err != nil {
+				b.previousError = errors.Wrap(err, "[dbr] Select.PaginateKeyset")
+				return b
+			}
+			cond.WriteString(" > ?)")
+			args = append(args, lastSeen[kc])
+		}
+		b.Where(ConditionRaw(cond.String(), args...))
+	}
+
+	var col = bufferpool.Get()
+	defer bufferpool.Put(col)
+	for _, kc := range keyColumns {
+		col.Reset()
+		if err := Quoter.writeQuotedColumn(kc, col); err != nil {
+			b.previousError = errors.Wrap(err, "[dbr] Select.PaginateKeyset")
+			return b
+		}
+		b.OrderDir(col.String(), true)
+	}
+	return b.Limit(pageSize)
+}
+
+// Count clones the Select into a new Select which counts the rows matching the
+// WHERE, JOIN and HAVING clauses of the original. The column list becomes
+// COUNT(column), the columns of the joined tables get removed and ORDER BY,
+// LIMIT and OFFSET get stripped. A DISTINCT Select counts with
+// COUNT(DISTINCT column). A DISTINCT Select counted with "*" gets wrapped into
+// SELECT COUNT(*) FROM (SELECT DISTINCT ...) AS t to count the distinct rows.
+// All conditions get deep copied so later changes to the original do not
+// affect the count query. Listeners do not get copied. GROUP BY clauses stay
+// and result in one count per group.
+func (b *Select) Count(column string) *Select {
+	if b.IsDistinct && column == "*" && b.RawFullSQL == "" {
+		return b.countDistinctRows()
+	}
+	countExpr := "COUNT(" + column + ")"
+	if b.IsDistinct {
+		countExpr = "COUNT(DISTINCT " + column + ")"
+	}
+
+	c := &Select{
+		Log:             b.Log,
+		DB:              b.DB,
+		Dialect:         b.Dialect,
+		Columns:         []string{countExpr},
+		FromTable:       b.FromTable,
+		WhereFragments:  b.WhereFragments.clone(),
+		HavingFragments: b.HavingFragments.clone(),
+		previousError:   b.previousError,
+	}
+	if b.RawFullSQL != "" {
+		c.previousError = errors.NewNotSupportedf("[dbr] Select.Count: Raw SQL cannot be counted: %q", b.RawFullSQL)
+	}
+	if b.GroupBys != nil {
+		c.GroupBys = append([]string(nil), b.GroupBys...)
+	}
+	if b.JoinFragments != nil {
+		c.JoinFragments = make(JoinFragments, len(b.JoinFragments))
+		for i, f := range b.JoinFragments {
+			c.JoinFragments[i] = &joinFragment{
+				JoinType:     f.JoinType,
+				Table:        f.Table,
+				OnConditions: WhereFragments(f.OnConditions).clone(),
+			}
+		}
+	}
+	return c
+}
+
+// countDistinctRows creates the count query for a DISTINCT Select counted with
+// "*". The original without ORDER BY, LIMIT, OFFSET and locking becomes a sub
+// query.
+func (b *Select) countDistinctRows() *Select {
+	inner := &Select{
+		Dialect:           b.Dialect,
+		IsDistinct:        true,
+		DistinctOnColumns: b.DistinctOnColumns,
+		Columns:           b.Columns,
+		ColumnArgs:        b.ColumnArgs,
+		FromTable:         b.FromTable,
+		WhereFragments:    b.WhereFragments,
+		JoinFragments:     b.JoinFragments,
+		GroupBys:          b.GroupBys,
+		HavingFragments:   b.HavingFragments,
+		previousError:     b.previousError,
+	}
+	c := &Select{
+		Log:     b.Log,
+		DB:      b.DB,
+		Dialect: b.Dialect,
+	}
+	sql, args, err := inner.ToSQL()
+	if err != nil {
+		c.previousError = errors.Wrap(err, "[dbr] Select.Count")
+		return c
+	}
+	c.RawFullSQL = "SELECT COUNT(*) FROM (" + sql + ") AS t"
+	c.RawArguments = args
+	return c
+}
+
+// countColumns returns the number of columns the SELECT statement will return
+// including the columns of the joined tables. The second argument reports
+// false if the number cannot be determined because of a raw SQL query or a
+// wildcard column.
+func (b *Select) countColumns() (int, bool) {
+	if b.RawFullSQL != "" {
+		return 0, false
+	}
+	cols := b.Columns
+	if len(cols) == 0 {
+		cols = b.DistinctOnColumns
+	}
+	for _, f := range b.JoinFragments {
+		cols = append(cols[:len(cols):len(cols)], f.Columns...)
+	}
+	for _, c := range cols {
+		if strings.IndexByte(c, '*') >= 0 {
+			return 0, false
+		}
+	}
+	return len(cols), true
+}
+
 // ToSQL serialized the Select to a SQL string
 // It returns the string with placeholders and a slice of query arguments
 func (b *Select) ToSQL() (string, []interface{}, error) {
+	sql, args, err := b.toSQL()
+	if err != nil {
+		b.lastSQL, b.lastArgs = "", nil
+		return "", nil, err
+	}
+	b.lastSQL, b.lastArgs = sql, args
+	return sql, args, nil
+}
+
+func (b *Select) toSQL() (string, []interface{}, error) {
+	var buf = bufferpool.Get()
+	defer bufferpool.Put(buf)
+	args, err := b.writeSQL(newDialectWriter(buf, b.Dialect))
+	if err != nil {
+		return "", nil, err
+	}
+	return buf.String(), args, nil
+}
+
+// writeSQL writes the statement in the dialect of sql and returns the
+// arguments. A raw SQL string must already be written in the dialect.
+func (b *Select) writeSQL(sql *dialectWriter) ([]interface{}, error) {
 
 	if err := b.Listeners.dispatch(OnBeforeToSQL, b); err != nil {
-		return "", nil, errors.Wrap(err, "[dbr] Select.Listeners.dispatch")
+		return nil, errors.Wrap(err, "[dbr] Select.Listeners.dispatch")
 	}
 	// TODO(CyS) implement SQL string cache. If cache set to true, then the finalized query will be written
 	// in the empty RawFullSQL field. if cache has been set to false, then query gets regenerated.
 
 	if b.RawFullSQL != "" {
-		return b.RawFullSQL, b.RawArguments, nil
+		sql.WriteString(b.RawFullSQL)
+		return b.RawArguments, nil
+	}
+
+	if b.previousError != nil {
+		return nil, errors.Wrap(b.previousError, "[dbr] Select.ToSQL")
 	}
 
 	if len(b.FromTable.Expression) == 0 {
-		return "", nil, errors.NewEmptyf(errTableMissing)
+		return nil, errors.NewEmptyf(errTableMissing)
 	}
-	if len(b.Columns) == 0 {
-		return "", nil, errors.NewEmptyf(errColumnsMissing)
+	columns := b.Columns
+	if len(columns) == 0 {
+		columns = b.DistinctOnColumns
+	}
+	if len(columns) == 0 {
+		return nil, errors.NewEmptyf(errColumnsMissing)
 	}
-
-	var sql = bufferpool.Get()
-	defer bufferpool.Put(sql)
 
 	var args []interface{}
+	if len(b.ColumnArgs) > 0 {
+		args = append(args, b.ColumnArgs...)
+	}
 
 	sql.WriteString("SELECT ")
 
@@ -231,39 +536,36 @@ func (b *Select) ToSQL() (string, []interface{}, error) {
 		sql.WriteString("DISTINCT ")
 	}
 
-	for i, s := range b.Columns {
+	for i, s := range columns {
 		if i > 0 {
 			sql.WriteString(", ")
 		}
-		sql.WriteString(s)
+		writeRawSQL(sql, s)
 	}
 
 	if len(b.JoinFragments) > 0 {
 		for _, f := range b.JoinFragments {
 			for _, c := range f.Columns {
 				sql.WriteString(", ")
-				sql.WriteString(c)
+				writeRawSQL(sql, c)
 			}
 		}
 	}
 
 	sql.WriteString(" FROM ")
-	sql.WriteString(b.FromTable.QuoteAs())
+	if err := b.FromTable.writeQuoteAs(sql); err != nil {
+		return nil, errors.Wrap(err, "[dbr] Select.ToSQL")
+	}
 
-	if len(b.JoinFragments) > 0 {
-		for _, f := range b.JoinFragments {
-			sql.WriteRune(' ')
-			sql.WriteString(f.JoinType)
-			sql.WriteString(" JOIN ")
-			sql.WriteString(f.Table.QuoteAs())
-			sql.WriteString(" ON ")
-			writeWhereFragmentsToSQL(f.OnConditions, sql, &args)
-		}
+	if err := writeJoinFragmentsToSQL(b.JoinFragments, sql, &args); err != nil {
+		return nil, errors.Wrap(err, "[dbr] Select.ToSQL.Join")
 	}
 
 	if len(b.WhereFragments) > 0 {
 		sql.WriteString(" WHERE ")
-		writeWhereFragmentsToSQL(b.WhereFragments, sql, &args)
+		if err := writeWhereFragmentsToSQL(b.WhereFragments, sql, &args); err != nil {
+			return nil, errors.Wrap(err, "[dbr] Select.ToSQL.Where")
+		}
 	}
 
 	if len(b.GroupBys) > 0 {
@@ -272,33 +574,24 @@ func (b *Select) ToSQL() (string, []interface{}, error) {
 			if i > 0 {
 				sql.WriteString(", ")
 			}
-			sql.WriteString(s)
+			writeRawSQL(sql, s)
 		}
 	}
 
 	if len(b.HavingFragments) > 0 {
 		sql.WriteString(" HAVING ")
-		writeWhereFragmentsToSQL(b.HavingFragments, sql, &args)
-	}
-
-	if len(b.OrderBys) > 0 {
-		sql.WriteString(" ORDER BY ")
-		for i, s := range b.OrderBys {
-			if i > 0 {
-				sql.WriteString(", ")
-			}
-			sql.WriteString(s)
+		if err := writeWhereFragmentsToSQL(b.HavingFragments, sql, &args); err != nil {
+			return nil, errors.Wrap(err, "[dbr] Select.ToSQL.Having")
 		}
 	}
 
-	if b.LimitValid {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(strconv.FormatUint(b.LimitCount, 10))
-	}
+	writeOrderLimitToSQL(sql, b.OrderBys, b.LimitValid, b.LimitCount, b.OffsetValid, b.OffsetCount)
 
-	if b.OffsetValid {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(strconv.FormatUint(b.OffsetCount, 10))
+	switch b.LockMode {
+	case LockForUpdate:
+		sql.WriteString(" FOR UPDATE")
+	case LockInShareMode:
+		sql.WriteString(" LOCK IN SHARE MODE")
 	}
-	return sql.String(), args, nil
+	return args, nil
 }
@@ -17,10 +17,12 @@ package dbr_test
 import (
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/util/cstesting"
 	"github.com/corestoreio/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSelect_Rows(t *testing.T) {
@@ -103,3 +105,248 @@ func TestSelect_Prepare(t *testing.T) {
 	})
 
 }
+
+func TestSelect_LoadInt64s(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+	sess := dbc.NewSession()
+
+	t.Run("skips NULL", func(t *testing.T) {
+		dbMock.ExpectQuery("SELECT id FROM `tableX`").WillReturnRows(
+			sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(nil).AddRow(5))
+
+		ids := []int64{1}
+		n, err := sess.Select("id").From("tableX").LoadInt64s(&ids)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 2, n)
+		assert.Exactly(t, []int64{1, 3, 5}, ids)
+	})
+
+	t.Run("too many columns", func(t *testing.T) {
+		dbMock.ExpectQuery("SELECT id, name FROM `tableX`").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "x"))
+
+		var ids []int64
+		n, err := sess.Select("id", "name").From("tableX").LoadInt64s(&ids)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		assert.Exactly(t, 0, n)
+		assert.Nil(t, ids)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		dbMock.ExpectQuery("SELECT id FROM `tableX`").WillReturnError(errors.NewAlreadyClosedf("Who closed myself?"))
+
+		var ids []int64
+		n, err := sess.Select("id").From("tableX").LoadInt64s(&ids)
+		assert.True(t, errors.IsAlreadyClosed(err), "%+v", err)
+		assert.Exactly(t, 0, n)
+	})
+}
+
+func TestSelect_LoadStrings(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+	sess := dbc.NewSession()
+
+	t.Run("skips NULL", func(t *testing.T) {
+		dbMock.ExpectQuery("SELECT name FROM `tableX`").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).AddRow("a").AddRow(nil).AddRow("").AddRow("b"))
+
+		var names []string
+		n, err := sess.Select("name").From("tableX").LoadStrings(&names)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 3, n)
+		assert.Exactly(t, []string{"a", "", "b"}, names)
+	})
+
+	t.Run("too many columns", func(t *testing.T) {
+		dbMock.ExpectQuery("SELECT id, name FROM `tableX`").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "x"))
+
+		var names []string
+		n, err := sess.Select("id", "name").From("tableX").LoadStrings(&names)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		assert.Exactly(t, 0, n)
+		assert.Nil(t, names)
+	})
+}
+
+func TestSelect_LoadMaps(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+	sess := dbc.NewSession()
+
+	t.Run("typed values and NULL", func(t *testing.T) {
+		dbMock.ExpectQuery("SELECT id, sku, price FROM `tableX`").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "sku", "price"}).
+				AddRow(int64(3), []byte("SKU-3"), 2.5).
+				AddRow(int64(4), nil, nil))
+
+		var rows []map[string]interface{}
+		n, err := sess.Select("id", "sku", "price").From("tableX").LoadMaps(&rows)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 2, n)
+		assert.Exactly(t, []map[string]interface{}{
+			{"id": int64(3), "sku": []byte("SKU-3"), "price": 2.5},
+			{"id": int64(4), "sku": nil, "price": nil},
+		}, rows)
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		dbMock.ExpectQuery("SELECT id FROM `tableX`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		var rows []map[string]interface{}
+		n, err := sess.Select("id").From("tableX").LoadMaps(&rows)
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, 0, n)
+		assert.Nil(t, rows)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		dbMock.ExpectQuery("SELECT id FROM `tableX`").WillReturnError(errors.NewAlreadyClosedf("Who closed myself?"))
+
+		var rows []map[string]interface{}
+		n, err := sess.Select("id").From("tableX").LoadMaps(&rows)
+		assert.True(t, errors.IsAlreadyClosed(err), "%+v", err)
+		assert.Exactly(t, 0, n)
+	})
+}
+
+type loadStructsBase struct {
+	ID        int64  `db:"entity_id"`
+	Name      string `db:"name"`
+	UpdatedAt string
+}
+
+type loadStructsMeta struct {
+	MetaTitle string `db:"meta_title"`
+	Name      string `db:"name"`
+}
+
+type loadStructsProduct struct {
+	loadStructsBase
+	// Name overlaps with the embedded structs and wins because it's the
+	// outermost field.
+	Name string `db:"name"`
+	SKU  string `db:"sku"`
+	loadStructsMeta
+	Ignored loadStructsMeta `db:"-"`
+}
+
+func TestSelect_LoadStructs_Embedded(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	dbMock.ExpectQuery("SELECT entity_id, name, sku, updated_at, meta_title FROM `catalog_product_entity`").WillReturnRows(
+		sqlmock.NewRows([]string{"entity_id", "name", "sku", "updated_at", "meta_title"}).
+			AddRow(int64(3), "Gopher", "SKU-3", "2016-08-31", "Gopher Plush").
+			AddRow(int64(4), "Elephant", "SKU-4", "2016-09-01", "PHP Elephant"))
+
+	var prods []*loadStructsProduct
+	n, err := dbc.NewSession().Select("entity_id", "name", "sku", "updated_at", "meta_title").
+		From("catalog_product_entity").LoadStructs(&prods)
+	require.NoError(t, err, "%+v", err)
+	assert.Exactly(t, 2, n)
+	require.Len(t, prods, 2)
+
+	want := &loadStructsProduct{
+		loadStructsBase: loadStructsBase{ID: 3, UpdatedAt: "2016-08-31"},
+		Name:            "Gopher",
+		SKU:             "SKU-3",
+		loadStructsMeta: loadStructsMeta{MetaTitle: "Gopher Plush"},
+	}
+	assert.Exactly(t, want, prods[0])
+	assert.Exactly(t, int64(4), prods[1].ID)
+	assert.Exactly(t, "Elephant", prods[1].Name)
+	assert.Exactly(t, "PHP Elephant", prods[1].MetaTitle)
+	assert.Exactly(t, "", prods[1].loadStructsBase.Name)
+	assert.Exactly(t, "", prods[1].Ignored.MetaTitle)
+}
+
+type loadStructsNamed struct {
+	ID   int64           `db:"entity_id"`
+	Meta loadStructsMeta // named field, gets searched like an embedded one
+}
+
+func TestSelect_LoadStructs_NamedField(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	dbMock.ExpectQuery("SELECT entity_id, meta_title, name FROM `catalog_product_entity`").WillReturnRows(
+		sqlmock.NewRows([]string{"entity_id", "meta_title", "name"}).
+			AddRow(int64(3), "Gopher Plush", "Gopher"))
+
+	var prods []*loadStructsNamed
+	n, err := dbc.NewSession().Select("entity_id", "meta_title", "name").
+		From("catalog_product_entity").LoadStructs(&prods)
+	require.NoError(t, err, "%+v", err)
+	assert.Exactly(t, 1, n)
+	require.Len(t, prods, 1)
+	assert.Exactly(t, &loadStructsNamed{
+		ID:   3,
+		Meta: loadStructsMeta{MetaTitle: "Gopher Plush", Name: "Gopher"},
+	}, prods[0])
+}
+
+func TestSelect_Count_LoadValue(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	sel := dbc.NewSession().Select("e.entity_id", "e.sku").From("catalog_product_entity", "e").
+		LeftJoin(
+			dbr.JoinTable("catalog_product_website", "w"),
+			dbr.JoinColumns("w.website_id"),
+			dbr.ConditionRaw("w.product_id = e.entity_id"),
+			dbr.ConditionRaw("w.website_id = ?", 2),
+		).
+		Where(dbr.ConditionRaw("e.type_id = ?", "simple")).
+		OrderBy("e.sku").
+		Paginate(3, 20)
+
+	dbMock.ExpectQuery(cstesting.SQLMockQuoteMeta("SELECT COUNT(e.entity_id) FROM `catalog_product_entity` AS `e` LEFT JOIN `catalog_product_website` AS `w` ON (w.product_id = e.entity_id) AND (w.website_id = 2) WHERE (e.type_id = 'simple')")).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(e.entity_id)"}).AddRow(42))
+
+	var count int64
+	assert.NoError(t, sel.Count("e.entity_id").LoadValue(&count))
+	assert.Exactly(t, int64(42), count)
+
+	sql, _, err := sel.ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "SELECT e.entity_id, e.sku, w.website_id FROM `catalog_product_entity` AS `e` LEFT JOIN `catalog_product_website` AS `w` ON (w.product_id = e.entity_id) AND (w.website_id = ?) WHERE (e.type_id = ?) ORDER BY e.sku LIMIT 20 OFFSET 40", sql)
+}
@@ -1,5 +1,7 @@
 package dbr
 
+import "github.com/corestoreio/errors"
+
 // JoinFragments defines multiple join conditions.
 type JoinFragments []*joinFragment
 
@@ -50,3 +52,20 @@ func (b *Select) LeftJoin(table, columns []string, onConditions ...ConditionArg)
 func (b *Select) RightJoin(table, columns []string, onConditions ...ConditionArg) *Select {
 	return b.join("RIGHT", table, columns, onConditions...)
 }
+
+// writeJoinFragmentsToSQL writes all join constructs with their ON conditions.
+func writeJoinFragmentsToSQL(fragments JoinFragments, w QueryWriter, args *[]interface{}) error {
+	for _, f := range fragments {
+		_, _ = w.WriteRune(' ')
+		_, _ = w.WriteString(f.JoinType)
+		_, _ = w.WriteString(" JOIN ")
+		if err := f.Table.writeQuoteAs(w); err != nil {
+			return errors.Wrapf(err, "[dbr] writeJoinFragmentsToSQL: %q", f.Table.Expression)
+		}
+		_, _ = w.WriteString(" ON ")
+		if err := writeWhereFragmentsToSQL(f.OnConditions, w, args); err != nil {
+			return errors.Wrapf(err, "[dbr] writeJoinFragmentsToSQL: %q", f.Table.Expression)
+		}
+	}
+	return nil
+}
@@ -1,15 +1,32 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
 	"reflect"
+	"time"
 
+	"github.com/corestoreio/csfw/util/null"
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/log"
 )
 
 // Rows executes a query and returns many rows. Does no interpolation.
 func (b *Select) Rows() (*sql.Rows, error) {
+	return b.RowsContext(nil)
+}
+
+// RowsContext same as Rows but respects the context. All listeners gets called
+// before the context aware function of the database gets invoked. A nil
+// context falls back to the non-context aware Query function of the database.
+// The OnAfterQuery listeners get called after the execution of the query.
+func (b *Select) RowsContext(ctx context.Context) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := b.rows(ctx)
+	return rows, b.dispatchAfterQuery(start, -1, err)
+}
+
+func (b *Select) rows(ctx context.Context) (*sql.Rows, error) {
 
 	sqlStr, args, err := b.ToSQL()
 	if err != nil {
@@ -21,7 +38,7 @@ func (b *Select) Rows() (*sql.Rows, error) {
 		defer log.WhenDone(b.Log).Info("dbr.Select.Rows.Timing", log.String("sql", sqlStr))
 	}
 
-	rows, err := b.DB.Query(sqlStr, args...)
+	rows, err := queryContext(ctx, b.DB.Querier, sqlStr, args...)
 	return rows, errors.Wrap(err, "[store] Select.Rows.QueryContext")
 }
 
@@ -29,23 +46,38 @@ func (b *Select) Rows() (*sql.Rows, error) {
 // always returns a non-nil value. Errors are deferred until Row's Scan method
 // is called.
 func (b *Select) Row() *sql.Row {
+	return b.RowContext(nil)
+}
+
+// RowContext same as Row but respects the context. A nil context falls back to
+// the non-context aware QueryRow function of the database. Unlike the other
+// context aware functions, which return a NotSupported error, a QueryRower
+// not implementing QueryRowerContext also falls back to QueryRow and ignores
+// the context, because *sql.Row cannot transport an error.
+func (b *Select) RowContext(ctx context.Context) *sql.Row {
 
 	sqlStr, args, err := b.ToSQL()
 	if err != nil {
 		panic(err) // todo remove panic and log error .... ?
 		// return nil, errors.Wrap(err, "[store] Select.Rows.ToSQL")
 	}
-	return b.DB.QueryRow(sqlStr, args...)
+	return queryRowContext(ctx, b.DB.QueryRower, sqlStr, args...)
 }
 
 // Prepare prepares a SQL statement.
 func (b *Select) Prepare() (*sql.Stmt, error) {
+	return b.PrepareContext(nil)
+}
+
+// PrepareContext same as Prepare but bound to the context. A nil context falls
+// back to the non-context aware Prepare function of the database.
+func (b *Select) PrepareContext(ctx context.Context) (*sql.Stmt, error) {
 
 	sqlStr, _, err := b.ToSQL()
 	if err != nil {
 		return nil, errors.Wrap(err, "[store] Select.Rows.ToSQL")
 	}
-	stmt, err := b.DB.Prepare(sqlStr)
+	stmt, err := prepareContext(ctx, b.DB.Preparer, sqlStr)
 	return stmt, errors.Wrap(err, "[store] Select.Rows.QueryContext")
 }
 
@@ -61,6 +93,18 @@ func (b *Select) Prepare() (*sql.Stmt, error) {
 // number of items found (which is not necessarily the # of items set). Slow
 // because of the massive use of reflection.
 func (b *Select) LoadStructs(dest interface{}) (int, error) {
+	return b.LoadStructsContext(nil, dest)
+}
+
+// LoadStructsContext same as LoadStructs but respects the context. A nil context
+// falls back to the non-context aware Query function of the database.
+func (b *Select) LoadStructsContext(ctx context.Context, dest interface{}) (int, error) {
+	start := time.Now()
+	n, err := b.loadStructs(ctx, dest)
+	return n, b.dispatchAfterQuery(start, n, err)
+}
+
+func (b *Select) loadStructs(ctx context.Context, dest interface{}) (int, error) {
 	//
 	// Validate the dest, and extract the reflection values we need.
 	//
@@ -100,7 +144,7 @@ func (b *Select) LoadStructs(dest interface{}) (int, error) {
 		return 0, errors.Wrap(err, "[dbr] Select.LoadStructs.ToSQL")
 	}
 
-	fullSQL, err := Preprocess(tSQL, tArg)
+	fullSQL, tArg, err := preprocessDialect(b.Dialect, tSQL, tArg)
 	if err != nil {
 		return 0, errors.Wrap(err, "[dbr] Select.LoadStructs.Preprocess")
 	}
@@ -113,7 +157,7 @@ func (b *Select) LoadStructs(dest interface{}) (int, error) {
 	}
 
 	// Run the query:
-	rows, err := b.DB.Query(fullSQL)
+	rows, err := queryContext(ctx, b.DB.Querier, fullSQL, tArg...)
 	if err != nil {
 		return 0, errors.Wrap(err, "[dbr] Select.LoadStructs.query")
 	}
@@ -172,6 +216,18 @@ func (b *Select) LoadStructs(dest interface{}) (int, error) {
 // dest must be a pointer to a struct Returns ErrNotFound behaviour. Slow
 // because of the massive use of reflection.
 func (b *Select) LoadStruct(dest interface{}) error {
+	return b.LoadStructContext(nil, dest)
+}
+
+// LoadStructContext same as LoadStruct but respects the context. A nil context
+// falls back to the non-context aware Query function of the database.
+func (b *Select) LoadStructContext(ctx context.Context, dest interface{}) error {
+	start := time.Now()
+	err := b.loadStruct(ctx, dest)
+	return b.dispatchAfterQuery(start, foundRows(err), err)
+}
+
+func (b *Select) loadStruct(ctx context.Context, dest interface{}) error {
 	//
 	// Validate the dest, and extract the reflection values we need.
 	//
@@ -193,7 +249,7 @@ func (b *Select) LoadStruct(dest interface{}) error {
 		return errors.Wrap(err, "[dbr] Select.LoadStruct.ToSQL")
 	}
 
-	fullSQL, err := Preprocess(tSQL, tArg)
+	fullSQL, tArg, err := preprocessDialect(b.Dialect, tSQL, tArg)
 	if err != nil {
 		return err
 	}
@@ -203,7 +259,7 @@ func (b *Select) LoadStruct(dest interface{}) error {
 	}
 
 	// Run the query:
-	rows, err := b.DB.Query(fullSQL)
+	rows, err := queryContext(ctx, b.DB.Querier, fullSQL, tArg...)
 	if err != nil {
 		return errors.Wrap(err, "[dbr] Select.load_one.query")
 	}
@@ -252,6 +308,18 @@ func (b *Select) LoadStruct(dest interface{}) error {
 // primitive values Returns ErrNotFound behaviour if no value was found, and it
 // was therefore not set. Slow because of the massive use of reflection.
 func (b *Select) LoadValues(dest interface{}) (int, error) {
+	return b.LoadValuesContext(nil, dest)
+}
+
+// LoadValuesContext same as LoadValues but respects the context. A nil context
+// falls back to the non-context aware Query function of the database.
+func (b *Select) LoadValuesContext(ctx context.Context, dest interface{}) (int, error) {
+	start := time.Now()
+	n, err := b.loadValues(ctx, dest)
+	return n, b.dispatchAfterQuery(start, n, err)
+}
+
+func (b *Select) loadValues(ctx context.Context, dest interface{}) (int, error) {
 	// Validate the dest and reflection values we need
 
 	// This must be a pointer to a slice
@@ -285,7 +353,7 @@ func (b *Select) LoadValues(dest interface{}) (int, error) {
 		return 0, errors.Wrap(err, "[dbr] Select.load_values.ToSQL")
 	}
 
-	fullSQL, err := Preprocess(tSQL, tArg)
+	fullSQL, tArg, err := preprocessDialect(b.Dialect, tSQL, tArg)
 	if err != nil {
 		return 0, err
 	}
@@ -297,7 +365,7 @@ func (b *Select) LoadValues(dest interface{}) (int, error) {
 	}
 
 	// Run the query:
-	rows, err := b.DB.Query(fullSQL)
+	rows, err := queryContext(ctx, b.DB.Querier, fullSQL, tArg...)
 	if err != nil {
 		return numberOfRowsReturned, errors.Wrap(err, "[dbr] Select.LoadValues.query")
 	}
@@ -330,14 +398,31 @@ func (b *Select) LoadValues(dest interface{}) (int, error) {
 
 // LoadValue executes the Select and loads the resulting data into a primitive
 // value Returns ErrNotFound if no value was found, and it was therefore not
-// set. Slow because of the massive use of reflection.
+// set. Slow because of the massive use of reflection. Besides primitive types
+// and sql.Scanner, dest can be a *time.Time, *[]byte or *sql.RawBytes. A NULL
+// value sets those three to their zero value, which is nil for the byte
+// slices. The byte slices contain a copy of the data, so a *sql.RawBytes
+// stays valid after the rows have been closed. Pointers to named types of the
+// primitive kinds and pointers to pointers of the supported types get
+// handled by database/sql, where a NULL value sets the inner pointer to nil.
+// All other pointer types, like structs, maps or non-byte slices, return a
+// NotSupported error.
 func (b *Select) LoadValue(dest interface{}) error {
-	// Validate the dest
-	valueOfDest := reflect.ValueOf(dest)
-	kindOfDest := valueOfDest.Kind()
+	return b.LoadValueContext(nil, dest)
+}
 
-	if kindOfDest != reflect.Ptr {
-		return errors.NewNotValidf("[dbr] Destination must be a pointer")
+// LoadValueContext same as LoadValue but respects the context. A nil context
+// falls back to the non-context aware Query function of the database.
+func (b *Select) LoadValueContext(ctx context.Context, dest interface{}) error {
+	start := time.Now()
+	err := b.loadValue(ctx, dest)
+	return b.dispatchAfterQuery(start, foundRows(err), err)
+}
+
+func (b *Select) loadValue(ctx context.Context, dest interface{}) error {
+	scanDest, assign, err := valueScanDest(dest)
+	if err != nil {
+		return errors.Wrap(err, "[dbr] Select.LoadValue")
 	}
 
 	//
@@ -348,7 +433,7 @@ func (b *Select) LoadValue(dest interface{}) error {
 		return errors.Wrap(err, "[dbr] Select.LoadValue.ToSQL")
 	}
 
-	fullSQL, err := Preprocess(tSQL, tArg)
+	fullSQL, tArg, err := preprocessDialect(b.Dialect, tSQL, tArg)
 	if err != nil {
 		return err
 	}
@@ -358,14 +443,20 @@ func (b *Select) LoadValue(dest interface{}) error {
 	}
 
 	// Run the query:
-	rows, err := b.DB.Query(fullSQL)
+	rows, err := queryContext(ctx, b.DB.Querier, fullSQL, tArg...)
 	if err != nil {
 		return errors.Wrap(err, "[dbr] Select.LoadValue.Query")
 	}
 	defer rows.Close()
 
 	if rows.Next() {
-		return errors.Wrap(rows.Scan(dest), "[dbr] Select.LoadValue.Scan")
+		if err := rows.Scan(scanDest); err != nil {
+			return errors.Wrap(err, "[dbr] Select.LoadValue.Scan")
+		}
+		if assign != nil {
+			assign()
+		}
+		return nil
 	}
 
 	if err := rows.Err(); err != nil {
@@ -374,3 +465,244 @@ func (b *Select) LoadValue(dest interface{}) error {
 
 	return errors.NewNotFoundf("[dbr] Entry not found")
 }
+
+// valueScanDest returns the argument for rows.Scan for the destination of
+// LoadValue. The optional assign function copies the scanned value into dest
+// and must be called after a successful Scan.
+func valueScanDest(dest interface{}) (scanDest interface{}, assign func(), _ error) {
+	switch d := dest.(type) {
+	case sql.Scanner:
+		return d, nil, nil
+	case *time.Time:
+		nt := new(null.Time)
+		return nt, func() { *d = nt.Time }, nil
+	case *[]byte:
+		// database/sql copies the data and sets nil for a NULL value.
+		return d, nil, nil
+	case *sql.RawBytes:
+		// The memory of RawBytes gets reused by the next call to Next or
+		// Close, so we need a copy.
+		var buf []byte
+		return &buf, func() { *d = buf }, nil
+	}
+
+	valueOfDest := reflect.ValueOf(dest)
+	if valueOfDest.Kind() != reflect.Ptr || valueOfDest.IsNil() {
+		return nil, nil, errors.NewNotValidf("[dbr] Destination must be a pointer")
+	}
+	if !isScannable(valueOfDest.Type().Elem()) {
+		return nil, nil, errors.NewNotSupportedf("[dbr] Destination type %T not supported", dest)
+	}
+	// Named types and pointers like **string get handled by database/sql.
+	return dest, nil, nil
+}
+
+var typeScanner = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// isScannable reports whether database/sql can scan into a pointer to t.
+func isScannable(t reflect.Type) bool {
+	if reflect.PtrTo(t).Implements(typeScanner) || t == reflect.TypeOf(time.Time{}) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Interface,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Ptr:
+		return isScannable(t.Elem())
+	}
+	return false
+}
+
+// LoadInt64s executes the Select and appends the values of the single column
+// to dest. Rows containing NULL get skipped. A result set with more than one
+// column returns a NotValid error. Returns the number of appended values.
+func (b *Select) LoadInt64s(dest *[]int64) (int, error) {
+	return b.LoadInt64sContext(nil, dest)
+}
+
+// LoadInt64sContext same as LoadInt64s but respects the context. A nil
+// context falls back to the non-context aware Query function of the database.
+func (b *Select) LoadInt64sContext(ctx context.Context, dest *[]int64) (int, error) {
+	var ni sql.NullInt64
+	return b.loadColumn(ctx, "LoadInt64s", &ni, func() bool {
+		if ni.Valid {
+			*dest = append(*dest, ni.Int64)
+		}
+		return ni.Valid
+	})
+}
+
+// LoadStrings executes the Select and appends the values of the single column
+// to dest. Rows containing NULL get skipped. A result set with more than one
+// column returns a NotValid error. Returns the number of appended values.
+func (b *Select) LoadStrings(dest *[]string) (int, error) {
+	return b.LoadStringsContext(nil, dest)
+}
+
+// LoadStringsContext same as LoadStrings but respects the context. A nil
+// context falls back to the non-context aware Query function of the database.
+func (b *Select) LoadStringsContext(ctx context.Context, dest *[]string) (int, error) {
+	var ns sql.NullString
+	return b.loadColumn(ctx, "LoadStrings", &ns, func() bool {
+		if ns.Valid {
+			*dest = append(*dest, ns.String)
+		}
+		return ns.Valid
+	})
+}
+
+// LoadMaps executes the Select and appends for each row a map to dest. The
+// keys of the map are the column names. The values are of the type returned
+// by the driver, mostly int64, float64, time.Time or []byte. NULL values are
+// nil. Returns the number of appended maps.
+func (b *Select) LoadMaps(dest *[]map[string]interface{}) (int, error) {
+	return b.LoadMapsContext(nil, dest)
+}
+
+// LoadMapsContext same as LoadMaps but respects the context. A nil context
+// falls back to the non-context aware Query function of the database.
+func (b *Select) LoadMapsContext(ctx context.Context, dest *[]map[string]interface{}) (int, error) {
+	start := time.Now()
+	n, err := b.loadMaps(ctx, dest)
+	return n, b.dispatchAfterQuery(start, n, err)
+}
+
+func (b *Select) loadMaps(ctx context.Context, dest *[]map[string]interface{}) (int, error) {
+	tSQL, tArg, err := b.ToSQL()
+	if err != nil {
+		return 0, errors.Wrap(err, "[dbr] Select.LoadMaps.ToSQL")
+	}
+
+	fullSQL, tArg, err := preprocessDialect(b.Dialect, tSQL, tArg)
+	if err != nil {
+		return 0, errors.Wrap(err, "[dbr] Select.LoadMaps.Preprocess")
+	}
+
+	if b.Log != nil && b.Log.IsInfo() {
+		defer log.WhenDone(b.Log).Info("dbr.Select.LoadMaps.QueryContext.timing", log.String("sql", fullSQL))
+	}
+
+	rows, err := queryContext(ctx, b.DB.Querier, fullSQL, tArg...)
+	if err != nil {
+		return 0, errors.Wrap(err, "[dbr] Select.LoadMaps.Query")
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, errors.Wrap(err, "[dbr] Select.LoadMaps.Columns")
+	}
+
+	values := make([]interface{}, len(columns))
+	scanArgs := make([]interface{}, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	loaded := 0
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return loaded, errors.Wrap(err, "[dbr] Select.LoadMaps.Scan")
+		}
+		m := make(map[string]interface{}, len(columns))
+		for i, c := range columns {
+			v := values[i]
+			if bv, ok := v.([]byte); ok {
+				// the driver might reuse the underlying array for the next row
+				v = append([]byte(nil), bv...)
+			}
+			m[c] = v
+		}
+		*dest = append(*dest, m)
+		loaded++
+	}
+	if err := rows.Err(); err != nil {
+		return loaded, errors.Wrap(err, "[dbr] Select.LoadMaps.Rows_err")
+	}
+	return loaded, nil
+}
+
+// loadColumn runs the query and scans each row of the single column into
+// scanDest. The function appendFn gets called after each scan and must report
+// whether the value has been appended.
+func (b *Select) loadColumn(ctx context.Context, name string, scanDest interface{}, appendFn func() bool) (int, error) {
+	start := time.Now()
+	n, err := b.loadColumnRows(ctx, name, scanDest, appendFn)
+	return n, b.dispatchAfterQuery(start, n, err)
+}
+
+func (b *Select) loadColumnRows(ctx context.Context, name string, scanDest interface{}, appendFn func() bool) (int, error) {
+	tSQL, tArg, err := b.ToSQL()
+	if err != nil {
+		return 0, errors.Wrapf(err, "[dbr] Select.%s.ToSQL", name)
+	}
+
+	fullSQL, tArg, err := preprocessDialect(b.Dialect, tSQL, tArg)
+	if err != nil {
+		return 0, errors.Wrapf(err, "[dbr] Select.%s.Preprocess", name)
+	}
+
+	if b.Log != nil && b.Log.IsInfo() {
+		defer log.WhenDone(b.Log).Info("dbr.Select."+name+".QueryContext.timing", log.String("sql", fullSQL))
+	}
+
+	rows, err := queryContext(ctx, b.DB.Querier, fullSQL, tArg...)
+	if err != nil {
+		return 0, errors.Wrapf(err, "[dbr] Select.%s.Query", name)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, errors.Wrapf(err, "[dbr] Select.%s.Columns", name)
+	}
+	if len(columns) != 1 {
+		return 0, errors.NewNotValidf("[dbr] Select.%s: Expecting one column but got %d: %v", name, len(columns), columns)
+	}
+
+	appended := 0
+	for rows.Next() {
+		if err := rows.Scan(scanDest); err != nil {
+			return appended, errors.Wrapf(err, "[dbr] Select.%s.Scan", name)
+		}
+		if appendFn() {
+			appended++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return appended, errors.Wrapf(err, "[dbr] Select.%s.Rows.Err", name)
+	}
+	return appended, nil
+}
+
+// dispatchAfterQuery sets the statistics and dispatches the OnAfterQuery
+// listeners. The error of the query has precedence over the error of the
+// listeners.
+func (b *Select) dispatchAfterQuery(start time.Time, rows int, err error) error {
+	b.Stats = QueryStats{
+		Duration:     time.Since(start),
+		Rows:         rows,
+		RowsAffected: -1,
+		LastInsertID: -1,
+		Err:          err,
+		SQL:          b.lastSQL,
+		Args:         b.lastArgs,
+	}
+	if lErr := b.Listeners.dispatch(OnAfterQuery, b); lErr != nil && err == nil {
+		return errors.Wrap(lErr, "[dbr] Select.Listeners.dispatch")
+	}
+	return err
+}
+
+// foundRows returns one if a single row has been loaded successfully.
+func foundRows(err error) int {
+	if err != nil {
+		return 0
+	}
+	return 1
+}
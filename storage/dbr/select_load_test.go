@@ -0,0 +1,132 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect_LoadValue_Types(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+	sess := dbc.NewSession()
+
+	expectValue := func(v interface{}) {
+		dbMock.ExpectQuery("SELECT a FROM `tableA`").
+			WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(v))
+	}
+	now := time.Date(2017, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	t.Run("time.Time", func(t *testing.T) {
+		expectValue(now)
+		var tm time.Time
+		require.NoError(t, sess.Select("a").From("tableA").LoadValue(&tm))
+		assert.True(t, now.Equal(tm), "Have %s Want %s", tm, now)
+	})
+	t.Run("time.Time from text", func(t *testing.T) {
+		expectValue([]byte("2017-03-04 05:06:07"))
+		var tm time.Time
+		require.NoError(t, sess.Select("a").From("tableA").LoadValue(&tm))
+		assert.True(t, now.Equal(tm), "Have %s Want %s", tm, now)
+	})
+	t.Run("time.Time NULL", func(t *testing.T) {
+		expectValue(nil)
+		tm := now
+		require.NoError(t, sess.Select("a").From("tableA").LoadValue(&tm))
+		assert.True(t, tm.IsZero(), "Have %s", tm)
+	})
+	t.Run("[]byte", func(t *testing.T) {
+		expectValue([]byte("blob"))
+		var b []byte
+		require.NoError(t, sess.Select("a").From("tableA").LoadValue(&b))
+		assert.Exactly(t, []byte("blob"), b)
+	})
+	t.Run("[]byte NULL", func(t *testing.T) {
+		expectValue(nil)
+		b := []byte("old")
+		require.NoError(t, sess.Select("a").From("tableA").LoadValue(&b))
+		assert.Nil(t, b)
+	})
+	t.Run("sql.RawBytes", func(t *testing.T) {
+		expectValue([]byte("raw"))
+		var rb sql.RawBytes
+		require.NoError(t, sess.Select("a").From("tableA").LoadValue(&rb))
+		assert.Exactly(t, sql.RawBytes("raw"), rb)
+	})
+	t.Run("sql.RawBytes NULL", func(t *testing.T) {
+		expectValue(nil)
+		rb := sql.RawBytes("old")
+		require.NoError(t, sess.Select("a").From("tableA").LoadValue(&rb))
+		assert.Nil(t, rb)
+	})
+	t.Run("*string", func(t *testing.T) {
+		expectValue("gopher")
+		var s *string
+		require.NoError(t, sess.Select("a").From("tableA").LoadValue(&s))
+		require.NotNil(t, s)
+		assert.Exactly(t, "gopher", *s)
+	})
+	t.Run("*string NULL", func(t *testing.T) {
+		expectValue(nil)
+		s := new(string)
+		require.NoError(t, sess.Select("a").From("tableA").LoadValue(&s))
+		assert.Nil(t, s)
+	})
+	t.Run("named type", func(t *testing.T) {
+		type code string
+		expectValue("de")
+		var c code
+		require.NoError(t, sess.Select("a").From("tableA").LoadValue(&c))
+		assert.Exactly(t, code("de"), c)
+	})
+	t.Run("**time.Time", func(t *testing.T) {
+		now := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+		expectValue(now)
+		var tm *time.Time
+		require.NoError(t, sess.Select("a").From("tableA").LoadValue(&tm))
+		require.NotNil(t, tm)
+		assert.Exactly(t, now, *tm)
+	})
+	t.Run("not supported", func(t *testing.T) {
+		for i, dest := range []interface{}{
+			&struct{ A int }{},
+			&map[string]int{},
+			&[]int{},
+			new(*struct{ A int }),
+		} {
+			err := sess.Select("a").From("tableA").LoadValue(dest)
+			assert.True(t, errors.IsNotSupported(err), "Index %d: %+v", i, err)
+		}
+	})
+	t.Run("not a pointer", func(t *testing.T) {
+		var i int
+		err := sess.Select("a").From("tableA").LoadValue(i)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+}
@@ -1,6 +1,7 @@
 package dbr
 
 import (
+	"database/sql/driver"
 	"testing"
 
 	"github.com/corestoreio/errors"
@@ -90,6 +91,86 @@ func TestSelectFullToSQL(t *testing.T) {
 
 }
 
+func TestSelect_LimitOffset(t *testing.T) {
+	s := createFakeSession()
+
+	t.Run("offset only", func(t *testing.T) {
+		sql, _, err := s.Select("a").From("c").OrderBy("l").Offset(8).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT a FROM `c` ORDER BY l LIMIT 18446744073709551615 OFFSET 8", sql)
+	})
+	t.Run("zero offset only", func(t *testing.T) {
+		sql, _, err := s.Select("a").From("c").OrderBy("l").Offset(0).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT a FROM `c` ORDER BY l", sql)
+	})
+	t.Run("limit only", func(t *testing.T) {
+		sql, _, err := s.Select("a").From("c").Limit(7).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT a FROM `c` LIMIT 7", sql)
+	})
+	t.Run("limit and offset", func(t *testing.T) {
+		sql, _, err := s.Select("a").From("c").Offset(8).Limit(7).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT a FROM `c` LIMIT 7 OFFSET 8", sql)
+	})
+}
+
+func TestSelect_Distinct(t *testing.T) {
+	s := createFakeSession()
+
+	t.Run("wildcard", func(t *testing.T) {
+		sql, args, err := s.Select("*").From("tableA").Distinct().ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT DISTINCT * FROM `tableA`", sql)
+		assert.Nil(t, args)
+	})
+	t.Run("columns", func(t *testing.T) {
+		sql, _, err := s.Select("a", "b").From("tableA").Distinct().Where(ConditionRaw("c = ?", 1)).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT DISTINCT a, b FROM `tableA` WHERE (c = ?)", sql)
+	})
+	t.Run("no columns", func(t *testing.T) {
+		sql, _, err := s.Select().From("tableA").Distinct().ToSQL()
+		assert.Empty(t, sql)
+		assert.True(t, errors.IsEmpty(err), "%+v", err)
+	})
+	t.Run("DistinctOn with columns", func(t *testing.T) {
+		sel := s.Select("a", "b").From("tableA").DistinctOn("a")
+		sql, _, err := sel.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT DISTINCT a, b FROM `tableA`", sql)
+		assert.Exactly(t, []string{"a"}, sel.DistinctOnColumns)
+	})
+	t.Run("DistinctOn without columns", func(t *testing.T) {
+		sel := NewSelect("tableA").DistinctOn("a", "b")
+		sql, _, err := sel.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT DISTINCT a, b FROM `tableA`", sql)
+		assert.Nil(t, sel.Columns)
+		n, ok := sel.countColumns()
+		assert.True(t, ok)
+		assert.Exactly(t, 2, n)
+	})
+	t.Run("DistinctOn wildcard", func(t *testing.T) {
+		sql, _, err := s.Select("*").From("tableA").DistinctOn("a").ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT DISTINCT * FROM `tableA`", sql)
+	})
+	t.Run("listener", func(t *testing.T) {
+		sel := s.Select("a").From("tableA")
+		sel.Listeners.Add(Listen{
+			EventType: OnBeforeToSQL,
+			SelectFunc: func(b *Select) {
+				b.Distinct().AddColumns("b")
+			},
+		})
+		sql, _, err := sel.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT DISTINCT a, b FROM `tableA`", sql)
+	})
+}
+
 func TestSelectPaginateOrderDirToSQL(t *testing.T) {
 	s := createFakeSession()
 
@@ -155,6 +236,61 @@ func TestSelect_Condition_Null(t *testing.T) {
 	assert.Exactly(t, []interface{}{3}, args)
 }
 
+func TestSelect_ConditionNamed(t *testing.T) {
+	s := createFakeSession()
+
+	t.Run("repeated name", func(t *testing.T) {
+		sql, args, err := s.Select("a").From("tableA").
+			Where(ConditionNamed("b = :id OR c = :id", map[string]interface{}{"id": 5})).
+			ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT a FROM `tableA` WHERE (b = ? OR c = ?)", sql)
+		assert.Exactly(t, []interface{}{5, 5}, args)
+	})
+
+	t.Run("order of occurrence", func(t *testing.T) {
+		sql, args, err := s.Select("a").From("tableA").
+			Where(ConditionRaw("x = ?", "x")).
+			Where(ConditionNamed("store_id = :store AND (b = :b1 OR b = :b2 OR store_id2 = :store)", map[string]interface{}{
+				"b2":    "two",
+				"store": int64(3),
+				"b1":    "one",
+			})).
+			Having(ConditionNamed("COUNT(*) > :cnt", map[string]interface{}{"cnt": 7})).
+			ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT a FROM `tableA` WHERE (x = ?) AND (store_id = ? AND (b = ? OR b = ? OR store_id2 = ?)) HAVING (COUNT(*) > ?)", sql)
+		assert.Exactly(t, []interface{}{"x", int64(3), "one", "two", int64(3), 7}, args)
+	})
+
+	t.Run("ignores quotes and casts", func(t *testing.T) {
+		sql, args, err := s.Select("a").From("tableA").
+			Where(ConditionNamed("b > '10:30:00' AND `c:d` = :val AND e = \"x:y\" AND f = :val::text", map[string]interface{}{"val": 1})).
+			ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT a FROM `tableA` WHERE (b > '10:30:00' AND `c:d` = ? AND e = \"x:y\" AND f = ?::text)", sql)
+		assert.Exactly(t, []interface{}{1, 1}, args)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		sql, args, err := s.Select("a").From("tableA").
+			Where(ConditionNamed("b = :id OR c = :idx", map[string]interface{}{"id": 5})).
+			ToSQL()
+		assert.Empty(t, sql)
+		assert.Nil(t, args)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+
+	t.Run("join", func(t *testing.T) {
+		sql, args, err := s.Select("a").From("tableA", "tA").
+			Join([]string{"tableB", "tB"}, []string{"tB.c"}, ConditionNamed("tA.id = tB.id AND tB.store = :store", map[string]interface{}{"store": 2})).
+			ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT a, tB.c FROM `tableA` AS `tA` INNER JOIN `tableB` AS `tB` ON (tA.id = tB.id AND tB.store = ?)", sql)
+		assert.Exactly(t, []interface{}{2}, args)
+	})
+}
+
 func TestSelectWhereMapSQL(t *testing.T) {
 	s := createFakeSession()
 
@@ -221,6 +357,159 @@ func TestSelectWhereEqSQL(t *testing.T) {
 	}
 }
 
+func TestSelectWhereNullEqSQL(t *testing.T) {
+	s := createFakeSession()
+
+	tests := []struct {
+		cond     ConditionArg
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{Eq{"a": nil}, "SELECT a FROM `b` WHERE (`a` IS NULL)", nil},
+		{NullEq{"a": nil}, "SELECT a FROM `b` WHERE (`a` <=> ?)", []interface{}{nil}},
+		{Eq{"a": 1}, "SELECT a FROM `b` WHERE (`a` = ?)", []interface{}{1}},
+		{NullEq{"a": 1}, "SELECT a FROM `b` WHERE (`a` <=> ?)", []interface{}{1}},
+		{NullEq{"a": []int{2}}, "SELECT a FROM `b` WHERE (`a` <=> ?)", []interface{}{2}},
+		{NullEq{"a": []int{2, 3}}, "SELECT a FROM `b` WHERE (`a` IN ?)", []interface{}{[]int{2, 3}}},
+	}
+	for i, test := range tests {
+		sql, args, err := s.Select("a").From("b").Where(test.cond).ToSQL()
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.wantSQL, sql, "Index %d", i)
+		assert.Exactly(t, test.wantArgs, args, "Index %d", i)
+	}
+
+	sql, args, err := s.Select("a").From("b").Where(Eq{"c": nil}, NullEq{"d": nil}).ToSQL()
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE (`c` IS NULL) AND (`d` <=> ?)", sql)
+	assert.Exactly(t, []interface{}{nil}, args)
+}
+
+func TestSelectWhereRawExpandSQL(t *testing.T) {
+	s := createFakeSession()
+
+	tests := []struct {
+		cond     ConditionArg
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{ConditionRawExpand("a IN ?", []int{}), "SELECT a FROM `b` WHERE (1=0)", nil},
+		{ConditionRawExpand("a IN ?", []int(nil)), "SELECT a FROM `b` WHERE (1=0)", nil},
+		{ConditionRawExpand("a NOT IN ?", []int{}), "SELECT a FROM `b` WHERE (1=1)", nil},
+		{ConditionRawExpand("c = ? AND `t`.`a` not in ?", "x", []string{}), "SELECT a FROM `b` WHERE (c = ? AND 1=1)", []interface{}{"x"}},
+		{ConditionRawExpand("LOWER(a) IN ? OR c = ?", []string{}, "x"), "SELECT a FROM `b` WHERE (1=0 OR c = ?)", []interface{}{"x"}},
+		{ConditionRawExpand("a IN ?", []int{1}), "SELECT a FROM `b` WHERE (a IN (?))", []interface{}{1}},
+		{ConditionRawExpand("a NOT IN ? AND c = ?", []int64{1, 2, 3}, "x"), "SELECT a FROM `b` WHERE (a NOT IN (?,?,?) AND c = ?)", []interface{}{int64(1), int64(2), int64(3), "x"}},
+		{ConditionRawExpand("c = ? OR a in?", "x", []string{"y", "z"}), "SELECT a FROM `b` WHERE (c = ? OR a in(?,?))", []interface{}{"x", "y", "z"}},
+		{ConditionRawExpand("c = 'IN ?' OR a IN ?", []int{4, 5}), "SELECT a FROM `b` WHERE (c = 'IN ?' OR a IN (?,?))", []interface{}{4, 5}},
+		// not an IN operator, the slice stays a single argument
+		{ConditionRawExpand("FIND_IN_SET(a, ?)", []int{4, 5}), "SELECT a FROM `b` WHERE (FIND_IN_SET(a, ?))", []interface{}{[]int{4, 5}}},
+		{ConditionRawExpand("a = ?", []int{4, 5}), "SELECT a FROM `b` WHERE (a = ?)", []interface{}{[]int{4, 5}}},
+		{ConditionRawExpand("a IN ?", []byte("xy")), "SELECT a FROM `b` WHERE (a IN ?)", []interface{}{[]byte("xy")}},
+	}
+	for i, test := range tests {
+		sql, args, err := s.Select("a").From("b").Where(test.cond).ToSQL()
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.wantSQL, sql, "Index %d", i)
+		assert.Exactly(t, test.wantArgs, args, "Index %d", i)
+	}
+
+	// the left operand cannot be replaced
+	for i, cond := range []ConditionArg{
+		ConditionRawExpand("? NOT IN ?", 1, []string{}),
+		ConditionRawExpand("'x' NOT IN ?", []string{}),
+	} {
+		_, err := cond.newWhereFragment()
+		assert.True(t, errors.IsNotValid(err), "Index %d: %+v", i, err)
+	}
+	sql, args, err := s.Select("a").From("b").Where(ConditionRawExpand("? IN ?", 1, []string{})).ToSQL()
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE (? IN (NULL))", sql)
+	assert.Exactly(t, []interface{}{1}, args)
+
+	sql, args, err = s.Select("a").From("b").Where(ConditionRawExpand("a IN ?", []int{1, 2})).ToSQL()
+	assert.NoError(t, err)
+	fullSQL, err := Preprocess(sql, args)
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE (a IN (1,2))", fullSQL)
+}
+
+func TestSelectWhereInSQL(t *testing.T) {
+	s := createFakeSession()
+
+	tests := []struct {
+		cond     ConditionArg
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{ConditionIn("a"), "SELECT a FROM `b` WHERE (1=0)", nil},
+		{ConditionNotIn("a"), "SELECT a FROM `b` WHERE (1=1)", nil},
+		{ConditionIn("a", []int{}), "SELECT a FROM `b` WHERE (1=0)", nil},
+		{ConditionNotIn("a", []int(nil)), "SELECT a FROM `b` WHERE (1=1)", nil},
+		{ConditionIn("a", 1), "SELECT a FROM `b` WHERE (`a` IN (?))", []interface{}{1}},
+		{ConditionNotIn("b.a", "x"), "SELECT a FROM `b` WHERE (`b`.`a` NOT IN (?))", []interface{}{"x"}},
+		{ConditionIn("a", 1, 2, 3), "SELECT a FROM `b` WHERE (`a` IN (?,?,?))", []interface{}{1, 2, 3}},
+		{ConditionNotIn("a", []int64{4, 5}), "SELECT a FROM `b` WHERE (`a` NOT IN (?,?))", []interface{}{int64(4), int64(5)}},
+		// byte slices are a single value
+		{ConditionIn("a", []byte("xy")), "SELECT a FROM `b` WHERE (`a` IN (?))", []interface{}{[]byte("xy")}},
+		// back ticks in the column get escaped
+		{ConditionIn("id` OR 1=1 OR `x", 1), "SELECT a FROM `b` WHERE (`id`` OR 1=1 OR ``x` IN (?))", []interface{}{1}},
+	}
+	for i, test := range tests {
+		sql, args, err := s.Select("a").From("b").Where(test.cond).ToSQL()
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.wantSQL, sql, "Index %d", i)
+		assert.Exactly(t, test.wantArgs, args, "Index %d", i)
+	}
+
+	_, err := ConditionIn("a\nb", 1).newWhereFragment()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	sql, args, err := s.Select("a").From("b").Where(ConditionIn("a", 1, 2), ConditionNotIn("c", "x", "y")).ToSQL()
+	assert.NoError(t, err)
+	fullSQL, err := Preprocess(sql, args)
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE (`a` IN (1,2)) AND (`c` NOT IN ('x','y'))", fullSQL)
+}
+
+func TestSelectWhereOrSQL(t *testing.T) {
+	s := createFakeSession()
+
+	sql, args, err := s.Select("a").From("b").
+		Where(ConditionRaw("c = ?", 1)).
+		WhereOr(ConditionRaw("d = ?", 2), Eq{"e": 3}).
+		Where(ConditionIsNull("f")).
+		ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE (c = ?) AND ((d = ?) OR (`e` = ?)) AND (f IS NULL)", sql)
+	assert.Exactly(t, []interface{}{1, 2, 3}, args)
+
+	// WhereOr as first clause and a nested group
+	sql, args, err = s.Select("a").From("b").
+		WhereOr(ConditionRaw("c = ?", 1), ConditionOr(ConditionRaw("d = ?", 2), ConditionNamed("e = :e", map[string]interface{}{"e": 3}))).
+		Where(ConditionIn("g", 4, 5)).
+		ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE ((c = ?) OR ((d = ?) OR (e = ?))) AND (`g` IN (?,?))", sql)
+	assert.Exactly(t, []interface{}{1, 2, 3, 4, 5}, args)
+
+	// an empty group writes nothing
+	sql, _, err = s.Select("a").From("b").WhereOr().Where(ConditionRaw("c = 1")).ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE (c = 1)", sql)
+
+	// only empty groups write no WHERE clause
+	sql, _, err = s.Select("a").From("b").WhereOr().Where(ConditionOr(), ConditionOr(ConditionOr())).ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "SELECT a FROM `b`", sql)
+
+	// cloned fragments keep the group
+	sel := s.Select("a").From("b").WhereOr(ConditionRaw("c = 1"), ConditionRaw("d = 2"))
+	sql, _, err = sel.Count("*").ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "SELECT COUNT(*) FROM `b` WHERE ((c = 1) OR (d = 2))", sql)
+}
+
 func TestSelectBySQL(t *testing.T) {
 	s := createFakeSession()
 
@@ -576,3 +865,167 @@ func TestSelect_AddColumns(t *testing.T) {
 	assert.NoError(t, err, "%+v", err)
 	assert.Exactly(t, "SELECT a, b, c, d, e, f, x AS `u`, y AS `v` FROM `tableA` AS `tA`", sql)
 }
+
+type errValuer struct{}
+
+func (errValuer) Value() (driver.Value, error) {
+	return nil, errors.NewNotValidf("Invalid value")
+}
+
+func TestSelect_Column(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mixed with plain columns", func(t *testing.T) {
+		s := NewSelect("tableA", "tA")
+		s.AddColumns("a").
+			Column("COUNT(*)", "c").
+			AddColumnsAliases("b", "bb").
+			Column("IF(x > ?, ?, 0)", "`big`", 10, 1).
+			Column("NOW()", "").
+			Column("COALESCE(y, ?)", "y", "def").
+			Where(ConditionRaw("z = ?", 3))
+		sql, args, err := s.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT a, COUNT(*) AS `c`, b AS `bb`, IF(x > ?, ?, 0) AS `big`, NOW(), COALESCE(y, ?) AS `y` FROM `tableA` AS `tA` WHERE (z = ?)", sql)
+		assert.Exactly(t, []interface{}{10, 1, "def", 3}, args)
+	})
+
+	t.Run("driver.Valuer", func(t *testing.T) {
+		sql, args, err := NewSelect("tableA").Column("IFNULL(a, ?)", "a", myString{Present: true, Val: "x"}).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT IFNULL(a, ?) AS `a` FROM `tableA`", sql)
+		assert.Exactly(t, []interface{}{"x"}, args)
+	})
+
+	t.Run("driver.Valuer error", func(t *testing.T) {
+		sql, args, err := NewSelect("tableA").Column("IFNULL(a, ?)", "a", errValuer{}).AddColumns("b").ToSQL()
+		assert.Empty(t, sql)
+		assert.Nil(t, args)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+}
+
+func TestSelect_PaginateKeyset(t *testing.T) {
+	t.Parallel()
+
+	newSel := func() *Select {
+		return NewSelect("catalog_product_entity").AddColumns("entity_id", "sku", "updated_at").
+			Where(Eq{"type_id": "simple"})
+	}
+
+	t.Run("first page", func(t *testing.T) {
+		sql, args, err := newSel().PaginateKeyset(100, []string{"updated_at", "entity_id"}, nil).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT entity_id, sku, updated_at FROM `catalog_product_entity` WHERE (`type_id` = ?) ORDER BY `updated_at` ASC, `entity_id` ASC LIMIT 100", sql)
+		assert.Exactly(t, []interface{}{"simple"}, args)
+	})
+
+	t.Run("next page", func(t *testing.T) {
+		sql, args, err := newSel().PaginateKeyset(100, []string{"updated_at", "entity_id"}, map[string]interface{}{
+			"entity_id":  int64(33),
+			"updated_at": "2016-10-14 13:14:15",
+			"ignored":    1,
+		}).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT entity_id, sku, updated_at FROM `catalog_product_entity` WHERE (`type_id` = ?) AND ((`updated_at` > ?) OR (`updated_at` = ? AND `entity_id` > ?)) ORDER BY `updated_at` ASC, `entity_id` ASC LIMIT 100", sql)
+		assert.Exactly(t, []interface{}{"simple", "2016-10-14 13:14:15", "2016-10-14 13:14:15", int64(33)}, args)
+	})
+
+	t.Run("single column with table prefix", func(t *testing.T) {
+		sql, args, err := NewSelect("tableA", "tA").AddColumns("tA.id").
+			PaginateKeyset(5, []string{"tA.id"}, map[string]interface{}{"tA.id": 44}).ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT tA.id FROM `tableA` AS `tA` WHERE ((`tA`.`id` > ?)) ORDER BY `tA`.`id` ASC LIMIT 5", sql)
+		assert.Exactly(t, []interface{}{44}, args)
+	})
+
+	t.Run("missing key column", func(t *testing.T) {
+		sql, args, err := newSel().PaginateKeyset(100, []string{"updated_at", "entity_id"}, map[string]interface{}{
+			"entity_id": int64(33),
+		}).ToSQL()
+		assert.Empty(t, sql)
+		assert.Nil(t, args)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+
+	t.Run("empty key columns", func(t *testing.T) {
+		_, _, err := newSel().PaginateKeyset(100, nil, nil).ToSQL()
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+}
+
+func TestSelect_Count(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deep copy", func(t *testing.T) {
+		sel := NewSelect("tableA", "tA").AddColumns("a", "b").
+			Join(JoinTable("tableB", "tB"), JoinColumns("tB.c"), ConditionRaw("tA.id = tB.id AND tB.d = ?", 3)).
+			Where(Eq{"e": 4}, ConditionRaw("f > ?", 5)).
+			OrderBy("a").Limit(10).Offset(20)
+
+		cnt := sel.Count("*")
+
+		// mutate the original after cloning
+		sel.Where(ConditionRaw("g = ?", 6))
+		sel.WhereFragments[0].EqualityMap["h"] = 7
+		sel.WhereFragments[1].Values[0] = 55
+		sel.JoinFragments[0].OnConditions[0].Values[0] = 33
+
+		sql, args, err := cnt.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT COUNT(*) FROM `tableA` AS `tA` INNER JOIN `tableB` AS `tB` ON (tA.id = tB.id AND tB.d = ?) WHERE (`e` = ?) AND (f > ?)", sql)
+		assert.Exactly(t, []interface{}{3, 4, 5}, args)
+	})
+
+	t.Run("distinct", func(t *testing.T) {
+		sql, _, err := NewSelect("tableA").AddColumns("a").Distinct().Count("a").ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT COUNT(DISTINCT a) FROM `tableA`", sql)
+	})
+
+	t.Run("distinct rows", func(t *testing.T) {
+		sel := NewSelect("tableA").AddColumns("a", "b").Distinct().
+			Where(Eq{"c": 3}).OrderBy("a").Limit(10)
+		cnt := sel.Count("*")
+		sel.Where(ConditionRaw("d = ?", 4))
+
+		sql, args, err := cnt.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "SELECT COUNT(*) FROM (SELECT DISTINCT a, b FROM `tableA` WHERE (`c` = ?)) AS t", sql)
+		assert.Exactly(t, []interface{}{3}, args)
+	})
+
+	t.Run("distinct rows error", func(t *testing.T) {
+		sql, _, err := NewSelect("tableA").Distinct().Count("*").ToSQL()
+		assert.Empty(t, sql)
+		assert.True(t, errors.IsEmpty(err), "%+v", err)
+	})
+
+	t.Run("raw SQL", func(t *testing.T) {
+		sel := &Select{RawFullSQL: "SELECT a FROM b"}
+		sql, _, err := sel.Count("*").ToSQL()
+		assert.Empty(t, sql)
+		assert.True(t, errors.IsNotSupported(err), "%+v", err)
+	})
+}
+
+func TestSelect_Lock(t *testing.T) {
+	s := createFakeSession()
+
+	tests := []struct {
+		mode    LockMode
+		wantSQL string
+	}{
+		{LockNone, "SELECT qty FROM `cataloginventory_stock_item` WHERE (`product_id` = ?) ORDER BY item_id LIMIT 1 OFFSET 2"},
+		{LockForUpdate, "SELECT qty FROM `cataloginventory_stock_item` WHERE (`product_id` = ?) ORDER BY item_id LIMIT 1 OFFSET 2 FOR UPDATE"},
+		{LockInShareMode, "SELECT qty FROM `cataloginventory_stock_item` WHERE (`product_id` = ?) ORDER BY item_id LIMIT 1 OFFSET 2 LOCK IN SHARE MODE"},
+	}
+	for i, test := range tests {
+		sql, args, err := s.Select("qty").From("cataloginventory_stock_item").
+			Where(Eq{"product_id": 4711}).OrderBy("item_id").Limit(1).Offset(2).
+			Lock(test.mode).ToSQL()
+		assert.NoError(t, err, "Index %d => %+v", i, err)
+		assert.Exactly(t, test.wantSQL, sql, "Index %d", i)
+		assert.Exactly(t, []interface{}{4711}, args, "Index %d", i)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/corestoreio/errors"
+)
+
+// CachedStmt is a prepared statement returned by Connection.PrepareCached.
+// Close hands the statement back to the cache of the connection instead of
+// closing it. Without a cache Close closes the statement. Calling Close more
+// than once has no further effect.
+type CachedStmt struct {
+	*sql.Stmt
+	cache *stmtCache
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Close releases the statement. The underlying sql.Stmt gets closed once it
+// has been evicted from the cache and all users have released it.
+func (cs *CachedStmt) Close() error {
+	cs.closeOnce.Do(func() {
+		if cs.cache == nil {
+			cs.closeErr = errors.Wrap(cs.Stmt.Close(), "[dbr] CachedStmt.Close")
+			return
+		}
+		cs.closeErr = cs.cache.release(cs.Stmt)
+	})
+	return cs.closeErr
+}
+
+// stmtCache caches prepared statements keyed by their SQL string. The number
+// of cached statements is bounded and the least recently used statement gets
+// evicted once the limit has been reached. Each handed out statement counts
+// as a reference which must be given back with release. An evicted statement
+// gets closed once all references have been released, so evicting never
+// closes a statement still in use by another goroutine. stmtCache is safe for
+// concurrent use.
+type stmtCache struct {
+	size int
+
+	mu sync.Mutex
+	// lru contains in its front the most recently used statement.
+	lru   *list.List
+	items map[string]*list.Element
+	// refs contains all cached and all evicted but not yet released
+	// statements.
+	refs map[*sql.Stmt]*stmtCacheItem
+}
+
+type stmtCacheItem struct {
+	query string
+	stmt  *sql.Stmt
+	// users number of unreleased references to stmt.
+	users int
+	// evicted gets set once stmt has been removed from the LRU list.
+	evicted bool
+}
+
+func newStmtCache(size int) *stmtCache {
+	return &stmtCache{
+		size:  size,
+		lru:   list.New(),
+		items: make(map[string]*list.Element, size),
+		refs:  make(map[*sql.Stmt]*stmtCacheItem, size),
+	}
+}
+
+// prepareContext returns a cached statement for query or prepares a new one
+// with db and adds it to the cache. The statement must be handed back with
+// release and not closed. A nil context falls back to the non-context aware
+// Prepare function of db.
+func (sc *stmtCache) prepareContext(ctx context.Context, db Preparer, query string) (*sql.Stmt, error) {
+	if stmt, ok := sc.get(query); ok {
+		return stmt, nil
+	}
+
+	// Prepare outside of the lock to avoid blocking other queries while talking
+	// to the database.
+	stmt, err := prepareContext(ctx, db, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "[dbr] stmtCache.Prepare")
+	}
+	return sc.add(query, stmt), nil
+}
+
+func (sc *stmtCache) get(query string) (*sql.Stmt, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if e, ok := sc.items[query]; ok {
+		sc.lru.MoveToFront(e)
+		item := e.Value.(*stmtCacheItem)
+		item.users++
+		return item.stmt, true
+	}
+	return nil, false
+}
+
+// add inserts the statement into the cache and returns the cached statement.
+// If another goroutine has been faster in preparing the same query, stmt gets
+// closed and the already cached statement returned.
+func (sc *stmtCache) add(query string, stmt *sql.Stmt) *sql.Stmt {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if e, ok := sc.items[query]; ok {
+		sc.lru.MoveToFront(e)
+		_ = stmt.Close()
+		item := e.Value.(*stmtCacheItem)
+		item.users++
+		return item.stmt
+	}
+
+	item := &stmtCacheItem{query: query, stmt: stmt, users: 1}
+	sc.items[query] = sc.lru.PushFront(item)
+	sc.refs[stmt] = item
+	for sc.lru.Len() > sc.size {
+		oldest := sc.lru.Back()
+		sc.lru.Remove(oldest)
+		item := oldest.Value.(*stmtCacheItem)
+		delete(sc.items, item.query)
+		item.evicted = true
+		if item.users == 0 {
+			delete(sc.refs, item.stmt)
+			_ = item.stmt.Close()
+		}
+	}
+	return stmt
+}
+
+// release gives back a statement returned by prepareContext. An evicted statement
+// gets closed once its last reference has been released. A statement which
+// has not been returned by the cache gets closed.
+func (sc *stmtCache) release(stmt *sql.Stmt) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	item, ok := sc.refs[stmt]
+	if !ok {
+		return errors.Wrap(stmt.Close(), "[dbr] stmtCache.release")
+	}
+	if item.users > 0 {
+		item.users--
+	}
+	if item.users > 0 || !item.evicted {
+		return nil
+	}
+	delete(sc.refs, stmt)
+	return errors.Wrap(stmt.Close(), "[dbr] stmtCache.release")
+}
+
+// Len returns the number of cached statements.
+func (sc *stmtCache) Len() int {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.lru.Len()
+}
+
+// Close closes all cached and all evicted but not yet released statements and
+// empties the cache. The first error gets returned but all statements will be
+// closed.
+func (sc *stmtCache) Close() error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	var firstErr error
+	for stmt := range sc.refs {
+		if err := stmt.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	sc.lru.Init()
+	sc.items = make(map[string]*list.Element, sc.size)
+	sc.refs = make(map[*sql.Stmt]*stmtCacheItem, sc.size)
+	return errors.Wrap(firstErr, "[dbr] stmtCache.Close")
+}
@@ -0,0 +1,289 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStmtCacheConnection(t testing.TB, size int) (*dbr.Connection, sqlmock.Sqlmock) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	dbc, err := dbr.NewConnection(dbr.WithDB(db), dbr.WithStmtCache(size))
+	require.NoError(t, err, "%+v", err)
+	return dbc, sqlMock
+}
+
+func TestWithStmtCache(t *testing.T) {
+
+	const selectA = "SELECT a FROM `tableA`"
+	const selectB = "SELECT b FROM `tableA`"
+	const selectC = "SELECT c FROM `tableA`"
+
+	t.Run("invalid size", func(t *testing.T) {
+		dbc, err := dbr.NewConnection(dbr.WithStmtCache(0))
+		assert.Nil(t, dbc)
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+
+	t.Run("returns cached statement", func(t *testing.T) {
+		dbc, sqlMock := newStmtCacheConnection(t, 2)
+		defer func() {
+			sqlMock.ExpectClose()
+			assert.NoError(t, dbc.Close())
+			if err := sqlMock.ExpectationsWereMet(); err != nil {
+				t.Error("there were unfulfilled expections", err)
+			}
+		}()
+
+		sqlMock.ExpectPrepare("SELECT a FROM `tableA` WHERE \\(b = \\?\\)")
+		sqlMock.ExpectPrepare("DELETE FROM `tableA` WHERE \\(b = \\?\\)")
+
+		sqlStr, _, err := dbc.NewSession().Select("a").From("tableA").Where(dbr.ConditionRaw("b = ?")).ToSQL()
+		require.NoError(t, err, "%+v", err)
+		stmt1, err := dbc.PrepareCached(sqlStr)
+		require.NoError(t, err, "%+v", err)
+		stmt2, err := dbc.PrepareCached(sqlStr)
+		require.NoError(t, err, "%+v", err)
+		assert.True(t, stmt1.Stmt == stmt2.Stmt, "Statements should be the same pointer")
+
+		stmt3, err := dbc.PrepareCached("DELETE FROM `tableA` WHERE (b = ?)")
+		require.NoError(t, err, "%+v", err)
+		assert.False(t, stmt1.Stmt == stmt3.Stmt, "Statements should differ")
+
+		// closing hands the statement back but keeps it open
+		assert.NoError(t, stmt1.Close())
+		assert.NoError(t, stmt1.Close())
+		assert.NoError(t, stmt2.Close())
+		stmt4, err := dbc.PrepareCached(sqlStr)
+		require.NoError(t, err, "%+v", err)
+		assert.True(t, stmt1.Stmt == stmt4.Stmt, "Statements should be the same pointer")
+	})
+
+	t.Run("builder Prepare does not use the cache", func(t *testing.T) {
+		dbc, sqlMock := newStmtCacheConnection(t, 2)
+		defer func() {
+			sqlMock.ExpectClose()
+			assert.NoError(t, dbc.Close())
+			if err := sqlMock.ExpectationsWereMet(); err != nil {
+				t.Error("there were unfulfilled expections", err)
+			}
+		}()
+
+		sqlMock.ExpectPrepare(selectA)
+		sqlMock.ExpectPrepare(selectA)
+
+		stmt, err := dbc.PrepareCached(selectA)
+		require.NoError(t, err, "%+v", err)
+		stmt2, err := dbc.NewSession().Select("a").From("tableA").Prepare()
+		require.NoError(t, err, "%+v", err)
+		assert.False(t, stmt.Stmt == stmt2, "Statements should differ")
+		assert.NoError(t, stmt2.Close())
+		assert.NoError(t, stmt.Close())
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		dbc, sqlMock := newStmtCacheConnection(t, 2)
+		defer func() {
+			sqlMock.ExpectClose()
+			assert.NoError(t, dbc.Close())
+			if err := sqlMock.ExpectationsWereMet(); err != nil {
+				t.Error("there were unfulfilled expections", err)
+			}
+		}()
+
+		sqlMock.ExpectPrepare(selectA)
+		sqlMock.ExpectPrepare(selectB)
+		sqlMock.ExpectPrepare(selectC)
+		sqlMock.ExpectPrepare(selectB)
+
+		sqlMock.ExpectExec(selectA).WillReturnResult(sqlmock.NewResult(0, 0))
+		sqlMock.ExpectExec(selectA).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		stmtA, err := dbc.PrepareCached(selectA)
+		require.NoError(t, err, "%+v", err)
+		stmtB, err := dbc.PrepareCached(selectB)
+		require.NoError(t, err, "%+v", err)
+		assert.NoError(t, stmtB.Close())
+		// touch a, so b becomes the least recently used statement
+		stmtA2, err := dbc.PrepareCached(selectA)
+		require.NoError(t, err, "%+v", err)
+		assert.True(t, stmtA.Stmt == stmtA2.Stmt, "Statements should be the same pointer")
+
+		_, err = dbc.PrepareCached(selectC) // evicts and closes the released b
+		require.NoError(t, err, "%+v", err)
+		_, err = stmtB.Exec()
+		assert.EqualError(t, err, "sql: statement is closed")
+
+		_, err = dbc.PrepareCached(selectB) // evicts a which is still in use
+		require.NoError(t, err, "%+v", err)
+		_, err = stmtA.Exec()
+		assert.NoError(t, err, "%+v", err)
+
+		assert.NoError(t, stmtA.Close())
+		assert.NoError(t, stmtA.Close()) // must not release the reference of stmtA2
+		_, err = stmtA2.Exec()
+		assert.NoError(t, err, "%+v", err)
+		assert.NoError(t, stmtA2.Close()) // last user closes a
+		_, err = stmtA.Exec()
+		assert.EqualError(t, err, "sql: statement is closed")
+	})
+
+	t.Run("without cache close closes", func(t *testing.T) {
+		db, sqlMock, err := sqlmock.New()
+		require.NoError(t, err)
+		dbc, err := dbr.NewConnection(dbr.WithDB(db))
+		require.NoError(t, err, "%+v", err)
+		defer func() {
+			sqlMock.ExpectClose()
+			assert.NoError(t, dbc.Close())
+		}()
+
+		sqlMock.ExpectPrepare(selectA)
+		sqlMock.ExpectPrepare(selectA)
+		stmt, err := dbc.PrepareCached(selectA)
+		require.NoError(t, err, "%+v", err)
+		stmt2, err := dbc.PrepareCached(selectA)
+		require.NoError(t, err, "%+v", err)
+		assert.False(t, stmt.Stmt == stmt2.Stmt, "Statements should differ")
+		assert.NoError(t, stmt.Close())
+		_, err = stmt.Exec()
+		assert.EqualError(t, err, "sql: statement is closed")
+		assert.NoError(t, stmt2.Close())
+	})
+
+	t.Run("option applied after NewConnection", func(t *testing.T) {
+		db, sqlMock, err := sqlmock.New()
+		require.NoError(t, err)
+		dbc, err := dbr.NewConnection(dbr.WithDB(db))
+		require.NoError(t, err, "%+v", err)
+		require.NoError(t, dbc.Options(dbr.WithStmtCache(2)))
+		defer func() {
+			sqlMock.ExpectClose()
+			assert.NoError(t, dbc.Close())
+			if err := sqlMock.ExpectationsWereMet(); err != nil {
+				t.Error("there were unfulfilled expections", err)
+			}
+		}()
+
+		sqlMock.ExpectPrepare(selectA)
+		stmt, err := dbc.PrepareCached(selectA)
+		require.NoError(t, err, "%+v", err)
+		stmt2, err := dbc.PrepareCached(selectA)
+		require.NoError(t, err, "%+v", err)
+		assert.True(t, stmt.Stmt == stmt2.Stmt, "Statements should be the same pointer")
+	})
+
+	t.Run("close closes all statements", func(t *testing.T) {
+		dbc, sqlMock := newStmtCacheConnection(t, 5)
+
+		sqlMock.ExpectPrepare("UPDATE `tableA` SET `a` = \\?")
+		stmt, err := dbc.PrepareCached("UPDATE `tableA` SET `a` = ?")
+		require.NoError(t, err, "%+v", err)
+
+		sqlMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+
+		_, err = stmt.Exec(1)
+		assert.EqualError(t, err, "sql: statement is closed")
+		assert.NoError(t, stmt.Close())
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		dbc, sqlMock := newStmtCacheConnection(t, 1)
+		// Depending on the scheduling not all prepare expectations get used,
+		// hence the error of Close gets ignored.
+		defer func() { _ = dbc.Close() }()
+
+		const goroutines = 10
+		for i := 0; i < goroutines; i++ {
+			sqlMock.ExpectPrepare(selectA)
+		}
+
+		var wg sync.WaitGroup
+		stmts := make([]*dbr.CachedStmt, goroutines)
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				stmt, err := dbc.PrepareCached(selectA)
+				assert.NoError(t, err, "%+v", err)
+				stmts[i] = stmt
+			}(i)
+		}
+		wg.Wait()
+		for i := 1; i < goroutines; i++ {
+			assert.True(t, stmts[0].Stmt == stmts[i].Stmt, "Statement %d should be the same pointer", i)
+		}
+		for _, stmt := range stmts {
+			assert.NoError(t, stmt.Close())
+		}
+	})
+}
+
+// BenchmarkStmtCache_Prepare compares the round trips of statement
+// preparation with and without the statement cache. Without the cache each
+// iteration has to prepare the statement in the database.
+func BenchmarkStmtCache_Prepare(b *testing.B) {
+
+	bench := func(b *testing.B, withCache bool) {
+		db, sqlMock, err := sqlmock.New()
+		require.NoError(b, err)
+		opts := []dbr.ConnectionOption{dbr.WithDB(db)}
+		if withCache {
+			opts = append(opts, dbr.WithStmtCache(10))
+		}
+		dbc, err := dbr.NewConnection(opts...)
+		require.NoError(b, err, "%+v", err)
+		defer func() { _ = dbc.Close() }()
+		// Each round trip to the database requires one prepare expectation.
+		roundTrips := b.N
+		if withCache {
+			roundTrips = 1
+		}
+		for i := 0; i < roundTrips; i++ {
+			sqlMock.ExpectPrepare("SELECT a, b FROM `tableA` WHERE \\(c = \\?\\)")
+		}
+		sqlStr, _, err := dbc.NewSession().Select("a", "b").From("tableA").Where(dbr.ConditionRaw("c = ?")).ToSQL()
+		require.NoError(b, err, "%+v", err)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			stmt, err := dbc.PrepareCached(sqlStr)
+			if err != nil {
+				b.Fatalf("%+v", err)
+			}
+			if err := stmt.Close(); err != nil {
+				b.Fatalf("%+v", err)
+			}
+		}
+	}
+
+	b.Run("NoCache", func(b *testing.B) {
+		bench(b, false)
+	})
+	b.Run("Cache", func(b *testing.B) {
+		bench(b, true)
+	})
+}
@@ -14,7 +14,13 @@ type fieldMapQueueElement struct {
 	Idxs []int
 }
 
-// recordType is the type of a structure
+// calculateFieldMap maps each column to the index sequence of a field in
+// recordType, which is the type of a structure. The column name of a field is
+// its db tag or the name of the field converted to snake case. The fields of
+// exported named struct fields and of anonymous embedded structs get
+// included, except pointers and fields tagged with "-". The search goes
+// breadth first, so when a column matches fields at different depths, the
+// outermost field wins. At the same depth the first declared field wins.
 func calculateFieldMap(recordType reflect.Type, columns []string, requireAllColumns bool) ([][]int, error) {
 
 	// each value is either the slice to get to the field via FieldByIndex(index
@@ -39,28 +45,34 @@ func calculateFieldMap(recordType reflect.Type, columns []string, requireAllColu
 			for j := 0; j < lenFields; j++ {
 				fieldStruct := curType.Field(j)
 
-				// Skip unexported field
-				if len(fieldStruct.PkgPath) != 0 {
-					continue
-				}
-
 				name := fieldStruct.Tag.Get("db")
-				if name != "-" {
-					if name == "" {
-						name = util.CamelCaseToUnderscore(fieldStruct.Name)
-					}
-					if name == col {
-						fieldMap[i] = append(curIdxs, j)
-						break QueueLoop
-					}
+				if name == "-" {
+					continue
 				}
 
-				if fieldStruct.Type.Kind() == reflect.Struct {
-					var idxs2 []int
+				// The exported fields of a struct field get searched in the
+				// next depth. Anonymous embedded structs get searched even if
+				// the struct type itself is unexported.
+				exported := len(fieldStruct.PkgPath) == 0
+				if (exported || fieldStruct.Anonymous) && fieldStruct.Type.Kind() == reflect.Struct {
+					idxs2 := make([]int, len(curIdxs), len(curIdxs)+1)
 					copy(idxs2, curIdxs)
 					idxs2 = append(idxs2, j)
 					queue = append(queue, fieldMapQueueElement{Type: fieldStruct.Type, Idxs: idxs2})
 				}
+
+				// Skip unexported field
+				if !exported {
+					continue
+				}
+
+				if name == "" {
+					name = util.CamelCaseToUnderscore(fieldStruct.Name)
+				}
+				if name == col {
+					fieldMap[i] = append(curIdxs[:len(curIdxs):len(curIdxs)], j)
+					break QueueLoop
+				}
 			}
 		}
 
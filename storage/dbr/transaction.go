@@ -1,6 +1,7 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/corestoreio/errors"
@@ -11,19 +12,68 @@ import (
 type Tx struct {
 	log.Logger
 	*sql.Tx
+	// Dialect gets passed to all builders created by the transaction.
+	Dialect Dialect
+	// Listeners get merged into the listeners of all builders created by the
+	// transaction.
+	Listeners ListenerBucket
 }
 
 // Begin creates a transaction for the given session
 func (sess *Session) Begin() (*Tx, error) {
-	tx, err := sess.cxn.DB.Begin()
+	return sess.BeginContext(nil)
+}
+
+// BeginContext same as Begin but the transaction gets bound to the context. If
+// the context gets canceled the transaction will be rolled back. A nil
+// context falls back to the non-context aware Begin function of the database.
+func (sess *Session) BeginContext(ctx context.Context) (*Tx, error) {
+	var tx *sql.Tx
+	var err error
+	if ctx == nil {
+		tx, err = sess.cxn.DB.Begin()
+	} else {
+		tx, err = sess.cxn.DB.BeginTx(ctx, nil)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "[dbr] transaction.begin.error")
 	}
 
-	return &Tx{
-		Logger: sess.Logger,
-		Tx:     tx,
-	}, nil
+	t := &Tx{
+		Logger:  sess.Logger,
+		Tx:      tx,
+		Dialect: sess.cxn.Dialect,
+	}
+	t.Listeners.Merge(&sess.cxn.Listeners)
+	return t, nil
+}
+
+// Transaction begins a transaction bound to the context and runs fn. A nil
+// error returned by fn commits the transaction. A non-nil error or a panic in
+// fn rolls back the transaction. A failing rollback gets reported within the
+// message of the returned error but the cause stays the error of fn. A panic
+// gets re-thrown after the rollback to preserve the stack trace. A nil
+// context falls back to the non-context aware Begin function of the database.
+func (sess *Session) Transaction(ctx context.Context, fn func(tx *Tx) error) error {
+	tx, err := sess.BeginContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "[dbr] Session.Transaction.Begin")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rErr := tx.Tx.Rollback(); rErr != nil {
+			return errors.Wrapf(err, "[dbr] Session.Transaction: Rollback failed with %q after", rErr)
+		}
+		return errors.Wrap(err, "[dbr] Session.Transaction")
+	}
+	return errors.Wrap(tx.Commit(), "[dbr] Session.Transaction")
 }
 
 // Commit finishes the transaction
@@ -36,6 +86,34 @@ func (tx *Tx) Rollback() error {
 	return errors.Wrap(tx.Tx.Rollback(), "[dbr] transaction.rollback.error")
 }
 
+// Savepoint sets a named transaction savepoint. An existing savepoint with the
+// same name gets replaced. The name must be a valid identifier otherwise a
+// NotValid error gets returned.
+func (tx *Tx) Savepoint(name string) error {
+	return errors.Wrap(tx.execSavepoint("SAVEPOINT ", name), "[dbr] Tx.Savepoint")
+}
+
+// RollbackTo rolls back the transaction to the named savepoint without
+// terminating the transaction. Modifications made after the savepoint have
+// been set get undone.
+func (tx *Tx) RollbackTo(name string) error {
+	return errors.Wrap(tx.execSavepoint("ROLLBACK TO SAVEPOINT ", name), "[dbr] Tx.RollbackTo")
+}
+
+// ReleaseSavepoint removes the named savepoint from the set of savepoints of
+// the transaction. No commit or rollback occurs.
+func (tx *Tx) ReleaseSavepoint(name string) error {
+	return errors.Wrap(tx.execSavepoint("RELEASE SAVEPOINT ", name), "[dbr] Tx.ReleaseSavepoint")
+}
+
+func (tx *Tx) execSavepoint(stmt, name string) error {
+	if err := isValidIdentifier(name); err != nil {
+		return errors.Wrap(err, "[dbr] Savepoint name")
+	}
+	_, err := tx.Tx.Exec(stmt + Quoter.QuoteAs(name))
+	return err
+}
+
 // RollbackUnlessCommitted rolls back the transaction unless it has already been
 // committed or rolled back. Useful to defer tx.RollbackUnlessCommitted() -- so
 // you don't have to handle N failure cases Keep in mind the only way to detect
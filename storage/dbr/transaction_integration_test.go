@@ -0,0 +1,175 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbr_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession_Transaction(t *testing.T) {
+
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+	sess := dbc.NewSession()
+
+	t.Run("commit", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec("UPDATE `tableA` SET `a` = 1").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		err := sess.Transaction(context.Background(), func(tx *dbr.Tx) error {
+			_, err := tx.Update("tableA").Set("a", 1).Exec()
+			return err
+		})
+		assert.NoError(t, err, "%+v", err)
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec("UPDATE `tableA` SET `a` = 2").WillReturnError(errors.NewAlreadyExistsf("Duplicate entry"))
+		dbMock.ExpectRollback()
+
+		err := sess.Transaction(context.Background(), func(tx *dbr.Tx) error {
+			_, err := tx.Update("tableA").Set("a", 2).Exec()
+			return err
+		})
+		assert.True(t, errors.IsAlreadyExists(err), "%+v", err)
+	})
+
+	t.Run("rollback fails", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectRollback().WillReturnError(errors.New("connection lost"))
+
+		err := sess.Transaction(nil, func(tx *dbr.Tx) error {
+			return errors.NewNotValidf("Invalid data")
+		})
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+		assert.Contains(t, err.Error(), "connection lost")
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectRollback()
+
+		defer func() {
+			r := recover()
+			assert.Exactly(t, "Oops", r)
+		}()
+		_ = sess.Transaction(context.Background(), func(tx *dbr.Tx) error {
+			panic("Oops")
+		})
+		t.Fatal("Expected a panic")
+	})
+
+	t.Run("begin fails", func(t *testing.T) {
+		dbMock.ExpectBegin().WillReturnError(errors.NewAlreadyClosedf("Who closed myself?"))
+
+		var called bool
+		err := sess.Transaction(context.Background(), func(tx *dbr.Tx) error {
+			called = true
+			return nil
+		})
+		assert.True(t, errors.IsAlreadyClosed(err), "%+v", err)
+		assert.False(t, called, "Callback should not be called")
+	})
+}
+
+func TestTx_Savepoint(t *testing.T) {
+
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec("SAVEPOINT `import_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectExec("ROLLBACK TO SAVEPOINT `import_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectExec("RELEASE SAVEPOINT `import_1`").WillReturnError(errors.NewNotFoundf("SAVEPOINT import_1 does not exist"))
+	dbMock.ExpectRollback()
+
+	tx, err := dbc.NewSession().Begin()
+	assert.NoError(t, err, "%+v", err)
+
+	assert.NoError(t, tx.Savepoint("import_1"))
+	assert.NoError(t, tx.RollbackTo("import_1"))
+	err = tx.ReleaseSavepoint("import_1")
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+
+	for _, name := range []string{"", "imp`ort", "import 1", "import;DROP TABLE x", "a123456789012345678901234567890123456789012345678901234567890123"} {
+		assert.True(t, errors.IsNotValid(tx.Savepoint(name)), "Savepoint %q", name)
+		assert.True(t, errors.IsNotValid(tx.RollbackTo(name)), "RollbackTo %q", name)
+		assert.True(t, errors.IsNotValid(tx.ReleaseSavepoint(name)), "ReleaseSavepoint %q", name)
+	}
+
+	assert.NoError(t, tx.Rollback())
+}
+
+// TestTx_Savepoint_Integration runs only if a DSN has been provided via the
+// environment variable CS_DSN.
+func TestTx_Savepoint_Integration(t *testing.T) {
+	if os.Getenv(cstesting.EnvDSN) == "" {
+		t.Skipf("Environment variable %q not set", cstesting.EnvDSN)
+	}
+
+	dbc := dbr.MustConnectAndVerify(dbr.WithDSN(cstesting.MustGetDSN()))
+	defer func() { assert.NoError(t, dbc.Close()) }()
+
+	tx, err := dbc.NewSession().Begin()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	_, err = tx.Exec("CREATE TEMPORARY TABLE `dbr_savepoint_test` (`id` INT NOT NULL PRIMARY KEY)")
+	assert.NoError(t, err)
+
+	_, err = tx.InsertInto("dbr_savepoint_test").Columns("id").Values(1).Exec()
+	assert.NoError(t, err, "%+v", err)
+
+	assert.NoError(t, tx.Savepoint("sp1"))
+
+	_, err = tx.InsertInto("dbr_savepoint_test").Columns("id").Values(2).Values(3).Exec()
+	assert.NoError(t, err, "%+v", err)
+
+	assert.NoError(t, tx.RollbackTo("sp1"))
+	assert.NoError(t, tx.ReleaseSavepoint("sp1"))
+
+	var ids []int64
+	_, err = tx.Select("id").From("dbr_savepoint_test").LoadInt64s(&ids)
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, []int64{1}, ids)
+
+	_, err = tx.Exec("DROP TEMPORARY TABLE `dbr_savepoint_test`")
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+}
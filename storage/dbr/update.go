@@ -1,9 +1,10 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
-	"strconv"
+	"time"
 
 	"github.com/corestoreio/csfw/util/bufferpool"
 	"github.com/corestoreio/errors"
@@ -27,6 +28,9 @@ type Update struct {
 		Preparer
 		Execer
 	}
+	// Dialect writes the placeholders, quoted identifiers and LIMIT of the
+	// generated SQL. Nil defaults to MySQL.
+	Dialect Dialect
 
 	RawFullSQL   string
 	RawArguments []interface{}
@@ -43,6 +47,13 @@ type Update struct {
 	// Listeners allows to dispatch certain functions in different
 	// situations.
 	Listeners UpdateListeners
+	// Stats contains the statistics of the last execution. They get set
+	// before the OnAfterExec listeners get dispatched.
+	Stats QueryStats
+	// lastSQL and lastArgs contain the statement and its arguments of the
+	// last call to ToSQL. They get copied into Stats after the execution.
+	lastSQL  string
+	lastArgs []interface{}
 	// PropagationStopped set to true if you would like to interrupt the
 	// listener chain. Once set to true all sub sequent calls of the next
 	// listeners will be suppressed.
@@ -71,10 +82,13 @@ type setClause struct {
 // Update creates a new Update for the given table
 func (sess *Session) Update(table ...string) *Update {
 	u := &Update{
-		Log:   sess.Logger,
-		Table: MakeAlias(table...),
+		Log:     sess.Logger,
+		Dialect: sess.cxn.Dialect,
+		Table:   MakeAlias(table...),
 	}
-	u.DB.Execer = sess.cxn.DB
+	u.DB.Execer = sess.cxn.execer()
+	u.DB.Preparer = sess.cxn.DB
+	u.Listeners.Merge(sess.cxn.Listeners.Update)
 	return u
 }
 
@@ -87,20 +101,26 @@ func (sess *Session) UpdateBySQL(sql string, args ...interface{}) *Update {
 	}
 	u := &Update{
 		Log:          sess.Logger,
+		Dialect:      sess.cxn.Dialect,
 		RawFullSQL:   sql,
 		RawArguments: args,
 	}
-	u.DB.Execer = sess.cxn.DB
+	u.DB.Execer = sess.cxn.execer()
+	u.DB.Preparer = sess.cxn.DB
+	u.Listeners.Merge(sess.cxn.Listeners.Update)
 	return u
 }
 
 // Update creates a new Update for the given table bound to a transaction
 func (tx *Tx) Update(table ...string) *Update {
 	u := &Update{
-		Log:   tx.Logger,
-		Table: MakeAlias(table...),
+		Log:     tx.Logger,
+		Dialect: tx.Dialect,
+		Table:   MakeAlias(table...),
 	}
 	u.DB.Execer = tx.Tx
+	u.DB.Preparer = tx.Tx
+	u.Listeners.Merge(tx.Listeners.Update)
 	return u
 }
 
@@ -113,10 +133,13 @@ func (tx *Tx) UpdateBySQL(sql string, args ...interface{}) *Update {
 	}
 	u := &Update{
 		Log:          tx.Logger,
+		Dialect:      tx.Dialect,
 		RawFullSQL:   sql,
 		RawArguments: args,
 	}
 	u.DB.Execer = tx.Tx
+	u.DB.Preparer = tx.Tx
+	u.Listeners.Merge(tx.Listeners.Update)
 	return u
 }
 
@@ -138,6 +161,23 @@ func (b *Update) Set(column string, value interface{}) *Update {
 	return b
 }
 
+// SetExpr appends a column/expression pair for the statement. The expression
+// gets written as is into the SET clause without a placeholder, e.g.:
+// SetExpr("qty", "qty - ?", 3) renders `qty` = qty - ?. The optional arguments
+// are getting appended to the arguments list in the order of the SET clauses.
+func (b *Update) SetExpr(column, expression string, args ...interface{}) *Update {
+	if b.previousError != nil {
+		return b
+	}
+	if err := argsValuer(&args); err != nil {
+		return &Update{
+			previousError: errors.Wrapf(err, "[dbr] Update.SetExpr %q Args: %v", column, args),
+		}
+	}
+	b.SetClauses = append(b.SetClauses, &setClause{column: column, value: Expr(expression, args...)})
+	return b
+}
+
 // SetMap appends the elements of the map as column/value pairs for the statement
 func (b *Update) SetMap(clauses map[string]interface{}) *Update {
 	for col, val := range clauses {
@@ -152,6 +192,12 @@ func (b *Update) Where(args ...ConditionArg) *Update {
 	return b
 }
 
+// WhereOr groups the conditions with OR and appends the group as one WHERE
+// clause, joined with AND to the other clauses. See ConditionOr.
+func (b *Update) WhereOr(args ...ConditionArg) *Update {
+	return b.Where(ConditionOr(args...))
+}
+
 // OrderBy appends a column to ORDER the statement by
 func (b *Update) OrderBy(ord string) *Update {
 	b.OrderBys = append(b.OrderBys, ord)
@@ -175,7 +221,9 @@ func (b *Update) Limit(limit uint64) *Update {
 	return b
 }
 
-// Offset sets an offset for the statement; overrides any existing OFFSET
+// Offset sets an offset for the statement; overrides any existing OFFSET.
+// MySQL does not support an OFFSET in an UPDATE statement, hence ToSQL returns
+// a NotValid error.
 func (b *Update) Offset(offset uint64) *Update {
 	b.OffsetCount = offset
 	b.OffsetValid = true
@@ -185,6 +233,16 @@ func (b *Update) Offset(offset uint64) *Update {
 // ToSQL serialized the Update to a SQL string
 // It returns the string with placeholders and a slice of query arguments
 func (b *Update) ToSQL() (string, []interface{}, error) {
+	sql, args, err := b.toSQL()
+	if err != nil {
+		b.lastSQL, b.lastArgs = "", nil
+		return "", nil, err
+	}
+	b.lastSQL, b.lastArgs = sql, args
+	return sql, args, nil
+}
+
+func (b *Update) toSQL() (string, []interface{}, error) {
 	if b.previousError != nil {
 		return "", nil, errors.Wrap(b.previousError, "[dbr] Update.ToSQL")
 	}
@@ -203,14 +261,20 @@ func (b *Update) ToSQL() (string, []interface{}, error) {
 	if len(b.SetClauses) == 0 {
 		return "", nil, errors.NewEmptyf("[dbr] Update: SetClauses are empty")
 	}
+	if b.OffsetValid {
+		return "", nil, errors.NewNotValidf("[dbr] Update.ToSQL: OFFSET is not supported")
+	}
 
-	var buf = bufferpool.Get()
-	defer bufferpool.Put(buf)
+	var bb = bufferpool.Get()
+	defer bufferpool.Put(bb)
+	buf := newDialectWriter(bb, b.Dialect)
 
 	var args = make([]interface{}, 0, len(b.SetClauses))
 
 	buf.WriteString("UPDATE ")
-	buf.WriteString(b.Table.QuoteAs())
+	if err := b.Table.writeQuoteAs(buf); err != nil {
+		return "", nil, errors.Wrap(err, "[dbr] Update.ToSQL")
+	}
 	buf.WriteString(" SET ")
 
 	// Build SET clause SQL with placeholders and add values to args
@@ -218,13 +282,15 @@ func (b *Update) ToSQL() (string, []interface{}, error) {
 		if i > 0 {
 			buf.WriteString(", ")
 		}
-		Quoter.writeQuotedColumn(c.column, buf)
+		if err := Quoter.writeQuotedColumn(c.column, buf); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] Update.ToSQL")
+		}
+		buf.WriteString(" = ")
 		if e, ok := c.value.(*expr); ok {
-			buf.WriteString(" = ")
-			buf.WriteString(e.SQL)
+			writeRawSQL(buf, e.SQL)
 			args = append(args, e.Values...)
 		} else {
-			buf.WriteString(" = ?")
+			writePlaceholder(buf)
 			args = append(args, c.value)
 		}
 	}
@@ -232,29 +298,13 @@ func (b *Update) ToSQL() (string, []interface{}, error) {
 	// Write WHERE clause if we have any fragments
 	if len(b.WhereFragments) > 0 {
 		buf.WriteString(" WHERE ")
-		writeWhereFragmentsToSQL(b.WhereFragments, buf, &args)
-	}
-
-	// Ordering and limiting
-	if len(b.OrderBys) > 0 {
-		buf.WriteString(" ORDER BY ")
-		for i, s := range b.OrderBys {
-			if i > 0 {
-				buf.WriteString(", ")
-			}
-			buf.WriteString(s)
+		if err := writeWhereFragmentsToSQL(b.WhereFragments, buf, &args); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] Update.ToSQL.Where")
 		}
 	}
 
-	if b.LimitValid {
-		buf.WriteString(" LIMIT ")
-		buf.WriteString(strconv.FormatUint(b.LimitCount, 10))
-	}
-
-	if b.OffsetValid {
-		buf.WriteString(" OFFSET ")
-		buf.WriteString(strconv.FormatUint(b.OffsetCount, 10))
-	}
+	// Ordering and limiting
+	writeOrderLimitToSQL(buf, b.OrderBys, b.LimitValid, b.LimitCount, false, 0)
 
 	return buf.String(), args, nil
 }
@@ -262,12 +312,30 @@ func (b *Update) ToSQL() (string, []interface{}, error) {
 // Exec executes the statement represented by the Update object. It returns the
 // raw database/sql Result and an error if there was one.
 func (b *Update) Exec() (sql.Result, error) {
+	return b.ExecContext(nil)
+}
+
+// ExecContext same as Exec but respects the context. All listeners gets called
+// before the context aware function of the database gets invoked. A nil
+// context falls back to the non-context aware Exec function of the database.
+// The OnAfterExec listeners get called after the execution.
+func (b *Update) ExecContext(ctx context.Context) (sql.Result, error) {
+	start := time.Now()
+	res, err := b.exec(ctx)
+	b.Stats = makeExecStats(start, b.lastSQL, b.lastArgs, res, err)
+	if lErr := b.Listeners.dispatch(OnAfterExec, b); lErr != nil && err == nil {
+		err = errors.Wrap(lErr, "[dbr] Update.Exec.Listeners.dispatch")
+	}
+	return res, err
+}
+
+func (b *Update) exec(ctx context.Context) (sql.Result, error) {
 	rawSQL, args, err := b.ToSQL()
 	if err != nil {
 		return nil, errors.Wrap(err, "[dbr] Update.Exec.ToSQL")
 	}
 
-	fullSQL, err := Preprocess(rawSQL, args)
+	fullSQL, args, err := preprocessDialect(b.Dialect, rawSQL, args)
 	if err != nil {
 		return nil, errors.Wrap(err, "[dbr] Update.Exec.Preprocess")
 	}
@@ -276,7 +344,7 @@ func (b *Update) Exec() (sql.Result, error) {
 		defer log.WhenDone(b.Log).Info("dbr.Update.Exec.Timing", log.String("sql", fullSQL))
 	}
 
-	result, err := b.DB.Exec(fullSQL)
+	result, err := execContext(ctx, b.DB.Execer, fullSQL, args...)
 	if err != nil {
 		return result, errors.Wrap(err, "[dbr] Update.Exec.Exec")
 	}
@@ -287,6 +355,12 @@ func (b *Update) Exec() (sql.Result, error) {
 // Prepare creates a new prepared statement represented by the Update object. It
 // returns the raw database/sql Stmt and an error if there was one.
 func (b *Update) Prepare() (*sql.Stmt, error) {
+	return b.PrepareContext(nil)
+}
+
+// PrepareContext same as Prepare but bound to the context. A nil context falls
+// back to the non-context aware Prepare function of the database.
+func (b *Update) PrepareContext(ctx context.Context) (*sql.Stmt, error) {
 	rawSQL, _, err := b.ToSQL() // TODO create a ToSQL version without any arguments
 	if err != nil {
 		return nil, errors.Wrap(err, "[dbr] Update.Prepare.ToSQL")
@@ -296,6 +370,6 @@ func (b *Update) Prepare() (*sql.Stmt, error) {
 		defer log.WhenDone(b.Log).Info("dbr.Update.Prepare.Timing", log.String("sql", rawSQL))
 	}
 
-	stmt, err := b.DB.Prepare(rawSQL)
+	stmt, err := prepareContext(ctx, b.DB.Preparer, rawSQL)
 	return stmt, errors.Wrap(err, "[dbr] Update.Prepare.Prepare")
 }
@@ -73,12 +73,32 @@ func TestUpdateSetExprToSQL(t *testing.T) {
 	assert.Equal(t, args, []interface{}{1, 2, 9})
 }
 
+func TestUpdate_SetExpr(t *testing.T) {
+	s := createFakeSession()
+
+	sql, args, err := s.Update("catalog_inventory").
+		Set("is_in_stock", true).
+		SetExpr("qty", "qty - ?", 3).
+		Set("updated_by", "gopher").
+		SetExpr("version", "version + 1").
+		Where(ConditionRaw("product_id = ?", 42)).
+		ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "UPDATE `catalog_inventory` SET `is_in_stock` = ?, `qty` = qty - ?, `updated_by` = ?, `version` = version + 1 WHERE (product_id = ?)", sql)
+	assert.Exactly(t, []interface{}{true, 3, "gopher", 42}, args)
+}
+
 func TestUpdateTenStaringFromTwentyToSQL(t *testing.T) {
 	s := createFakeSession()
 
 	sql, args, err := s.Update("a").Set("b", 1).Limit(10).Offset(20).ToSQL()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+	assert.Empty(t, sql)
+	assert.Nil(t, args)
+
+	sql, args, err = s.Update("a").Set("b", 1).Limit(10).ToSQL()
 	assert.NoError(t, err)
-	assert.Equal(t, sql, "UPDATE `a` SET `b` = ? LIMIT 10 OFFSET 20")
+	assert.Equal(t, sql, "UPDATE `a` SET `b` = ? LIMIT 10")
 	assert.Equal(t, args, []interface{}{1})
 }
 
@@ -261,3 +281,61 @@ func TestUpdate_Events(t *testing.T) {
 	})
 
 }
+
+func TestUpdate_ConditionNamed(t *testing.T) {
+	s := createFakeSession()
+
+	sql, args, err := s.Update("tableA").Set("c", 1).Where(ConditionNamed("a = :id OR b = :id", map[string]interface{}{"id": 5})).ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "UPDATE `tableA` SET `c` = ? WHERE (a = ? OR b = ?)", sql)
+	assert.Exactly(t, []interface{}{1, 5, 5}, args)
+
+	sql, args, err = s.Update("tableA").Set("c", 1).Where(ConditionNamed("a = :idx", map[string]interface{}{"id": 5})).ToSQL()
+	assert.Empty(t, sql)
+	assert.Nil(t, args)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+func TestUpdate_WhereOr(t *testing.T) {
+	s := createFakeSession()
+
+	sql, args, err := s.Update("tableA").Set("c", 1).WhereOr(ConditionRaw("a = ?", 2), ConditionRaw("b = ?", 3)).Where(Eq{"d": 4}).ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "UPDATE `tableA` SET `c` = ? WHERE ((a = ?) OR (b = ?)) AND (`d` = ?)", sql)
+	assert.Exactly(t, []interface{}{1, 2, 3, 4}, args)
+
+	sql, _, err = s.Update("tableA").Set("c", 1).WhereOr().ToSQL()
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "UPDATE `tableA` SET `c` = ?", sql)
+}
+
+func TestUpdate_OrderLimit(t *testing.T) {
+	s := createFakeSession()
+
+	t.Run("batched update", func(t *testing.T) {
+		sql, args, err := s.Update("catalog_product_entity").
+			Set("sku", "x").
+			SetExpr("updated_at", "DATE_ADD(updated_at, INTERVAL ? DAY)", 2).
+			Where(ConditionRaw("type_id = ?", "simple"), Eq{"has_options": 0}).
+			OrderDir("entity_id", true).
+			OrderDir("updated_at", false).
+			Limit(500).
+			ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t,
+			"UPDATE `catalog_product_entity` SET `sku` = ?, `updated_at` = DATE_ADD(updated_at, INTERVAL ? DAY) WHERE (type_id = ?) AND (`has_options` = ?) ORDER BY entity_id ASC, updated_at DESC LIMIT 500",
+			sql)
+		assert.Exactly(t, []interface{}{"x", 2, "simple", 0}, args)
+	})
+
+	t.Run("same rendering as delete", func(t *testing.T) {
+		up := s.Update("a").Set("b", 1).Where(Eq{"c": 2}).OrderBy("d").Limit(3)
+		del := s.DeleteFrom("a").Where(Eq{"c": 2}).OrderBy("d").Limit(3)
+		upSQL, _, err := up.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		delSQL, _, err := del.ToSQL()
+		assert.NoError(t, err, "%+v", err)
+		assert.Exactly(t, "UPDATE `a` SET `b` = ? WHERE (`c` = ?) ORDER BY d LIMIT 3", upSQL)
+		assert.Exactly(t, "DELETE FROM `a` WHERE (`c` = ?) ORDER BY d LIMIT 3", delSQL)
+	})
+}
@@ -7,6 +7,28 @@ import (
 	"github.com/corestoreio/errors"
 )
 
+// maxIdentifierLength see http://dev.mysql.com/doc/refman/5.7/en/identifiers.html
+const maxIdentifierLength = 64
+
+// isValidIdentifier checks the permissible syntax for identifiers with the same
+// rules as csdb.IsValidIdentifier: ASCII [0-9,a-z,A-Z$_] and a maximum length
+// of 63 characters. Returns errors.NotValid.
+func isValidIdentifier(name string) error {
+	if len(name) >= maxIdentifierLength || name == "" {
+		return errors.NewNotValidf("[dbr] Incorrect identifier. Too long or empty: %q", name)
+	}
+	for _, r := range name {
+		switch {
+		case '0' <= r && r <= '9':
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		case r == '$', r == '_':
+		default:
+			return errors.NewNotValidf("[dbr] Invalid character %q in name %q", string(r), name)
+		}
+	}
+	return nil
+}
+
 // argsValuer checks if an argument implements driver.Valuer interface. If so
 // uses the Value() function to get the correct value.
 func argsValuer(args *[]interface{}) error {
@@ -52,3 +74,27 @@ func (sc stmtChecker) IsDelete(sql string) bool {
 func (sc stmtChecker) IsInsert(sql string) bool {
 	return sc.startContain(sql, "insert", " ")
 }
+
+// limitMax gets written as LIMIT if only an OFFSET has been set because MySQL
+// does not support an OFFSET without a LIMIT.
+const limitMax = "18446744073709551615"
+
+// writeOrderLimitToSQL writes the ORDER BY clause of a Select, Update or
+// Delete statement and the LIMIT and OFFSET clauses of the dialect of w. An
+// OFFSET of zero without a LIMIT gets omitted. Update and Delete must reject an
+// OFFSET before because MySQL does not support it for them.
+func writeOrderLimitToSQL(w QueryWriter, orderBys []string, limitValid bool, limit uint64, offsetValid bool, offset uint64) {
+	if !limitValid && offset == 0 {
+		offsetValid = false
+	}
+	if len(orderBys) > 0 {
+		_, _ = w.WriteString(" ORDER BY ")
+		for i, s := range orderBys {
+			if i > 0 {
+				_, _ = w.WriteString(", ")
+			}
+			writeRawSQL(w, s)
+		}
+	}
+	dialectOf(w).WriteLimitOffset(w, limitValid, limit, offsetValid, offset)
+}
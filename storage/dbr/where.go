@@ -1,8 +1,11 @@
 package dbr
 
 import (
+	"database/sql/driver"
 	"reflect"
+	"strings"
 
+	"github.com/corestoreio/csfw/util/bufferpool"
 	"github.com/corestoreio/errors"
 )
 
@@ -21,6 +24,20 @@ func (eq Eq) newWhereFragment() (*whereFragment, error) {
 	}, nil
 }
 
+// NullEq is a map Expression -> value pairs which must be matched in a query
+// by using the NULL-safe equal operator <=>. A nil value compares equal to
+// NULL, e.g.: `col` <=> ? with the argument nil. Slices with more than one
+// value are written as IN ?. Joined as AND statements to the WHERE clause.
+// Implements ConditionArg interface.
+type NullEq map[string]interface{}
+
+func (eq NullEq) newWhereFragment() (*whereFragment, error) {
+	return &whereFragment{
+		EqualityMap: eq,
+		NullSafe:    true,
+	}, nil
+}
+
 // ConditionIsNull checks if expression is null.
 type ConditionIsNull string
 
@@ -39,15 +56,140 @@ func (n ConditionNotNull) newWhereFragment() (*whereFragment, error) {
 	}, nil
 }
 
+// ConditionIn checks if the column matches one of the values and writes
+// `column` IN (?,?,...) with one placeholder per value. A single slice value
+// gets flattened into the list of values. Without any value the condition
+// never matches and writes 1=0.
+//
+//	ConditionIn("entity_id", 1, 2, 3)
+//	ConditionIn("entity_id", []int64{1, 2, 3})
+func ConditionIn(column string, vals ...interface{}) ConditionArg {
+	return conditionIn(column, " IN ", "1=0", vals)
+}
+
+// ConditionNotIn checks if the column matches none of the values and writes
+// `column` NOT IN (?,?,...) with one placeholder per value. A single slice
+// value gets flattened into the list of values. Without any value the
+// condition always matches and writes 1=1.
+func ConditionNotIn(column string, vals ...interface{}) ConditionArg {
+	return conditionIn(column, " NOT IN ", "1=1", vals)
+}
+
+func conditionIn(column, operator, empty string, vals []interface{}) ConditionArg {
+	return conditionArgFunc(func() (*whereFragment, error) {
+		args := append([]interface{}(nil), vals...)
+		if len(vals) == 1 {
+			if rv := reflect.ValueOf(vals[0]); isExpandableSlice(rv) {
+				args = make([]interface{}, rv.Len())
+				for i := range args {
+					args[i] = rv.Index(i).Interface()
+				}
+			}
+		}
+		if len(args) == 0 {
+			return &whereFragment{
+				Condition: empty,
+			}, nil
+		}
+
+		if err := argsValuer(&args); err != nil {
+			return nil, errors.Wrapf(err, "[dbr] In: %q; Values %v", column, vals)
+		}
+
+		buf := bufferpool.Get()
+		defer bufferpool.Put(buf)
+		if err := Quoter.writeQuotedColumn(column, buf); err != nil {
+			return nil, errors.Wrapf(err, "[dbr] In: %q", column)
+		}
+		_, _ = buf.WriteString(operator)
+		_ = buf.WriteByte('(')
+		for i := range args {
+			if i > 0 {
+				_ = buf.WriteByte(',')
+			}
+			_ = buf.WriteByte('?')
+		}
+		_ = buf.WriteByte(')')
+		return &whereFragment{
+			Condition: buf.String(),
+			Values:    args,
+		}, nil
+	})
+}
+
+// ConditionOr groups the conditions with OR into one condition. Each
+// condition gets enclosed in parentheses, so groups can be nested.
+//
+//	ConditionOr(ConditionRaw("a = ?", 1), Eq{"b": 2}, ConditionOr(...))
+//
+// generates ((a = ?) OR (`b` = ?) OR (...)) with the arguments 1, 2, ... An
+// empty group, also a group containing only empty groups, gets skipped.
+func ConditionOr(args ...ConditionArg) ConditionArg {
+	return conditionArgFunc(func() (*whereFragment, error) {
+		or := make(WhereFragments, 0, len(args))
+		for _, arg := range args {
+			wf, err := arg.newWhereFragment()
+			if err != nil {
+				return nil, errors.Wrap(err, "[dbr] Or")
+			}
+			if !wf.isEmptyOr() {
+				or = append(or, wf)
+			}
+		}
+		return &whereFragment{
+			Or: or,
+		}, nil
+	})
+}
+
 type whereFragment struct {
 	Condition   string
 	Values      []interface{}
 	EqualityMap map[string]interface{}
+	// NamedArgs if set, Condition contains :name placeholders which get
+	// replaced by positional placeholders when writing the SQL.
+	NamedArgs map[string]interface{}
+	// NullSafe uses for the EqualityMap the NULL-safe equal operator <=>.
+	NullSafe bool
+	// Or contains the fragments which get joined with OR into one
+	// condition.
+	Or WhereFragments
 }
 
 // WhereFragments provides a list where clauses
 type WhereFragments []*whereFragment
 
+// clone returns a deep copy of the fragments. Values of the maps and slices
+// get copied shallow.
+func (wf WhereFragments) clone() WhereFragments {
+	if wf == nil {
+		return nil
+	}
+	c := make(WhereFragments, len(wf))
+	for i, f := range wf {
+		cf := *f
+		if f.Values != nil {
+			cf.Values = append([]interface{}(nil), f.Values...)
+		}
+		cf.EqualityMap = cloneArgsMap(f.EqualityMap)
+		cf.NamedArgs = cloneArgsMap(f.NamedArgs)
+		cf.Or = f.Or.clone()
+		c[i] = &cf
+	}
+	return c
+}
+
+func cloneArgsMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
 // ConditionArg used as argument in Where()
 type ConditionArg interface {
 	newWhereFragment() (*whereFragment, error)
@@ -73,88 +215,385 @@ func ConditionRaw(raw string, values ...interface{}) ConditionArg {
 	})
 }
 
+// ConditionRawExpand same as ConditionRaw but expands a slice value whose
+// placeholder follows the IN operator into one placeholder per element and
+// flattens the values. Like ConditionIn and ConditionNotIn an empty slice
+// replaces the whole predicate with 1=0 for IN and 1=1 for NOT IN. Slices at
+// other placeholders and byte slices are left untouched. If the left operand
+// of the operator cannot be located, for example a placeholder or a quoted
+// string, an empty slice writes (NULL) for IN and causes a NotValid error for
+// NOT IN because `x NOT IN (NULL)` would never match a row.
+//
+//	ConditionRawExpand("a IN ? AND b = ?", []int{1, 2, 3}, 4)
+//
+// generates `(a IN (?,?,?) AND b = ?)` with the arguments 1, 2, 3, 4.
+func ConditionRawExpand(raw string, values ...interface{}) ConditionArg {
+	return conditionArgFunc(func() (*whereFragment, error) {
+		cond, args, err := expandInPlaceholders(raw, values)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[dbr] RawExpand: %q; Values %v", raw, values)
+		}
+		if err := argsValuer(&args); err != nil {
+			return nil, errors.Wrapf(err, "[dbr] RawExpand: %q; Values %v", raw, values)
+		}
+		return &whereFragment{
+			Condition: cond,
+			Values:    args,
+		}, nil
+	})
+}
+
+// expandInPlaceholders replaces each question mark after an IN operator
+// whose value is a slice with a list of question marks, one for each slice
+// element. Placeholders within quoted strings are ignored.
+func expandInPlaceholders(raw string, values []interface{}) (string, []interface{}, error) {
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+
+	args := make([]interface{}, 0, len(values))
+	var quote byte
+	pos := 0   // position of the current value
+	start := 0 // start of the not yet written part of raw
+	for i := 0; i < len(raw) && pos < len(values); i++ {
+		c := raw[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			v := values[pos]
+			pos++
+			rv := reflect.ValueOf(v)
+			if !isExpandableSlice(rv) || !endsWithIn(raw[:i]) {
+				args = append(args, v)
+				continue
+			}
+			l := rv.Len()
+			if l == 0 {
+				p, isNot := inPredicateStart(raw[:i])
+				if p >= start {
+					_, _ = buf.WriteString(raw[start:p])
+					if isNot {
+						_, _ = buf.WriteString("1=1")
+					} else {
+						_, _ = buf.WriteString("1=0")
+					}
+					start = i + 1
+					continue
+				}
+				if isNot {
+					return "", nil, errors.NewNotValidf("[dbr] Cannot find the left operand of the NOT IN operator for the empty slice at position %d", i)
+				}
+			}
+			_, _ = buf.WriteString(raw[start:i])
+			start = i + 1
+			if l == 0 {
+				_, _ = buf.WriteString("(NULL)")
+				continue
+			}
+			_ = buf.WriteByte('(')
+			for j := 0; j < l; j++ {
+				if j > 0 {
+					_ = buf.WriteByte(',')
+				}
+				_ = buf.WriteByte('?')
+				args = append(args, rv.Index(j).Interface())
+			}
+			_ = buf.WriteByte(')')
+		}
+	}
+	_, _ = buf.WriteString(raw[start:])
+	args = append(args, values[pos:]...)
+	return buf.String(), args, nil
+}
+
+// isExpandableSlice reports true for slices and arrays except byte slices.
+func isExpandableSlice(rv reflect.Value) bool {
+	k := rv.Kind()
+	return (k == reflect.Slice || k == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8
+}
+
+// endsWithIn reports true if s ends with the keyword IN, ignoring trailing
+// white spaces.
+func endsWithIn(s string) bool {
+	s = strings.TrimRight(s, " \t\r\n")
+	l := len(s)
+	if l < 2 || !strings.EqualFold(s[l-2:], "IN") {
+		return false
+	}
+	return l == 2 || !isNamePart(s[l-3])
+}
+
+// inPredicateStart returns the start of the left operand of the [NOT] IN
+// operator at the end of s and reports whether the operator is NOT IN. The
+// operand can be a name, a quoted or qualified name or a parenthesized
+// expression optionally preceded by a function name. Returns -1 if no operand
+// can be found.
+func inPredicateStart(s string) (int, bool) {
+	s = strings.TrimRight(s, " \t\r\n")
+	k := len(strings.TrimRight(s[:len(s)-2], " \t\r\n"))
+	isNot := k >= 3 && strings.EqualFold(s[k-3:k], "NOT") && (k == 3 || !isNamePart(s[k-4]))
+	if isNot {
+		k = len(strings.TrimRight(s[:k-3], " \t\r\n"))
+	}
+	end := k
+	if k > 0 && s[k-1] == ')' {
+		depth := 0
+		for k > 0 {
+			k--
+			if s[k] == ')' {
+				depth++
+			} else if s[k] == '(' {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+		if depth != 0 {
+			return -1, isNot
+		}
+	}
+	for k > 0 {
+		switch c := s[k-1]; {
+		case c == '`':
+			q := strings.LastIndexByte(s[:k-1], '`')
+			if q < 0 {
+				return -1, isNot
+			}
+			k = q
+		case c == '.' || isNamePart(c):
+			k--
+		default:
+			if k == end {
+				return -1, isNot
+			}
+			return k, isNot
+		}
+	}
+	if k == end {
+		return -1, isNot
+	}
+	return k, isNot
+}
+
+// ConditionNamed adds a condition with :name style placeholders. Each
+// placeholder gets replaced by a positional place holder and its value from
+// the args map gets appended to the arguments, in the order of occurrence.
+// The same name can be used multiple times. A name missing in args causes a
+// NotValid error in ToSQL. Placeholders within quoted strings are ignored.
+//
+//	ConditionNamed("a = :id OR b = :id", map[string]interface{}{"id": 5})
+//
+// generates `(a = ? OR b = ?)` with the arguments 5, 5.
+func ConditionNamed(raw string, args map[string]interface{}) ConditionArg {
+	return conditionArgFunc(func() (*whereFragment, error) {
+		named := make(map[string]interface{}, len(args))
+		for k, v := range args {
+			if dbVal, ok := v.(driver.Valuer); ok {
+				val, err := dbVal.Value()
+				if err != nil {
+					return nil, errors.Wrapf(err, "[dbr] Named: %q; Name %q", raw, k)
+				}
+				v = val
+			}
+			named[k] = v
+		}
+		return &whereFragment{
+			Condition: raw,
+			NamedArgs: named,
+		}, nil
+	})
+}
+
+// writeNamedCondition writes the condition to w and replaces all :name
+// placeholders with the placeholder of the dialect. The values get appended
+// to args.
+func writeNamedCondition(cond string, named map[string]interface{}, w QueryWriter, args *[]interface{}) error {
+	var quote byte
+	start := 0 // start of the not yet written part of cond
+	for i := 0; i < len(cond); i++ {
+		c := cond[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ':' && i+1 < len(cond) && isNameStart(cond[i+1]) && (i == 0 || cond[i-1] != ':'):
+			j := i + 1
+			for j < len(cond) && isNamePart(cond[j]) {
+				j++
+			}
+			name := cond[i+1 : j]
+			v, ok := named[name]
+			if !ok {
+				return errors.NewNotValidf("[dbr] Named argument %q not found in condition %q", name, cond)
+			}
+			writeRawSQL(w, cond[start:i])
+			writePlaceholder(w)
+			*args = append(*args, v)
+			start = j
+			i = j - 1
+		}
+	}
+	writeRawSQL(w, cond[start:])
+	return nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
+
 func newWhereFragments(wargs ...ConditionArg) WhereFragments {
-	ret := make(WhereFragments, len(wargs))
-	for i, warg := range wargs {
+	ret := make(WhereFragments, 0, len(wargs))
+	for _, warg := range wargs {
 		wf, err := warg.newWhereFragment()
 		if err != nil {
 			panic(err) // damn it ... TODO remove panic
 		}
-		ret[i] = wf
+		if !wf.isEmptyOr() {
+			ret = append(ret, wf)
+		}
 	}
 	return ret
 }
 
+// isEmptyOr reports true if the fragment has been created by ConditionOr
+// without any conditions. It would write nothing.
+func (wf *whereFragment) isEmptyOr() bool {
+	return wf.Or != nil && len(wf.Or) == 0
+}
+
 // Invariant: only called when len(fragments) > 0
-func writeWhereFragmentsToSQL(fragments WhereFragments, sql QueryWriter, args *[]interface{}) {
+func writeWhereFragmentsToSQL(fragments WhereFragments, sql QueryWriter, args *[]interface{}) error {
 	anyConditions := false
 	for _, f := range fragments {
-		if f.Condition != "" {
+		if len(f.Or) > 0 {
+			if anyConditions {
+				_, _ = sql.WriteString(" AND (")
+			} else {
+				_, _ = sql.WriteRune('(')
+				anyConditions = true
+			}
+			for i, of := range f.Or {
+				if i > 0 {
+					_, _ = sql.WriteString(" OR ")
+				}
+				if err := writeWhereFragmentsToSQL(WhereFragments{of}, sql, args); err != nil {
+					return errors.Wrap(err, "[dbr] writeWhereFragmentsToSQL.Or")
+				}
+			}
+			_, _ = sql.WriteRune(')')
+		} else if f.Condition != "" {
 			if anyConditions {
 				_, _ = sql.WriteString(" AND (")
 			} else {
 				_, _ = sql.WriteRune('(')
 				anyConditions = true
 			}
-			_, _ = sql.WriteString(f.Condition)
+			if f.NamedArgs != nil {
+				if err := writeNamedCondition(f.Condition, f.NamedArgs, sql, args); err != nil {
+					return errors.Wrap(err, "[dbr] writeWhereFragmentsToSQL")
+				}
+			} else {
+				writeRawSQL(sql, f.Condition)
+			}
 			_, _ = sql.WriteRune(')')
 			if len(f.Values) > 0 {
 				*args = append(*args, f.Values...)
 			}
 		} else if f.EqualityMap != nil {
-			anyConditions = writeEqualityMapToSQL(f.EqualityMap, sql, args, anyConditions)
+			var err error
+			if anyConditions, err = writeEqualityMapToSQL(f.EqualityMap, sql, args, anyConditions, f.NullSafe); err != nil {
+				return errors.Wrap(err, "[dbr] writeWhereFragmentsToSQL")
+			}
 		}
 	}
+	return nil
 }
 
-func writeEqualityMapToSQL(eq map[string]interface{}, w QueryWriter, args *[]interface{}, anyConditions bool) bool {
+func writeEqualityMapToSQL(eq map[string]interface{}, w QueryWriter, args *[]interface{}, anyConditions, nullSafe bool) (bool, error) {
+	eqPred := " = ?"
+	if nullSafe {
+		eqPred = " <=> ?"
+	}
+	var err error
 	for k, v := range eq {
+		if v == nil && nullSafe {
+			if anyConditions, err = writeWhereCondition(w, k, eqPred, anyConditions); err != nil {
+				return anyConditions, err
+			}
+			*args = append(*args, nil)
+			continue
+		}
 		if v == nil {
-			anyConditions = writeWhereCondition(w, k, " IS NULL", anyConditions)
+			if anyConditions, err = writeWhereCondition(w, k, " IS NULL", anyConditions); err != nil {
+				return anyConditions, err
+			}
 			continue
 		}
 
 		vVal := reflect.ValueOf(v)
 
+		pred := eqPred
 		if vVal.Kind() == reflect.Array || vVal.Kind() == reflect.Slice {
 			vValLen := vVal.Len()
 			if vValLen == 0 {
 				if vVal.IsNil() {
-					anyConditions = writeWhereCondition(w, k, " IS NULL", anyConditions)
+					pred = " IS NULL"
 				} else {
 					if anyConditions {
 						_, _ = w.WriteString(" AND (1=0)")
 					} else {
 						_, _ = w.WriteString("(1=0)")
 					}
+					continue
 				}
 			} else if vValLen == 1 {
-				anyConditions = writeWhereCondition(w, k, " = ?", anyConditions)
 				*args = append(*args, vVal.Index(0).Interface())
-			} else {
-				anyConditions = writeWhereCondition(w, k, " IN ?", anyConditions)
+			} else if isDialectMySQL(dialectOf(w)) {
+				// the slice gets expanded by the interpolation
+				pred = " IN ?"
 				*args = append(*args, v)
+			} else {
+				// the database driver binds only scalar arguments
+				pred = " IN (?" + strings.Repeat(",?", vValLen-1) + ")"
+				for i := 0; i < vValLen; i++ {
+					*args = append(*args, vVal.Index(i).Interface())
+				}
 			}
 		} else {
-			anyConditions = writeWhereCondition(w, k, " = ?", anyConditions)
 			*args = append(*args, v)
 		}
-
+		if anyConditions, err = writeWhereCondition(w, k, pred, anyConditions); err != nil {
+			return anyConditions, err
+		}
 	}
 
-	return anyConditions
+	return anyConditions, nil
 }
 
-func writeWhereCondition(w QueryWriter, k string, pred string, anyConditions bool) bool {
+func writeWhereCondition(w QueryWriter, k string, pred string, anyConditions bool) (bool, error) {
 	if anyConditions {
 		_, _ = w.WriteString(" AND (")
 	} else {
 		_, _ = w.WriteRune('(')
 		anyConditions = true
 	}
-	Quoter.writeQuotedColumn(k, w)
-	_, _ = w.WriteString(pred)
+	if err := Quoter.writeQuotedColumn(k, w); err != nil {
+		return anyConditions, errors.Wrap(err, "[dbr] writeWhereCondition")
+	}
+	writeRawSQL(w, pred)
 	_, _ = w.WriteRune(')')
 
-	return anyConditions
+	return anyConditions, nil
 }
@@ -38,6 +38,10 @@ const PermDefault Perm = 1 << Default
 // config.Scoped implementation.
 const PermStoreReverse Perm = 1 << Store
 
+// PermGroupReverse convenient helper to enforce hierarchy levels. Only used in
+// config.Scoped implementation.
+const PermGroupReverse Perm = 1<<Store | 1<<Group
+
 // PermWebsiteReverse convenient helper to enforce hierarchy levels. Only used in
 // config.Scoped implementation.
 const PermWebsiteReverse Perm = 1<<Store | 1<<Website
@@ -38,6 +38,9 @@ func TestPermTop(t *testing.T) {
 	assert.Exactly(t, scope.Website, scope.Perm(44).Top())
 	assert.Exactly(t, scope.Store, scope.PermWebsiteReverse.Top())
 	assert.Exactly(t, scope.Store, scope.PermStoreReverse.Top())
+	assert.Exactly(t, scope.Store, scope.PermGroupReverse.Top())
+	assert.True(t, scope.PermGroupReverse.Has(scope.Group))
+	assert.False(t, scope.PermGroupReverse.Has(scope.Website))
 }
 
 func TestPermMarshalJSONAll(t *testing.T) {
@@ -78,7 +78,7 @@ func ToSlice(i interface{}) []interface{} {
 }
 
 func ToStringSlice(i interface{}) []string {
-	v, _ := ToStringSliceE(i)
+	v, _ := ToStringSliceE(i, "")
 	return v
 }
 
@@ -252,6 +252,40 @@ func TestSlices(t *testing.T) {
 	assert.Equal(t, []int{2, 3}, ToIntSlice([2]string{"2", "3"}))
 }
 
+func TestToStringSliceE(t *testing.T) {
+	tests := []struct {
+		have       interface{}
+		sep        string
+		want       []string
+		wantErrBhf errors.BehaviourFunc
+	}{
+		{[]string{"a", " b "}, ",", []string{"a", " b "}, nil},
+		{"a, b,,c ,", ",", []string{"a", "b", "c"}, nil},
+		{"a|b", "|", []string{"a", "b"}, nil},
+		{" a  b\tc ", "", []string{"a", "b", "c"}, nil},
+		{"", ",", []string{}, nil},
+		{[]byte("x;y"), ";", []string{"x", "y"}, nil},
+		{[]interface{}{1, "b", 2.5}, ",", []string{"1", "b", "2.5"}, nil},
+		{[]interface{}{1, struct{}{}}, ",", nil, errors.IsNotValid},
+		{struct{}{}, ",", nil, errors.IsNotValid},
+		{nil, ",", nil, errors.IsNotValid},
+	}
+	for i, test := range tests {
+		have, err := ToStringSliceE(test.have, test.sep)
+		if test.wantErrBhf != nil {
+			assert.True(t, test.wantErrBhf(err), "Index %d => %+v", i, err)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, have, "Index %d", i)
+	}
+
+	_, err := ToDurationE([]byte("30 seconds"))
+	assert.Contains(t, err.Error(), `[]byte{0x33, 0x30,`)
+	_, err = ToDurationE(true)
+	assert.Contains(t, err.Error(), "Unable to cast true to Duration")
+}
+
 func TestToBool2(t *testing.T) {
 
 	assert.Equal(t, ToBool(0), false)
@@ -392,6 +426,44 @@ func TestToDuration(t *testing.T) {
 	assert.Equal(t, dbf, b)
 }
 
+func TestToDurationE(t *testing.T) {
+	dur := 90 * time.Second
+	tests := []struct {
+		have       interface{}
+		want       time.Duration
+		wantErrBhf errors.BehaviourFunc
+	}{
+		{time.Minute, time.Minute, nil},
+		{&dur, dur, nil},
+		// numbers are nanoseconds, contrary to a string without a unit
+		{int(5), 5 * time.Nanosecond, nil},
+		{int32(6), 6 * time.Nanosecond, nil},
+		{int64(30), 30 * time.Nanosecond, nil},
+		{int64(time.Second), time.Second, nil},
+		{float64(time.Millisecond), time.Millisecond, nil},
+		{"30s", 30 * time.Second, nil},
+		{"2h", 2 * time.Hour, nil},
+		{" 1h30m ", 90 * time.Minute, nil},
+		{"30", 30 * time.Second, nil},
+		{"-5", -5 * time.Second, nil},
+		{[]byte("45m"), 45 * time.Minute, nil},
+		{[]byte("12"), 12 * time.Second, nil},
+		{"30 seconds", 0, errors.IsNotValid},
+		{"", 0, errors.IsNotValid},
+		{true, 0, errors.IsNotValid},
+		{nil, 0, errors.IsNotValid},
+	}
+	for i, test := range tests {
+		have, err := ToDurationE(test.have)
+		if test.wantErrBhf != nil {
+			assert.True(t, test.wantErrBhf(err), "Index %d => %+v", i, err)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, have, "Index %d", i)
+	}
+}
+
 func getMockTime(format string) time.Time {
 	nowS := time.Now().Format(format)
 	t, err := time.ParseInLocation(format, nowS, time.Local)
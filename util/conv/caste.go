@@ -44,21 +44,40 @@ func ToTimeE(i interface{}) (time.Time, error) {
 	}
 }
 
-// ToDurationE casts an empty interface to time.Duration.
+// ToDurationE casts an empty interface to time.Duration. Supported types:
+// time.Duration, int, int32, int64, float64, string and []byte. Numbers are
+// nanoseconds like a time.Duration, to stay compatible with existing callers,
+// so ToDurationE(30) returns 30ns. A string gets parsed with
+// time.ParseDuration, e.g. "30s" or "2h". A string without a unit, e.g. "30",
+// falls back to an integer in seconds because configuration values are
+// usually written in seconds. An unparseable value returns a NotValid error.
 func ToDurationE(i interface{}) (d time.Duration, err error) {
 	i = indirect(i)
 
 	switch s := i.(type) {
 	case time.Duration:
 		return s, nil
+	case int:
+		d = time.Duration(s)
+		return
+	case int32:
+		d = time.Duration(s)
+		return
 	case int64:
 		d = time.Duration(s)
 		return
 	case float64:
 		d = time.Duration(s)
 		return
+	case []byte:
+		if d, err = stringToDuration(string(s)); err != nil {
+			err = errors.NewNotValidf("[conv] Unable to cast %#v to Duration: %s\n", i, err)
+		}
+		return
 	case string:
-		d, err = time.ParseDuration(s)
+		if d, err = stringToDuration(s); err != nil {
+			err = errors.NewNotValidf("[conv] Unable to cast %#v to Duration: %s\n", i, err)
+		}
 		return
 	default:
 		err = errors.NewNotValidf("[conv] Unable to cast %#v to Duration\n", i)
@@ -66,6 +85,20 @@ func ToDurationE(i interface{}) (d time.Duration, err error) {
 	}
 }
 
+// stringToDuration parses s with time.ParseDuration and falls back to an
+// integer in seconds. It returns the error of time.ParseDuration.
+func stringToDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	d, err := time.ParseDuration(s)
+	if err == nil {
+		return d, nil
+	}
+	if sec, aErr := strconv.ParseInt(s, 10, 64); aErr == nil {
+		return time.Duration(sec) * time.Second, nil
+	}
+	return 0, err
+}
+
 // ToBoolE casts an empty interface to a bool. If a type implements function
 //		ToBool() bool
 // this function will get called.
@@ -517,7 +550,7 @@ func ToStringMapStringSliceE(i interface{}) (map[string][]string, error) {
 			if err != nil {
 				return m, errors.NewNotValidf("[conv] Unable to cast %#v to map[string][]string. %s", i, err)
 			}
-			value, err := ToStringSliceE(val)
+			value, err := ToStringSliceE(val, "")
 			if err != nil {
 				return m, errors.NewNotValidf("[conv] Unable to cast %#v to map[string][]string. %s", i, err)
 			}
@@ -591,21 +624,33 @@ func ToSliceE(i interface{}) ([]interface{}, error) {
 	}
 }
 
-// ToStringSliceE casts an empty interface to a []string.
-func ToStringSliceE(i interface{}) ([]string, error) {
+// ToStringSliceE casts an empty interface to a []string. A []string gets
+// returned as it is. A string or []byte gets split by sep, each entry gets
+// trimmed and empty entries are dropped. An empty sep splits around white
+// space. Each element of a []interface{} gets converted with ToStringE. Any
+// other type gets converted with ToStringE into a slice with one entry.
+// Unsupported types return a NotValid error.
+func ToStringSliceE(i interface{}, sep string) ([]string, error) {
 
 	var a []string
 
 	switch v := i.(type) {
 	case []interface{}:
-		for _, u := range v {
-			a = append(a, ToString(u))
+		a = make([]string, 0, len(v))
+		for idx, u := range v {
+			str, err := ToStringE(u)
+			if err != nil {
+				return nil, errors.NewNotValidf("[conv] Unable to cast %#v at index %d to string. %s", u, idx, err)
+			}
+			a = append(a, str)
 		}
 		return a, nil
 	case []string:
 		return v, nil
 	case string:
-		return strings.Fields(v), nil
+		return splitString(v, sep), nil
+	case []byte:
+		return splitString(string(v), sep), nil
 	case interface{}:
 		str, err := ToStringE(v)
 		if err != nil {
@@ -617,6 +662,20 @@ func ToStringSliceE(i interface{}) ([]string, error) {
 	}
 }
 
+func splitString(s, sep string) []string {
+	if sep == "" {
+		return strings.Fields(s)
+	}
+	parts := strings.Split(s, sep)
+	ret := parts[:0]
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
 // ToIntSliceE casts an empty interface to a []int.
 func ToIntSliceE(i interface{}) ([]int, error) {
 
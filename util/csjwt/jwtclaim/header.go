@@ -10,6 +10,7 @@ import "github.com/corestoreio/errors"
 const (
 	HeaderAlg = "alg"
 	HeaderTyp = "typ"
+	HeaderKid = "kid"
 )
 
 // ContentTypeJWT defines the content type of a token. At the moment only JWT is
@@ -86,6 +87,8 @@ func (s *HeadSegments) Set(key, value string) (err error) {
 		s.Algorithm = value
 	case HeaderTyp:
 		s.Type = value
+	case HeaderKid:
+		s.KID = value
 	default:
 		return errors.NewNotSupportedf(errHeaderKeyNotSupported, key)
 	}
@@ -100,6 +103,8 @@ func (s *HeadSegments) Get(key string) (value string, err error) {
 		return s.Algorithm, nil
 	case HeaderTyp:
 		return s.Type, nil
+	case HeaderKid:
+		return s.KID, nil
 	}
 	return "", errors.NewNotSupportedf(errHeaderKeyNotSupported, key)
 }
@@ -63,6 +63,7 @@ func TestHeadSegmentsGetSet(t *testing.T) {
 	}{
 		{&jwtclaim.HeadSegments{}, jwtclaim.HeaderAlg, "", nil, nil},
 		{&jwtclaim.HeadSegments{}, jwtclaim.HeaderTyp, "Go", nil, nil},
+		{&jwtclaim.HeadSegments{}, jwtclaim.HeaderKid, "key-2016", nil, nil},
 		{&jwtclaim.HeadSegments{}, "ext", "Test", errors.IsNotSupported, errors.IsNotSupported},
 	}
 	for i, test := range tests {
@@ -97,6 +97,32 @@ func (k Key) Algorithm() (a string) {
 	return a
 }
 
+// SigningMethod returns the default signing method for the key. ECDSA keys
+// return ES256, ES384 or ES512 depending on the bit size of their curve. RSA
+// keys return RS256 and HMAC passwords HS256 because the bit size cannot be
+// derived from the key. Returns nil for an empty key.
+func (k Key) SigningMethod() Signer {
+	switch k.Algorithm() {
+	case HS:
+		return NewSigningMethodHS256()
+	case RS:
+		return NewSigningMethodRS256()
+	case ES:
+		pub := k.ecdsaKeyPub
+		if k.ecdsaKeyPriv != nil {
+			pub = &k.ecdsaKeyPriv.PublicKey
+		}
+		switch pub.Curve.Params().BitSize {
+		case 384:
+			return NewSigningMethodES384()
+		case 521:
+			return NewSigningMethodES512()
+		}
+		return NewSigningMethodES256()
+	}
+	return nil
+}
+
 // WithPassword uses the byte slice as the password for the HMAC-SHA signing
 // method.
 func WithPassword(password []byte) Key {
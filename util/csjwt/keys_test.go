@@ -135,3 +135,25 @@ func TestKeyWithRSAGenerator(t *testing.T) {
 	key := WithRSAGenerated()
 	assert.Exactly(t, RS, key.Algorithm())
 }
+
+func TestKey_SigningMethod(t *testing.T) {
+	tests := []struct {
+		key     Key
+		wantAlg string
+	}{
+		{WithPasswordRandom(), HS256},
+		{WithRSAPrivateKeyFromFile("test/test_rsa_np"), RS256},
+		{WithRSAPublicKeyFromFile("test/sample_key.pub"), RS256},
+		{WithECPrivateKeyFromFile("test/ec256-private.pem"), ES256},
+		{WithECPrivateKeyFromFile("test/ec384-private.pem"), ES384},
+		{WithECPrivateKeyFromFile("test/ec512-private.pem"), ES512},
+		{WithECPublicKeyFromFile("test/ec384-public.pem"), ES384},
+	}
+	for i, test := range tests {
+		if test.key.Error != nil {
+			t.Fatalf("Index %d: %+v", i, test.key.Error)
+		}
+		assert.Exactly(t, test.wantAlg, test.key.SigningMethod().Alg(), "Index %d", i)
+	}
+	assert.Nil(t, Key{}.SigningMethod())
+}
@@ -98,6 +98,90 @@ func MustConnectDB(opts ...dbr.ConnectionOption) (*dbr.Connection, magento.Versi
 	return dbc, v
 }
 
+// sharedTester is *testing.T or *testing.B.
+type sharedTester interface {
+	fataler
+	Skipf(format string, args ...interface{})
+}
+
+// sharedConn holds the connection handed out by SharedDB. It gets created
+// once per test binary and never reset.
+type sharedConn struct {
+	once    sync.Once
+	dbc     *dbr.Connection
+	version magento.Version
+	err     error
+}
+
+var sharedDB = new(sharedConn)
+
+func (sc *sharedConn) connect(dsn string, opts []dbr.ConnectionOption) {
+	cos := make([]dbr.ConnectionOption, 0, len(opts)+1)
+	cos = append(cos, dbr.WithDSN(dsn))
+	sc.dbc, sc.err = dbr.NewConnection(append(cos, opts...)...)
+	if sc.err != nil {
+		sc.err = errors.Wrap(sc.err, "[cstesting] SharedDB.NewConnection")
+		return
+	}
+	if sc.err = sc.dbc.Ping(); sc.err != nil {
+		_ = sc.dbc.Close()
+		sc.err = errors.Wrap(sc.err, "[cstesting] SharedDB.Ping")
+		return
+	}
+
+	if v := dsnVersionCache.v(dsn); v > 0 {
+		sc.version = v
+		return
+	}
+	tables, err := showTables(sc.dbc.DB)
+	if err != nil {
+		sc.err = errors.Wrap(err, "[cstesting] SharedDB.showTables")
+		return
+	}
+	sc.version = magento.DetectVersion("", tables)
+	dsnVersionCache.set(dsn, sc.version)
+}
+
+// SharedDB returns a database connection and the detected Magento version
+// shared between all tests of a package, including parallel ones. The
+// connection gets lazily created once by the first caller using the DSN found
+// in the environment variable EnvDSN. The options, e.g. for installing
+// fixtures, only apply to the first caller. The connection stays open for all
+// following tests and can be closed with CloseSharedDB in TestMain. Skips the
+// test if the DSN environment variable has not been set and fails it if the
+// connection cannot be established.
+func SharedDB(t sharedTester, opts ...dbr.ConnectionOption) (*dbr.Connection, magento.Version) {
+	dsn, err := getDSN(EnvDSN)
+	if errors.IsNotFound(err) {
+		t.Skipf("%s", err)
+	}
+
+	sharedDB.once.Do(func() {
+		sharedDB.connect(dsn, opts)
+	})
+	if sharedDB.err != nil {
+		t.Fatalf("%+v", sharedDB.err)
+	}
+	return sharedDB.dbc, sharedDB.version
+}
+
+// CloseSharedDB closes the connection created by SharedDB, if any. It must
+// only be called after all tests have finished, usually in TestMain after
+// m.Run.
+//		func TestMain(m *testing.M) {
+//			code := m.Run()
+//			if err := cstesting.CloseSharedDB(); err != nil {
+//				fmt.Fprintf(os.Stderr, "%+v", err)
+//			}
+//			os.Exit(code)
+//		}
+func CloseSharedDB() error {
+	if sharedDB.dbc == nil || sharedDB.err != nil {
+		return nil
+	}
+	return errors.Wrap(sharedDB.dbc.Close(), "[cstesting] CloseSharedDB")
+}
+
 // MockDB creates a mocked database connection. Fatals on error.
 func MockDB(t fataler) (*dbr.Connection, sqlmock.Sqlmock) {
 	db, sm, err := sqlmock.New()
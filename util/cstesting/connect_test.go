@@ -50,6 +50,62 @@ func TestMustConnectDB(t *testing.T) {
 	}
 }
 
+func TestSharedDB(t *testing.T) {
+	// t.Parallel() not possible because of the ENV vars
+
+	db, sm, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldEnv := os.Getenv(csdb.EnvDSN)
+	defer os.Setenv(csdb.EnvDSN, oldEnv)
+
+	t.Run("Skip without DSN", func(t *testing.T) {
+		os.Setenv(csdb.EnvDSN, "")
+		defer func() {
+			assert.True(t, t.Skipped(), "Test should have been skipped")
+		}()
+		cstesting.SharedDB(t)
+		t.Fatal("Expecting a skipped test")
+	})
+
+	os.Setenv(csdb.EnvDSN, "us3r:passw0rd@tcp(localhost:3306)/database4")
+
+	sm.ExpectQuery("SHOW TABLES").WillReturnRows(sqlmock.NewRows([]string{"Tables_in_Database"}).
+		FromCSVString("integration\nstore_website\nstore_group\nauthorization_role"),
+	)
+
+	dbc, version := cstesting.SharedDB(t, dbr.WithDB(db))
+	assert.NotNil(t, dbc)
+	assert.Exactly(t, magento.Version2, version)
+
+	var dbcA, dbcB *dbr.Connection
+	t.Run("A", func(t *testing.T) {
+		dbcA, version = cstesting.SharedDB(t, dbr.WithDB(db))
+		assert.Exactly(t, magento.Version2, version)
+	})
+	t.Run("B", func(t *testing.T) {
+		dbcB, version = cstesting.SharedDB(t, dbr.WithDB(db))
+		assert.Exactly(t, magento.Version2, version)
+	})
+	cstesting.EqualPointers(t, dbc, dbcA)
+	cstesting.EqualPointers(t, dbcA, dbcB)
+
+	// a sequential test after the previous ones have finished gets the same
+	// still open connection and does not query the database again.
+	dbcC, version := cstesting.SharedDB(t)
+	cstesting.EqualPointers(t, dbc, dbcC)
+	assert.Exactly(t, magento.Version2, version)
+	assert.NoError(t, dbcC.Ping())
+
+	sm.ExpectClose()
+	assert.NoError(t, cstesting.CloseSharedDB())
+	if err := sm.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
+
 func TestMustConnectDB_Mock(t *testing.T) {
 	t.Parallel()
 
@@ -14,7 +14,7 @@
 
 // Package magento contains helper functions to handle the different versions.
 //
-// Distinguish between Magento 1 and Magento 2.
+// Distinguish between Magento 1, Magento 2 up to 2.3 and Magento 2.4.
 //
 // Magento is a trademark of MAGENTO, INC.
 package magento
@@ -18,46 +18,55 @@ package magento
 // tables.
 type Version int
 
-// Version* defines the return values of Version() function.
+// Version* defines the return values of Version() function. Version2 covers
+// all Magento 2 releases and Version24 the releases starting with 2.4.
+// Version24 includes the bit of Version2, so a check for Magento 2 must be
+// written as v&Version2 != 0.
 const (
 	Version1 Version = 2 << (iota + 1)
 	Version2
-	VersionAll = Version1 | Version2
+	Version24  = Version2 | 2<<(iota+1)
+	VersionAll = Version1 | Version24
 )
 
 var v1 = [4]string{"core_store", "core_website", "core_store_group", "api_user"}
 var v2 = [4]string{"integration", "store_website", "store_group", "authorization_role"}
+var v24 = [4]string{"login_as_customer", "login_as_customer_assistance_allowed", "media_gallery_asset", "media_gallery_keyword"}
 
 // DetectVersion detects the running version by reading the list of tables. It
 // searches for the tables core_store, core_website, core_store_group and
 // api_user for Magento v1. It searches for the tables integration,
-// store_website, store_group and authorization_role for Magento v2. Prefix is
-// the prefix for each table. Returns zero if the version cannot be detected.
+// store_website, store_group and authorization_role for Magento v2. If
+// additionally the tables login_as_customer,
+// login_as_customer_assistance_allowed, media_gallery_asset and
+// media_gallery_keyword, introduced with 2.4, exist it returns Version24.
+// Prefix is the prefix for each table. Returns zero if the version cannot be
+// detected.
 func DetectVersion(prefix string, tableList []string) Version {
-	var one, two bool
-	lv1 := len(v1)
-	f1, f2 := 0, 0
+	f1, f2, f24 := 0, 0, 0
 	for _, table := range tableList {
-		for i := 0; i < lv1; i++ {
-			if table == prefix+v1[i] {
+		for _, t := range v1 {
+			if table == prefix+t {
 				f1++
 			}
-			if table == prefix+v2[i] {
+		}
+		for _, t := range v2 {
+			if table == prefix+t {
 				f2++
 			}
 		}
-
-		if f1 == lv1 {
-			one = true
-		}
-		if f2 == lv1 {
-			two = true
+		for _, t := range v24 {
+			if table == prefix+t {
+				f24++
+			}
 		}
 	}
 	switch {
-	case one:
+	case f1 == len(v1):
 		return Version1
-	case two:
+	case f2 == len(v2) && f24 == len(v24):
+		return Version24
+	case f2 == len(v2):
 		return Version2
 	default:
 		return 0